@@ -0,0 +1,150 @@
+package parquet
+
+import (
+	"io"
+
+	"github.com/parquet-go/parquet-go/encoding"
+	"github.com/parquet-go/parquet-go/format"
+)
+
+// adaptiveEncodings lists the encodings that writers select from when the
+// AdaptiveEncoding option is enabled. When multiple encodings produce pages of
+// the same size, the first one in the list is preferred.
+var adaptiveEncodings = [...]encoding.Encoding{
+	&Plain,
+	&RLEDictionary,
+	&DeltaBinaryPacked,
+	&DeltaLengthByteArray,
+	&DeltaByteArray,
+	&ByteStreamSplit,
+}
+
+// selectPageEncoding selects the encoding of the column chunk by encoding the
+// buffered page with each candidate encoding and retaining the one producing
+// the smallest output.
+//
+// When dictionary encoding is selected, the buffered values are moved to a
+// new column buffer holding indexes into the dictionary. The best of the other
+// encodings is retained as fallback if the dictionary grows too large.
+func (c *writerColumn) selectPageEncoding() error {
+	c.encodingSelected = true
+
+	page := c.columnBuffer.Page()
+	values, err := readPageValues(page)
+	if err != nil {
+		return err
+	}
+
+	var (
+		selected         encoding.Encoding
+		selectedSize     int
+		alternative      encoding.Encoding
+		alternativeSize  int
+		isDictionaryKind = c.baseType.Kind() != Boolean
+	)
+
+	for _, enc := range adaptiveEncodings {
+		var size int
+		var err error
+
+		switch {
+		case isDictionaryEncoding(enc):
+			if !isDictionaryKind {
+				continue
+			}
+			size, err = c.dictionaryEncodedSize(values)
+		case canEncode(enc, c.baseType.Kind()):
+			size, err = c.encodedSize(page.Data(), enc)
+		default:
+			continue
+		}
+		// Some logical types do not support all the encodings of their
+		// physical type, the encodings that they reject are skipped.
+		if err != nil {
+			continue
+		}
+
+		if selected == nil || size < selectedSize {
+			selected, selectedSize = enc, size
+		}
+		if !isDictionaryEncoding(enc) && (alternative == nil || size < alternativeSize) {
+			alternative, alternativeSize = enc, size
+		}
+	}
+
+	if selected == nil {
+		return nil
+	}
+	c.alternativeEncoding = alternative
+	c.setEncoding(selected)
+
+	if c.columnBuffer == nil {
+		c.columnBuffer = c.newColumnBuffer()
+		_, err = c.columnBuffer.WriteValues(values)
+	}
+	return err
+}
+
+// encodedSize returns the size of data after encoding it with enc, and after
+// compression if the writer compares compressed sizes.
+func (c *writerColumn) encodedSize(data encoding.Values, enc encoding.Encoding) (int, error) {
+	buf := c.buffers
+	var err error
+	if buf.page, err = c.baseType.Encode(buf.page[:0], data, enc); err != nil {
+		return 0, err
+	}
+	return c.compressedSize(buf.page, true)
+}
+
+// dictionaryEncodedSize returns the size of the dictionary page and data page
+// produced when values are written with dictionary encoding.
+func (c *writerColumn) dictionaryEncodedSize(values []Value) (int, error) {
+	nonNullValues := make([]Value, 0, len(values))
+	for _, v := range values {
+		if !v.IsNull() {
+			nonNullValues = append(nonNullValues, v)
+		}
+	}
+	indexes := make([]int32, len(nonNullValues))
+	dict := c.newDictionary()
+	dict.Insert(indexes, nonNullValues)
+
+	dictSize, err := c.encodedSize(dict.Page().Data(), &Plain)
+	if err != nil {
+		return 0, err
+	}
+
+	buf := c.buffers
+	if buf.page, err = RLEDictionary.EncodeInt32(buf.page[:0], indexes); err != nil {
+		return 0, err
+	}
+	// Dictionary indexes are not compressed in data pages v2, see newWriter.
+	indexSize, err := c.compressedSize(buf.page, c.dataPageType != format.DataPageV2)
+	return dictSize + indexSize, err
+}
+
+func (c *writerColumn) compressedSize(data []byte, compressed bool) (int, error) {
+	if !compressed || !c.compareCompressed || !isCompressed(c.compression) {
+		return len(data), nil
+	}
+	var err error
+	c.buffers.scratch, err = c.compression.Encode(c.buffers.scratch[:0], data)
+	return len(c.buffers.scratch), err
+}
+
+func readPageValues(page Page) ([]Value, error) {
+	values := make([]Value, page.NumValues())
+	reader := page.Values()
+	n := 0
+	for n < len(values) {
+		rn, err := reader.ReadValues(values[n:])
+		n += rn
+		if err != nil {
+			if err == io.EOF {
+				break
+			}
+			return nil, err
+		}
+	}
+	return values[:n], nil
+}
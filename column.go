@@ -354,6 +354,8 @@ func (cl *columnLoader) open(file *File, path []string) (*Column, error) {
 		c.typ = &mapType{}
 	} else if lt != nil && lt.List != nil {
 		c.typ = &listType{}
+	} else if lt != nil && lt.Variant != nil {
+		c.typ = (*variantType)(lt.Variant)
 	}
 	c.columns = make([]*Column, numChildren)
 
@@ -386,6 +388,8 @@ func schemaElementTypeOf(s *format.SchemaElement) Type {
 			return (*mapType)(lt.Map)
 		case lt.List != nil:
 			return (*listType)(lt.List)
+		case lt.Variant != nil:
+			return (*variantType)(lt.Variant)
 		case lt.Enum != nil:
 			return (*enumType)(lt.Enum)
 		case lt.Decimal != nil:
@@ -426,6 +430,12 @@ func schemaElementTypeOf(s *format.SchemaElement) Type {
 			return (*bsonType)(lt.Bson)
 		case lt.UUID != nil:
 			return (*uuidType)(lt.UUID)
+		case lt.Float16 != nil:
+			return (*float16Type)(lt.Float16)
+		case lt.Geometry != nil:
+			return (*geometryType)(lt.Geometry)
+		case lt.Geography != nil:
+			return (*geographyType)(lt.Geography)
 		}
 	}
 
@@ -504,7 +514,9 @@ func schemaElementTypeOf(s *format.SchemaElement) Type {
 		case deprecated.Bson:
 			return &bsonType{}
 		case deprecated.Interval:
-			// TODO
+			if s.Type != nil && Kind(*s.Type) == FixedLenByteArray && s.TypeLength != nil && *s.TypeLength == deprecated.IntervalSize {
+				return &intervalType{}
+			}
 		}
 	}
 
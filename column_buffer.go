@@ -1682,6 +1682,32 @@ func (col *fixedLenByteArrayColumnBuffer) ReadValuesAt(values []Value, offset in
 	}
 }
 
+type float16ColumnBuffer struct{ fixedLenByteArrayColumnBuffer }
+
+func newFloat16ColumnBuffer(typ Type, columnIndex int16, numValues int32) *float16ColumnBuffer {
+	return &float16ColumnBuffer{*newFixedLenByteArrayColumnBuffer(typ, columnIndex, numValues)}
+}
+
+func (col *float16ColumnBuffer) Clone() ColumnBuffer {
+	return &float16ColumnBuffer{*col.fixedLenByteArrayColumnBuffer.Clone().(*fixedLenByteArrayColumnBuffer)}
+}
+
+func (col *float16ColumnBuffer) ColumnIndex() (ColumnIndex, error) {
+	return float16ColumnIndex{col.page()}, nil
+}
+
+func (col *float16ColumnBuffer) Pages() Pages { return onePage(col.Page()) }
+
+func (col *float16ColumnBuffer) Page() Page { return col.page() }
+
+func (col *float16ColumnBuffer) page() *float16Page {
+	return &float16Page{col.fixedLenByteArrayPage}
+}
+
+func (col *float16ColumnBuffer) Less(i, j int) bool {
+	return compareFloat16(col.index(i), col.index(j)) < 0
+}
+
 type uint32ColumnBuffer struct{ uint32Page }
 
 func newUint32ColumnBuffer(typ Type, columnIndex int16, numValues int32) *uint32ColumnBuffer {
@@ -1991,8 +2017,24 @@ type writeRowsFunc func(columns []ColumnBuffer, rows sparse.Array, levels column
 // parquet schema. The column path indicates the column that the function is
 // being generated for in the parquet schema.
 func writeRowsFuncOf(t reflect.Type, schema *Schema, path columnPath) writeRowsFunc {
-	if leaf, exists := schema.Lookup(path...); exists && leaf.Node.Type().LogicalType() != nil && leaf.Node.Type().LogicalType().Json != nil {
-		return writeRowsFuncOfJSON(t, schema, path)
+	if t.Kind() != reflect.Pointer {
+		if node := lookupColumnPath(schema, path); node != nil && !node.Leaf() && isVariant(node) {
+			return writeRowsFuncOfVariant(t, schema, path)
+		}
+	}
+
+	if leaf, exists := schema.Lookup(path...); exists {
+		if lt := leaf.Node.Type().LogicalType(); lt != nil {
+			switch {
+			case lt.Json != nil:
+				return writeRowsFuncOfJSON(t, schema, path)
+			case lt.Float16 != nil:
+				switch t.Kind() {
+				case reflect.Float32, reflect.Float64:
+					return writeRowsFuncOfFloat16(t, schema, path)
+				}
+			}
+		}
 	}
 
 	switch t {
@@ -2000,6 +2042,8 @@ func writeRowsFuncOf(t reflect.Type, schema *Schema, path columnPath) writeRowsF
 		return writeRowsFuncOfRequired(t, schema, path)
 	case reflect.TypeOf(time.Time{}):
 		return writeRowsFuncOfTime(t, schema, path)
+	case reflect.TypeOf(deprecated.Duration{}):
+		return writeRowsFuncOfInterval(t, schema, path)
 	}
 
 	switch t.Kind() {
@@ -2272,7 +2316,13 @@ func writeRowsFuncOfStruct(t reflect.Type, schema *Schema, path columnPath) writ
 		writeRows := writeRowsFuncOf(f.Type, schema, columnPath)
 		if optional {
 			switch f.Type.Kind() {
-			case reflect.Pointer, reflect.Slice:
+			case reflect.Pointer:
+			case reflect.Slice:
+				// Byte slices are leaf values written by writeRowsFuncOfRequired,
+				// they need the optional wrapper to produce definition levels.
+				if f.Type.Elem().Kind() == reflect.Uint8 {
+					writeRows = writeRowsFuncOfOptional(f.Type, schema, columnPath, writeRows)
+				}
 			default:
 				writeRows = writeRowsFuncOfOptional(f.Type, schema, columnPath, writeRows)
 			}
@@ -2402,6 +2452,48 @@ func writeRowsFuncOfJSON(t reflect.Type, schema *Schema, path columnPath) writeR
 	}
 }
 
+func writeRowsFuncOfFloat16(t reflect.Type, schema *Schema, path columnPath) writeRowsFunc {
+	writeRows := writeRowsFuncOfRequired(reflect.TypeOf([float16Size]byte{}), schema, path)
+
+	return func(columns []ColumnBuffer, rows sparse.Array, levels columnLevels) error {
+		if rows.Len() == 0 {
+			return writeRows(columns, rows, levels)
+		}
+
+		values := make([]byte, 0, float16Size*rows.Len())
+		for i := 0; i < rows.Len(); i++ {
+			var f float32
+			if t.Kind() == reflect.Float32 {
+				f = *(*float32)(rows.Index(i))
+			} else {
+				f = float32(*(*float64)(rows.Index(i)))
+			}
+			values = appendFloat16(values, f)
+		}
+
+		a := makeArray(unsafe.Pointer(unsafe.SliceData(values)), rows.Len(), float16Size)
+		return writeRows(columns, a, levels)
+	}
+}
+
+func writeRowsFuncOfInterval(_ reflect.Type, schema *Schema, path columnPath) writeRowsFunc {
+	writeRows := writeRowsFuncOfRequired(reflect.TypeOf([deprecated.IntervalSize]byte{}), schema, path)
+
+	return func(columns []ColumnBuffer, rows sparse.Array, levels columnLevels) error {
+		if rows.Len() == 0 {
+			return writeRows(columns, rows, levels)
+		}
+
+		values := make([]byte, 0, deprecated.IntervalSize*rows.Len())
+		for i := 0; i < rows.Len(); i++ {
+			values = (*(*deprecated.Duration)(rows.Index(i))).Append(values)
+		}
+
+		a := makeArray(unsafe.Pointer(unsafe.SliceData(values)), rows.Len(), deprecated.IntervalSize)
+		return writeRows(columns, a, levels)
+	}
+}
+
 func writeRowsFuncOfTime(_ reflect.Type, schema *Schema, path columnPath) writeRowsFunc {
 	t := reflect.TypeOf(int64(0))
 	elemSize := uintptr(t.Size())
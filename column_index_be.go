@@ -72,14 +72,14 @@ func (f *formatColumnIndex) MinValue(i int) Value {
 	if f.NullPage(i) {
 		return Value{}
 	}
-	return f.kind.Value(f.index.MinValues[i])
+	return makeColumnIndexValue(f.kind, f.index.MinValues[i])
 }
 
 func (f *formatColumnIndex) MaxValue(i int) Value {
 	if f.NullPage(i) {
 		return Value{}
 	}
-	return f.kind.Value(f.index.MaxValues[i])
+	return makeColumnIndexValue(f.kind, f.index.MaxValues[i])
 }
 
 func (f *formatColumnIndex) IsAscending() bool {
@@ -133,7 +133,7 @@ func (i fileColumnIndex) IsDescending() bool {
 }
 
 func (i *fileColumnIndex) makeValue(b []byte) Value {
-	return i.chunk.column.typ.Kind().Value(b)
+	return makeColumnIndexValue(i.chunk.column.typ.Kind(), b)
 }
 
 func (i fileColumnIndex) columnIndex() *format.ColumnIndex { return i.chunk.columnIndex.Load() }
@@ -235,6 +235,20 @@ func (i fixedLenByteArrayColumnIndex) MaxValue(int) Value {
 func (i fixedLenByteArrayColumnIndex) IsAscending() bool  { return false }
 func (i fixedLenByteArrayColumnIndex) IsDescending() bool { return false }
 
+type float16ColumnIndex struct{ page *float16Page }
+
+func (i float16ColumnIndex) NumPages() int       { return 1 }
+func (i float16ColumnIndex) NullCount(int) int64 { return 0 }
+func (i float16ColumnIndex) NullPage(int) bool   { return false }
+func (i float16ColumnIndex) MinValue(int) Value {
+	return makeValueBytes(FixedLenByteArray, i.page.min())
+}
+func (i float16ColumnIndex) MaxValue(int) Value {
+	return makeValueBytes(FixedLenByteArray, i.page.max())
+}
+func (i float16ColumnIndex) IsAscending() bool  { return false }
+func (i float16ColumnIndex) IsDescending() bool { return false }
+
 type uint32ColumnIndex struct{ page *uint32Page }
 
 func (i uint32ColumnIndex) NumPages() int       { return 1 }
@@ -656,6 +670,40 @@ func (i *fixedLenByteArrayColumnIndexer) ColumnIndex() format.ColumnIndex {
 	)
 }
 
+type float16ColumnIndexer struct {
+	baseColumnIndexer
+	minValues [][float16Size]byte
+	maxValues [][float16Size]byte
+}
+
+func newFloat16ColumnIndexer() *float16ColumnIndexer {
+	return new(float16ColumnIndexer)
+}
+
+func (i *float16ColumnIndexer) Reset() {
+	i.reset()
+	i.minValues = i.minValues[:0]
+	i.maxValues = i.maxValues[:0]
+}
+
+func (i *float16ColumnIndexer) IndexPage(numValues, numNulls int64, min, max Value) {
+	i.observe(numValues, numNulls)
+	var minValue, maxValue [float16Size]byte
+	copy(minValue[:], min.byteArray())
+	copy(maxValue[:], max.byteArray())
+	i.minValues = append(i.minValues, minValue)
+	i.maxValues = append(i.maxValues, maxValue)
+}
+
+func (i *float16ColumnIndexer) ColumnIndex() format.ColumnIndex {
+	return i.columnIndex(
+		splitFixedLenByteArrays(unsafecast.Slice[byte](i.minValues), float16Size),
+		splitFixedLenByteArrays(unsafecast.Slice[byte](i.maxValues), float16Size),
+		orderOfFloat16(i.minValues),
+		orderOfFloat16(i.maxValues),
+	)
+}
+
 type uint32ColumnIndexer struct {
 	baseColumnIndexer
 	minValues []uint32
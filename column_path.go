@@ -14,6 +14,10 @@ func (path columnPath) equal(other columnPath) bool {
 	return stringsAreEqual(path, other)
 }
 
+func (path columnPath) isPrefixOf(other columnPath) bool {
+	return len(path) <= len(other) && stringsAreEqual(path, other[:len(path)])
+}
+
 func (path columnPath) less(other columnPath) bool {
 	return stringsAreOrdered(path, other)
 }
@@ -121,6 +121,10 @@ func compareFloat64(v1, v2 float64) int {
 	}
 }
 
+func compareFloat16(v1, v2 []byte) int {
+	return compareFloat32(float16FromBytes(v1), float16FromBytes(v2))
+}
+
 func compareUint32(v1, v2 uint32) int {
 	switch {
 	case v1 < v2:
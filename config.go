@@ -8,6 +8,7 @@ import (
 	"sync"
 
 	"github.com/parquet-go/parquet-go/compress"
+	"github.com/parquet-go/parquet-go/encoding"
 )
 
 // ReadMode is an enum that is used to configure the way that a File reads pages.
@@ -18,17 +19,37 @@ const (
 	ReadModeAsync                 // ReadModeAsync reads pages asynchronously in the background.
 )
 
+// AdaptiveEncodingMode is an enum that is used to configure how writers select
+// the encoding of columns that have no explicit encoding in their schema.
+type AdaptiveEncodingMode int
+
+const (
+	AdaptiveEncodingDisabled       AdaptiveEncodingMode = iota // AdaptiveEncodingDisabled uses the default encoding of columns (Default).
+	AdaptiveEncodingSize                                       // AdaptiveEncodingSize selects the encoding producing the smallest pages.
+	AdaptiveEncodingCompressedSize                             // AdaptiveEncodingCompressedSize selects the encoding producing the smallest pages after compression.
+)
+
 const (
-	DefaultColumnIndexSizeLimit = 16
-	DefaultColumnBufferCapacity = 16 * 1024
-	DefaultPageBufferSize       = 256 * 1024
-	DefaultWriteBufferSize      = 32 * 1024
-	DefaultDataPageVersion      = 2
-	DefaultDataPageStatistics   = false
-	DefaultSkipPageIndex        = false
-	DefaultSkipBloomFilters     = false
-	DefaultMaxRowsPerRowGroup   = math.MaxInt64
-	DefaultReadMode             = ReadModeSync
+	DefaultColumnIndexSizeLimit  = 16
+	DefaultColumnBufferCapacity  = 16 * 1024
+	DefaultPageBufferSize        = 256 * 1024
+	DefaultDataPageRowCountLimit = math.MaxInt
+	DefaultWriteBufferSize       = 32 * 1024
+	DefaultDataPageVersion       = 2
+	DefaultDataPageStatistics    = false
+	DefaultSkipPageChecksums     = false
+	DefaultDictionaryMaxBytes    = 1024 * 1024
+	DefaultSkipPageIndex         = false
+	DefaultSkipBloomFilters      = false
+	DefaultMaxRowsPerRowGroup    = math.MaxInt64
+	DefaultMaxRowGroupBytes      = math.MaxInt64
+	DefaultReadMode              = ReadModeSync
+	DefaultReadConcurrency       = 1
+	DefaultReadAheadBytes        = 64 * 1024 * 1024
+	DefaultWriteConcurrency      = 1
+	DefaultPrefetchMaxGap        = 1024 * 1024
+	DefaultPrefetchMaxRangeSize  = 32 * 1024 * 1024
+	DefaultPrefetchConcurrency   = 4
 )
 
 const (
@@ -90,11 +111,20 @@ func formatCreatedBy(application, version, build string) string {
 //		ReadMode:         ReadModeAsync,
 //	})
 type FileConfig struct {
-	SkipPageIndex    bool
-	SkipBloomFilters bool
-	ReadBufferSize   int
-	ReadMode         ReadMode
-	Schema           *Schema
+	SkipPageIndex        bool
+	SkipBloomFilters     bool
+	ReadBufferSize       int
+	ReadMode             ReadMode
+	Schema               *Schema
+	KeyRetriever         KeyRetriever
+	AADPrefix            []byte
+	RequirePageChecksums bool
+
+	Prefetch             bool
+	PrefetchColumns      [][]string
+	PrefetchMaxGap       int64
+	PrefetchMaxRangeSize int64
+	PrefetchConcurrency  int
 }
 
 // DefaultFileConfig returns a new FileConfig value initialized with the
@@ -106,6 +136,10 @@ func DefaultFileConfig() *FileConfig {
 		ReadBufferSize:   defaultReadBufferSize,
 		ReadMode:         DefaultReadMode,
 		Schema:           nil,
+
+		PrefetchMaxGap:       DefaultPrefetchMaxGap,
+		PrefetchMaxRangeSize: DefaultPrefetchMaxRangeSize,
+		PrefetchConcurrency:  DefaultPrefetchConcurrency,
 	}
 }
 
@@ -130,17 +164,31 @@ func (c *FileConfig) Apply(options ...FileOption) {
 // ConfigureFile applies configuration options from c to config.
 func (c *FileConfig) ConfigureFile(config *FileConfig) {
 	*config = FileConfig{
-		SkipPageIndex:    c.SkipPageIndex,
-		SkipBloomFilters: c.SkipBloomFilters,
-		ReadBufferSize:   coalesceInt(c.ReadBufferSize, config.ReadBufferSize),
-		ReadMode:         ReadMode(coalesceInt(int(c.ReadMode), int(config.ReadMode))),
-		Schema:           coalesceSchema(c.Schema, config.Schema),
+		SkipPageIndex:        c.SkipPageIndex,
+		SkipBloomFilters:     c.SkipBloomFilters,
+		ReadBufferSize:       coalesceInt(c.ReadBufferSize, config.ReadBufferSize),
+		ReadMode:             ReadMode(coalesceInt(int(c.ReadMode), int(config.ReadMode))),
+		Schema:               coalesceSchema(c.Schema, config.Schema),
+		KeyRetriever:         coalesceKeyRetriever(c.KeyRetriever, config.KeyRetriever),
+		AADPrefix:            coalesceBytes(c.AADPrefix, config.AADPrefix),
+		RequirePageChecksums: c.RequirePageChecksums,
+
+		Prefetch:             c.Prefetch,
+		PrefetchColumns:      coalesceColumnPaths(c.PrefetchColumns, config.PrefetchColumns),
+		PrefetchMaxGap:       coalesceInt64(c.PrefetchMaxGap, config.PrefetchMaxGap),
+		PrefetchMaxRangeSize: coalesceInt64(c.PrefetchMaxRangeSize, config.PrefetchMaxRangeSize),
+		PrefetchConcurrency:  coalesceInt(c.PrefetchConcurrency, config.PrefetchConcurrency),
 	}
 }
 
 // Validate returns a non-nil error if the configuration of c is invalid.
 func (c *FileConfig) Validate() error {
-	return nil
+	const baseName = "parquet.(*FileConfig)."
+	return errorInvalidConfiguration(
+		validatePositiveInt64(baseName+"PrefetchMaxGap", c.PrefetchMaxGap),
+		validatePositiveInt64(baseName+"PrefetchMaxRangeSize", c.PrefetchMaxRangeSize),
+		validatePositiveInt(baseName+"PrefetchConcurrency", c.PrefetchConcurrency),
+	)
 }
 
 // The ReaderConfig type carries configuration options for parquet readers.
@@ -152,13 +200,21 @@ func (c *FileConfig) Validate() error {
 //		// ...
 //	})
 type ReaderConfig struct {
-	Schema *Schema
+	Schema          *Schema
+	Filter          Predicate
+	Projection      [][]string
+	Selection       RowSelection
+	ReadConcurrency int
+	ReadAheadBytes  int64
 }
 
 // DefaultReaderConfig returns a new ReaderConfig value initialized with the
 // default reader configuration.
 func DefaultReaderConfig() *ReaderConfig {
-	return &ReaderConfig{}
+	return &ReaderConfig{
+		ReadConcurrency: DefaultReadConcurrency,
+		ReadAheadBytes:  DefaultReadAheadBytes,
+	}
 }
 
 // NewReaderConfig constructs a new reader configuration applying the options
@@ -182,13 +238,23 @@ func (c *ReaderConfig) Apply(options ...ReaderOption) {
 // ConfigureReader applies configuration options from c to config.
 func (c *ReaderConfig) ConfigureReader(config *ReaderConfig) {
 	*config = ReaderConfig{
-		Schema: coalesceSchema(c.Schema, config.Schema),
+		Schema:          coalesceSchema(c.Schema, config.Schema),
+		Filter:          coalescePredicate(c.Filter, config.Filter),
+		Projection:      coalesceColumnPaths(c.Projection, config.Projection),
+		Selection:       coalesceRowSelection(c.Selection, config.Selection),
+		ReadConcurrency: coalesceInt(c.ReadConcurrency, config.ReadConcurrency),
+		ReadAheadBytes:  coalesceInt64(c.ReadAheadBytes, config.ReadAheadBytes),
 	}
 }
 
 // Validate returns a non-nil error if the configuration of c is invalid.
 func (c *ReaderConfig) Validate() error {
-	return nil
+	const baseName = "parquet.(*ReaderConfig)."
+	return errorInvalidConfiguration(
+		validatePositiveInt(baseName+"ReadConcurrency", c.ReadConcurrency),
+		validatePositiveInt64(baseName+"ReadAheadBytes", c.ReadAheadBytes),
+		c.Selection.Validate(),
+	)
 }
 
 // The WriterConfig type carries configuration options for parquet writers.
@@ -200,34 +266,65 @@ func (c *ReaderConfig) Validate() error {
 //		CreatedBy: "my test program",
 //	})
 type WriterConfig struct {
-	CreatedBy            string
-	ColumnPageBuffers    BufferPool
-	ColumnIndexSizeLimit int
-	PageBufferSize       int
-	WriteBufferSize      int
-	DataPageVersion      int
-	DataPageStatistics   bool
-	MaxRowsPerRowGroup   int64
-	KeyValueMetadata     map[string]string
-	Schema               *Schema
-	BloomFilters         []BloomFilterColumn
-	Compression          compress.Codec
-	Sorting              SortingConfig
-	SkipPageBounds       [][]string
+	CreatedBy             string
+	ColumnPageBuffers     BufferPool
+	ColumnIndexSizeLimit  int
+	PageBufferSize        int
+	DataPageRowCountLimit int
+	PageLimits            []PageLimit
+	WriteBufferSize       int
+	DataPageVersion       int
+	DataPageStatistics    bool
+	SkipPageChecksums     bool
+	MaxRowsPerRowGroup    int64
+	MaxRowGroupBytes      int64
+	KeyValueMetadata      map[string]string
+	Schema                *Schema
+	BloomFilters          []BloomFilterColumn
+	Compression           compress.Codec
+	Sorting               SortingConfig
+	SkipPageBounds        [][]string
+	Encryption            *EncryptionConfig
+	DictionaryMaxBytes    int64
+	DictionaryLimits      []DictionaryLimit
+	AdaptiveEncoding      AdaptiveEncodingMode
+	WriteConcurrency      int
+}
+
+// PageLimit overrides the page buffer size and the row count limit of data
+// pages of the column at Path. Zero values retain the limits configured on the
+// writer.
+type PageLimit struct {
+	Path          []string
+	BufferSize    int
+	RowCountLimit int
+}
+
+// DictionaryLimit overrides the dictionary size limit and the fallback
+// encoding of the column at Path.
+type DictionaryLimit struct {
+	Path     []string
+	MaxBytes int64
+	Fallback encoding.Encoding
 }
 
 // DefaultWriterConfig returns a new WriterConfig value initialized with the
 // default writer configuration.
 func DefaultWriterConfig() *WriterConfig {
 	return &WriterConfig{
-		CreatedBy:            defaultCreatedBy(),
-		ColumnPageBuffers:    &defaultColumnBufferPool,
-		ColumnIndexSizeLimit: DefaultColumnIndexSizeLimit,
-		PageBufferSize:       DefaultPageBufferSize,
-		WriteBufferSize:      DefaultWriteBufferSize,
-		DataPageVersion:      DefaultDataPageVersion,
-		DataPageStatistics:   DefaultDataPageStatistics,
-		MaxRowsPerRowGroup:   DefaultMaxRowsPerRowGroup,
+		CreatedBy:             defaultCreatedBy(),
+		ColumnPageBuffers:     &defaultColumnBufferPool,
+		ColumnIndexSizeLimit:  DefaultColumnIndexSizeLimit,
+		PageBufferSize:        DefaultPageBufferSize,
+		DataPageRowCountLimit: DefaultDataPageRowCountLimit,
+		WriteBufferSize:       DefaultWriteBufferSize,
+		DataPageVersion:       DefaultDataPageVersion,
+		DataPageStatistics:    DefaultDataPageStatistics,
+		SkipPageChecksums:     DefaultSkipPageChecksums,
+		MaxRowsPerRowGroup:    DefaultMaxRowsPerRowGroup,
+		MaxRowGroupBytes:      DefaultMaxRowGroupBytes,
+		DictionaryMaxBytes:    DefaultDictionaryMaxBytes,
+		WriteConcurrency:      DefaultWriteConcurrency,
 		Sorting: SortingConfig{
 			SortingBuffers: &defaultSortingBufferPool,
 		},
@@ -265,32 +362,63 @@ func (c *WriterConfig) ConfigureWriter(config *WriterConfig) {
 	}
 
 	*config = WriterConfig{
-		CreatedBy:            coalesceString(c.CreatedBy, config.CreatedBy),
-		ColumnPageBuffers:    coalesceBufferPool(c.ColumnPageBuffers, config.ColumnPageBuffers),
-		ColumnIndexSizeLimit: coalesceInt(c.ColumnIndexSizeLimit, config.ColumnIndexSizeLimit),
-		PageBufferSize:       coalesceInt(c.PageBufferSize, config.PageBufferSize),
-		WriteBufferSize:      coalesceInt(c.WriteBufferSize, config.WriteBufferSize),
-		DataPageVersion:      coalesceInt(c.DataPageVersion, config.DataPageVersion),
-		DataPageStatistics:   coalesceBool(c.DataPageStatistics, config.DataPageStatistics),
-		MaxRowsPerRowGroup:   coalesceInt64(c.MaxRowsPerRowGroup, config.MaxRowsPerRowGroup),
-		KeyValueMetadata:     keyValueMetadata,
-		Schema:               coalesceSchema(c.Schema, config.Schema),
-		BloomFilters:         coalesceBloomFilters(c.BloomFilters, config.BloomFilters),
-		Compression:          coalesceCompression(c.Compression, config.Compression),
-		Sorting:              coalesceSortingConfig(c.Sorting, config.Sorting),
+		CreatedBy:             coalesceString(c.CreatedBy, config.CreatedBy),
+		ColumnPageBuffers:     coalesceBufferPool(c.ColumnPageBuffers, config.ColumnPageBuffers),
+		ColumnIndexSizeLimit:  coalesceInt(c.ColumnIndexSizeLimit, config.ColumnIndexSizeLimit),
+		PageBufferSize:        coalesceInt(c.PageBufferSize, config.PageBufferSize),
+		DataPageRowCountLimit: coalesceInt(c.DataPageRowCountLimit, config.DataPageRowCountLimit),
+		PageLimits:            append(config.PageLimits[:len(config.PageLimits):len(config.PageLimits)], c.PageLimits...),
+		WriteBufferSize:       coalesceInt(c.WriteBufferSize, config.WriteBufferSize),
+		DataPageVersion:       coalesceInt(c.DataPageVersion, config.DataPageVersion),
+		DataPageStatistics:    coalesceBool(c.DataPageStatistics, config.DataPageStatistics),
+		SkipPageChecksums:     coalesceBool(c.SkipPageChecksums, config.SkipPageChecksums),
+		MaxRowsPerRowGroup:    coalesceInt64(c.MaxRowsPerRowGroup, config.MaxRowsPerRowGroup),
+		MaxRowGroupBytes:      coalesceInt64(c.MaxRowGroupBytes, config.MaxRowGroupBytes),
+		KeyValueMetadata:      keyValueMetadata,
+		Schema:                coalesceSchema(c.Schema, config.Schema),
+		BloomFilters:          coalesceBloomFilters(c.BloomFilters, config.BloomFilters),
+		Compression:           coalesceCompression(c.Compression, config.Compression),
+		Sorting:               coalesceSortingConfig(c.Sorting, config.Sorting),
+		Encryption:            coalesceEncryptionConfig(c.Encryption, config.Encryption),
+		DictionaryMaxBytes:    coalesceInt64(c.DictionaryMaxBytes, config.DictionaryMaxBytes),
+		DictionaryLimits:      append(config.DictionaryLimits[:len(config.DictionaryLimits):len(config.DictionaryLimits)], c.DictionaryLimits...),
+		AdaptiveEncoding:      AdaptiveEncodingMode(coalesceInt(int(c.AdaptiveEncoding), int(config.AdaptiveEncoding))),
+		WriteConcurrency:      coalesceInt(c.WriteConcurrency, config.WriteConcurrency),
 	}
 }
 
 // Validate returns a non-nil error if the configuration of c is invalid.
 func (c *WriterConfig) Validate() error {
 	const baseName = "parquet.(*WriterConfig)."
-	return errorInvalidConfiguration(
+	var encryption error
+	if c.Encryption != nil {
+		encryption = c.Encryption.Validate()
+	}
+	reasons := []error{
 		validateNotNil(baseName+"ColumnPageBuffers", c.ColumnPageBuffers),
 		validatePositiveInt(baseName+"ColumnIndexSizeLimit", c.ColumnIndexSizeLimit),
 		validatePositiveInt(baseName+"PageBufferSize", c.PageBufferSize),
+		validatePositiveInt(baseName+"DataPageRowCountLimit", c.DataPageRowCountLimit),
 		validateOneOfInt(baseName+"DataPageVersion", c.DataPageVersion, 1, 2),
+		validatePositiveInt64(baseName+"MaxRowGroupBytes", c.MaxRowGroupBytes),
+		validatePositiveInt64(baseName+"DictionaryMaxBytes", c.DictionaryMaxBytes),
+		validateOneOfInt(baseName+"AdaptiveEncoding", int(c.AdaptiveEncoding), int(AdaptiveEncodingDisabled), int(AdaptiveEncodingSize), int(AdaptiveEncodingCompressedSize)),
+		validatePositiveInt(baseName+"WriteConcurrency", c.WriteConcurrency),
 		c.Sorting.Validate(),
-	)
+		encryption,
+	}
+	for _, limit := range c.PageLimits {
+		if limit.BufferSize < 0 || limit.RowCountLimit < 0 || (limit.BufferSize == 0 && limit.RowCountLimit == 0) {
+			reasons = append(reasons, errorInvalidOptionValue(baseName+"PageLimits", limit))
+		}
+	}
+	for _, limit := range c.DictionaryLimits {
+		reasons = append(reasons, validatePositiveInt64(baseName+"DictionaryLimits", limit.MaxBytes))
+		if limit.Fallback != nil && isDictionaryEncoding(limit.Fallback) {
+			reasons = append(reasons, errorInvalidOptionValue(baseName+"DictionaryLimits", limit.Fallback))
+		}
+	}
+	return errorInvalidConfiguration(reasons...)
 }
 
 // The RowGroupConfig type carries configuration options for parquet row groups.
@@ -405,6 +533,107 @@ func (c *SortingConfig) ConfigureSorting(config *SortingConfig) {
 	*config = coalesceSortingConfig(*c, *config)
 }
 
+// EncryptionAlgorithm is an enum representing the algorithms used to encrypt
+// parquet files.
+type EncryptionAlgorithm int
+
+const (
+	// AESGCMV1 encrypts all the modules of parquet files with AES-GCM.
+	AESGCMV1 EncryptionAlgorithm = iota
+	// AESGCMCTRV1 encrypts data and dictionary pages with AES-CTR, and all
+	// other modules with AES-GCM. This trades integrity verification of the
+	// page contents for faster encryption.
+	AESGCMCTRV1
+)
+
+// The EncryptionConfig type carries configuration options for writing
+// parquet files with modular encryption.
+//
+// When no column keys are configured, all columns are encrypted with the
+// footer key. Otherwise, only the configured columns are encrypted, and the
+// other columns are written in plaintext.
+//
+// EncryptionConfig implements the EncryptionOption interface so it can be used
+// directly as argument to the FileEncryption function when needed, for example:
+//
+//	writer := parquet.NewWriter(output, parquet.FileEncryption(
+//		&parquet.EncryptionConfig{
+//			FooterKey: footerKey,
+//			Algorithm: parquet.AESGCMCTRV1,
+//		},
+//	))
+type EncryptionConfig struct {
+	Algorithm         EncryptionAlgorithm
+	FooterKey         []byte
+	FooterKeyMetadata []byte
+	ColumnKeys        []ColumnEncryptionKey
+	AADPrefix         []byte
+	SupplyAADPrefix   bool
+	PlaintextFooter   bool
+}
+
+// ColumnEncryptionKey associates a key to the path of a column encrypted with
+// a key different from the footer key.
+type ColumnEncryptionKey struct {
+	Path        []string
+	Key         []byte
+	KeyMetadata []byte
+}
+
+// DefaultEncryptionConfig returns a new EncryptionConfig value initialized
+// with the default encryption configuration.
+func DefaultEncryptionConfig() *EncryptionConfig {
+	return &EncryptionConfig{
+		Algorithm: AESGCMV1,
+	}
+}
+
+// NewEncryptionConfig constructs a new encryption configuration applying the
+// options passed as arguments.
+//
+// The function returns an non-nil error if some of the options carried invalid
+// configuration values.
+func NewEncryptionConfig(options ...EncryptionOption) (*EncryptionConfig, error) {
+	config := DefaultEncryptionConfig()
+	config.Apply(options...)
+	return config, config.Validate()
+}
+
+// Validate returns a non-nil error if the configuration of c is invalid.
+func (c *EncryptionConfig) Validate() error {
+	const baseName = "parquet.(*EncryptionConfig)."
+	reasons := []error{
+		validateOneOfInt(baseName+"Algorithm", int(c.Algorithm), int(AESGCMV1), int(AESGCMCTRV1)),
+		validateEncryptionKey(baseName+"FooterKey", c.FooterKey),
+	}
+	for _, column := range c.ColumnKeys {
+		if column.Key != nil {
+			reasons = append(reasons, validateEncryptionKey(baseName+"ColumnKeys", column.Key))
+		}
+	}
+	return errorInvalidConfiguration(reasons...)
+}
+
+// Apply applies the given list of options to c.
+func (c *EncryptionConfig) Apply(options ...EncryptionOption) {
+	for _, opt := range options {
+		opt.ConfigureEncryption(c)
+	}
+}
+
+// ConfigureEncryption applies configuration options from c to config.
+func (c *EncryptionConfig) ConfigureEncryption(config *EncryptionConfig) {
+	*config = EncryptionConfig{
+		Algorithm:         EncryptionAlgorithm(coalesceInt(int(c.Algorithm), int(config.Algorithm))),
+		FooterKey:         coalesceBytes(c.FooterKey, config.FooterKey),
+		FooterKeyMetadata: coalesceBytes(c.FooterKeyMetadata, config.FooterKeyMetadata),
+		ColumnKeys:        append(config.ColumnKeys[:len(config.ColumnKeys):len(config.ColumnKeys)], c.ColumnKeys...),
+		AADPrefix:         coalesceBytes(c.AADPrefix, config.AADPrefix),
+		SupplyAADPrefix:   coalesceBool(c.SupplyAADPrefix, config.SupplyAADPrefix),
+		PlaintextFooter:   coalesceBool(c.PlaintextFooter, config.PlaintextFooter),
+	}
+}
+
 // FileOption is an interface implemented by types that carry configuration
 // options for parquet files.
 type FileOption interface {
@@ -435,6 +664,12 @@ type SortingOption interface {
 	ConfigureSorting(*SortingConfig)
 }
 
+// EncryptionOption is an interface implemented by types that carry
+// configuration options for the encryption of parquet files.
+type EncryptionOption interface {
+	ConfigureEncryption(*EncryptionConfig)
+}
+
 // SkipPageIndex is a file configuration option which prevents automatically
 // reading the page index when opening a parquet file, when set to true. This is
 // useful as an optimization when programs know that they will not need to
@@ -486,6 +721,167 @@ func FileSchema(schema *Schema) FileOption {
 	return fileOption(func(config *FileConfig) { config.Schema = schema })
 }
 
+// FileDecryption is a file configuration option which sets the key retriever
+// used to decrypt parquet files written with modular encryption.
+//
+// Both files with encrypted footers and files with plaintext footers are
+// supported. In the latter case, the signature of the footer is verified with
+// the footer key. Files with plaintext footers may be opened without a key
+// retriever, but only their unencrypted columns can be read.
+//
+// Defaults to nil.
+func FileDecryption(keys KeyRetriever) FileOption {
+	return fileOption(func(config *FileConfig) { config.KeyRetriever = keys })
+}
+
+// FileAADPrefix is a file configuration option which supplies the AAD prefix
+// of encrypted parquet files. It is required to open files written with an AAD
+// prefix that was not stored in the file.
+//
+// Defaults to nil.
+func FileAADPrefix(prefix []byte) FileOption {
+	return fileOption(func(config *FileConfig) { config.AADPrefix = prefix })
+}
+
+// RequirePageChecksums is a file configuration option which makes reading pages
+// that have no CRC32 checksum in their header fail with ErrMissingPageChecksum,
+// when set to true. Checksums of pages are always verified when present; this
+// option guarantees that all the data read from the file was verified.
+//
+// Defaults to false.
+func RequirePageChecksums(require bool) FileOption {
+	return fileOption(func(config *FileConfig) { config.RequirePageChecksums = require })
+}
+
+// PrefetchColumns is a file configuration option which enables prefetching
+// the parts of parquet files that are needed to read the given columns when
+// the files are opened. When no columns are passed, all the columns of the
+// file are prefetched.
+//
+// After the footer was read, the byte ranges of the page index, the bloom
+// filters, and the column chunks of the prefetched columns are planned, nearby
+// ranges are merged into larger ones according to the PrefetchMaxGap and
+// PrefetchMaxRangeSize options, and the ranges are read concurrently. Reads of
+// the file which fall within the prefetched ranges are then served from
+// memory. This option is useful to reduce the number of requests issued to
+// network storage such as object stores, at the expense of holding the
+// prefetched column chunks in memory for as long as the file is used.
+//
+// Defaults to prefetching nothing.
+func PrefetchColumns(paths ...[]string) FileOption {
+	return fileOption(func(config *FileConfig) {
+		config.Prefetch = true
+		config.PrefetchColumns = paths
+	})
+}
+
+// PrefetchMaxGap is a file configuration option which sets the largest number
+// of unused bytes between two ranges which are merged into a single read when
+// prefetching files with the PrefetchColumns option.
+//
+// Defaults to 1 MiB.
+func PrefetchMaxGap(size int64) FileOption {
+	return fileOption(func(config *FileConfig) { config.PrefetchMaxGap = size })
+}
+
+// PrefetchMaxRangeSize is a file configuration option which sets the size
+// limit of reads issued when prefetching files with the PrefetchColumns
+// option; larger ranges are split into multiple reads.
+//
+// Defaults to 32 MiB.
+func PrefetchMaxRangeSize(size int64) FileOption {
+	return fileOption(func(config *FileConfig) { config.PrefetchMaxRangeSize = size })
+}
+
+// PrefetchConcurrency is a file configuration option which sets the number of
+// reads issued concurrently when prefetching files with the PrefetchColumns
+// option.
+//
+// Defaults to 4.
+func PrefetchConcurrency(reads int) FileOption {
+	return fileOption(func(config *FileConfig) { config.PrefetchConcurrency = reads })
+}
+
+// Filter configures a predicate used by readers to only return rows matching
+// it.
+//
+// Row groups and pages which cannot contain rows matching the predicate are
+// skipped using the statistics, page indexes, bloom filters, and dictionaries
+// of the column chunks; the number of rows that were pruned is reported by the
+// FilterStats method of readers. The predicate is then evaluated on each of the
+// remaining rows: values of the columns that it depends on are decoded first,
+// and values of the other columns are only decoded for rows that matched.
+//
+// The number of rows reported by the NumRows method of readers configured with
+// a filter is an upper bound of the number of rows matching the predicate. Row
+// indexes passed to their SeekToRow method are relative to the rows matching
+// the predicate.
+func Filter(predicate Predicate) ReaderOption {
+	return readerOption(func(config *ReaderConfig) { config.Filter = predicate })
+}
+
+// Projection configures readers to only read the columns at the given paths.
+// A path may designate a group, in which case all the columns of the group are
+// read.
+//
+// The paths are resolved in the schema of the reader (e.g. the schema of the
+// type parameter of GenericReader), the pages of other columns are not read
+// from the files, and the corresponding fields of the Go values that rows are
+// read into are left to their zero value. The predicate of the Filter option
+// may depend on columns which are not projected.
+//
+// Paths may also designate the typed columns of shredded VARIANT groups (see
+// VariantFieldPath), in which case the variant values are reconstructed from
+// those columns only: the values which were not shredded are absent from the
+// objects, and the metadata and value columns are not read unless they are
+// needed to decode the projected columns.
+//
+// Readers panic if one of the paths does not exist in their schema.
+func Projection(paths ...[]string) ReaderOption {
+	return readerOption(func(config *ReaderConfig) { config.Projection = paths })
+}
+
+// SelectRows configures readers to only read the rows within the selection.
+// The row indexes of the selection are relative to the beginning of the file
+// or row group that the reader reads from.
+//
+// Only the pages which contain selected rows are read when the column chunks
+// have an offset index, and values of the rows outside of the selection are
+// not returned. When combined with the Filter option, readers return the rows
+// of the selection which match the predicate. The NumRows and SeekToRow methods
+// of readers apply to the selected rows.
+//
+// Readers panic if the ranges of the selection are not sorted, or overlap.
+func SelectRows(selection RowSelection) ReaderOption {
+	return readerOption(func(config *ReaderConfig) { config.Selection = selection })
+}
+
+// ReadConcurrency configures the number of goroutines that readers use to
+// decode row groups concurrently. The rows are still returned in the order of
+// the row groups in the files.
+//
+// Each goroutine decodes the next row group that is not being decoded yet, the
+// rows are buffered in memory until they are read; see ReadAheadBytes to
+// configure the amount of memory used for buffering. Concurrency does not
+// apply within a row group, files with a single row group are decoded on the
+// goroutine reading the rows.
+//
+// Defaults to 1.
+func ReadConcurrency(workers int) ReaderOption {
+	return readerOption(func(config *ReaderConfig) { config.ReadConcurrency = workers })
+}
+
+// ReadAheadBytes configures the approximate amount of memory that readers with
+// a read concurrency greater than one use to buffer the values of rows that
+// were decoded ahead of the rows being read. The goroutines decoding row
+// groups pause when the limit is reached, except for the one decoding the row
+// group that rows are being read from.
+//
+// Defaults to 64 MiB.
+func ReadAheadBytes(size int64) ReaderOption {
+	return readerOption(func(config *ReaderConfig) { config.ReadAheadBytes = size })
+}
+
 // PageBufferSize configures the size of column page buffers on parquet writers.
 //
 // Note that the page buffer size refers to the in-memory buffers where pages
@@ -499,6 +895,34 @@ func PageBufferSize(size int) WriterOption {
 	return writerOption(func(config *WriterConfig) { config.PageBufferSize = size })
 }
 
+// DataPageRowCountLimit configures the maximum number of rows in data pages
+// produced by parquet writers.
+//
+// Pages are written when either the page buffer size or the row count limit
+// is reached. Limiting the number of rows helps keep the column index useful
+// to skip pages of columns with small values, such as booleans or dictionary
+// indexes, which would otherwise hold a very large number of rows per page.
+// Pages always end on row boundaries, so pages of repeated columns may hold
+// more values than rows.
+//
+// Defaults to unlimited.
+func DataPageRowCountLimit(numRows int) WriterOption {
+	return writerOption(func(config *WriterConfig) { config.DataPageRowCountLimit = numRows })
+}
+
+// ColumnPageLimits overrides the page buffer size and the row count limit of
+// data pages of the column at the given path. A zero value retains the limit
+// configured on the writer.
+//
+// This option is additive, it may be used multiple times to configure limits
+// of multiple columns.
+func ColumnPageLimits(bufferSize, rowCountLimit int, path ...string) WriterOption {
+	limit := PageLimit{Path: path, BufferSize: bufferSize, RowCountLimit: rowCountLimit}
+	return writerOption(func(config *WriterConfig) {
+		config.PageLimits = append(config.PageLimits, limit)
+	})
+}
+
 // WriteBufferSize configures the size of the write buffer.
 //
 // Setting the writer buffer size to zero deactivates buffering, all writes are
@@ -525,6 +949,28 @@ func MaxRowsPerRowGroup(numRows int64) WriterOption {
 	return writerOption(func(config *WriterConfig) { config.MaxRowsPerRowGroup = numRows })
 }
 
+// MaxRowGroupBytes configures the target size in bytes of the row groups that
+// a writer will produce.
+//
+// The writer estimates the size that the row group will have in the file from
+// the size of the pages that it already encoded and compressed, and from the
+// size of the values buffered in memory, scaled by the ratio observed between
+// the in-memory and on-disk size of previous pages of each column. The row
+// group is flushed once the estimate reaches the target; since rows cannot be
+// split, and the estimate is only an approximation, row groups may be slightly
+// larger or smaller than the target.
+//
+// This option may be combined with MaxRowsPerRowGroup, row groups are flushed
+// when either limit is reached.
+//
+// Defaults to unlimited.
+func MaxRowGroupBytes(size int64) WriterOption {
+	if size <= 0 {
+		size = DefaultMaxRowGroupBytes
+	}
+	return writerOption(func(config *WriterConfig) { config.MaxRowGroupBytes = size })
+}
+
 // CreatedBy creates a configuration option which sets the name of the
 // application that created a parquet file.
 //
@@ -576,6 +1022,15 @@ func DataPageStatistics(enabled bool) WriterOption {
 	return writerOption(func(config *WriterConfig) { config.DataPageStatistics = enabled })
 }
 
+// PageChecksums creates a configuration option which defines whether the CRC32
+// checksums of data and dictionary pages are computed and written to the page
+// headers, allowing readers to detect corrupted pages.
+//
+// Defaults to true.
+func PageChecksums(enabled bool) WriterOption {
+	return writerOption(func(config *WriterConfig) { config.SkipPageChecksums = !enabled })
+}
+
 // KeyValueMetadata creates a configuration option which adds key/value metadata
 // to add to the metadata of parquet files.
 //
@@ -633,6 +1088,135 @@ func SkipPageBounds(path ...string) WriterOption {
 	return writerOption(func(config *WriterConfig) { config.SkipPageBounds = append(config.SkipPageBounds, path) })
 }
 
+// DictionaryMaxBytes configures the maximum size of dictionaries of column
+// chunks written with dictionary encoding.
+//
+// When the dictionary of a column chunk grows beyond this limit, the writer
+// stops adding values to it and encodes the remaining pages of the column
+// chunk with PLAIN encoding. The dictionary is still written at the beginning
+// of the column chunk to decode the pages that were written before reaching
+// the limit, and dictionary encoding resumes with the next row group.
+//
+// Defaults to 1MiB.
+func DictionaryMaxBytes(size int64) WriterOption {
+	return writerOption(func(config *WriterConfig) { config.DictionaryMaxBytes = size })
+}
+
+// ColumnDictionaryLimit overrides the dictionary size limit configured by
+// DictionaryMaxBytes for the column at the given path, and the encoding of
+// pages written after the limit was reached. A nil fallback encoding selects
+// PLAIN encoding, or the encoding selected by the writer when AdaptiveEncoding
+// is enabled.
+//
+// This option is additive, it may be used multiple times to configure limits
+// of multiple columns.
+func ColumnDictionaryLimit(maxBytes int64, fallback encoding.Encoding, path ...string) WriterOption {
+	limit := DictionaryLimit{Path: path, MaxBytes: maxBytes, Fallback: fallback}
+	return writerOption(func(config *WriterConfig) {
+		config.DictionaryLimits = append(config.DictionaryLimits, limit)
+	})
+}
+
+// AdaptiveEncoding configures how writers select the encoding of columns that
+// have no explicit encoding in their schema.
+//
+// When enabled, the writer encodes the first page of each column chunk with
+// all the encodings supported by the column type among PLAIN, RLE_DICTIONARY,
+// DELTA_BINARY_PACKED, DELTA_LENGTH_BYTE_ARRAY, DELTA_BYTE_ARRAY, and
+// BYTE_STREAM_SPLIT, and uses the one producing the smallest output for the
+// rest of the column chunk. The selection is made again for each row group.
+// When dictionary encoding is selected, the best alternative encoding is used
+// as fallback if the dictionary exceeds its size limit.
+//
+// Defaults to AdaptiveEncodingDisabled.
+func AdaptiveEncoding(mode AdaptiveEncodingMode) WriterOption {
+	return writerOption(func(config *WriterConfig) { config.AdaptiveEncoding = mode })
+}
+
+// WriteConcurrency configures the number of goroutines that writers use to
+// encode and compress the pages of different columns in parallel.
+//
+// The data pages which reach their size or row count limit while rows are
+// written, and the last data pages and dictionary pages of column chunks when
+// row groups are flushed, are encoded concurrently; the layout of the files is
+// the same regardless of the concurrency. Each column of writers configured
+// with a concurrency greater than one uses its own buffers to encode pages,
+// which increases memory usage for schemas with many columns.
+//
+// Defaults to 1.
+func WriteConcurrency(workers int) WriterOption {
+	return writerOption(func(config *WriterConfig) { config.WriteConcurrency = workers })
+}
+
+// FileEncryption is a writer option which enables parquet modular encryption
+// of the files produced by the writer, applying the given encryption options.
+//
+// At least the footer key must be configured with EncryptionWithFooterKey.
+func FileEncryption(options ...EncryptionOption) WriterOption {
+	options = append([]EncryptionOption{}, options...)
+	return writerOption(func(config *WriterConfig) {
+		if config.Encryption == nil {
+			config.Encryption = DefaultEncryptionConfig()
+		}
+		config.Encryption.Apply(options...)
+	})
+}
+
+// EncryptionWithFooterKey creates a configuration option which sets the key
+// used to encrypt the footer of parquet files, and the columns which were not
+// configured with their own key. The key metadata is stored in the file to
+// help readers retrieve the key, it may be nil.
+//
+// Keys must be 16, 24, or 32 bytes long to select AES-128, AES-192, or AES-256.
+func EncryptionWithFooterKey(key, keyMetadata []byte) EncryptionOption {
+	return encryptionOption(func(config *EncryptionConfig) {
+		config.FooterKey = key
+		config.FooterKeyMetadata = keyMetadata
+	})
+}
+
+// EncryptionWithColumnKey creates a configuration option which encrypts the
+// column at the given path with its own key. When key is nil, the column is
+// encrypted with the footer key.
+//
+// This option is additive, it may be used multiple times to configure keys of
+// multiple columns. Note that once at least one column is configured, the
+// columns which were not configured are written in plaintext.
+func EncryptionWithColumnKey(key, keyMetadata []byte, path ...string) EncryptionOption {
+	column := ColumnEncryptionKey{Path: path, Key: key, KeyMetadata: keyMetadata}
+	return encryptionOption(func(config *EncryptionConfig) {
+		config.ColumnKeys = append(config.ColumnKeys, column)
+	})
+}
+
+// EncryptionWithAlgorithm creates a configuration option which selects the
+// algorithm used to encrypt parquet files.
+//
+// Defaults to AESGCMV1.
+func EncryptionWithAlgorithm(algorithm EncryptionAlgorithm) EncryptionOption {
+	return encryptionOption(func(config *EncryptionConfig) { config.Algorithm = algorithm })
+}
+
+// EncryptionWithAADPrefix creates a configuration option which sets the AAD
+// prefix of encrypted parquet files. The prefix is stored in the file unless
+// supply is true, in which case readers must supply it with FileAADPrefix.
+func EncryptionWithAADPrefix(prefix []byte, supply bool) EncryptionOption {
+	return encryptionOption(func(config *EncryptionConfig) {
+		config.AADPrefix = prefix
+		config.SupplyAADPrefix = supply
+	})
+}
+
+// EncryptionWithPlaintextFooter creates a configuration option which leaves
+// the footer of parquet files unencrypted, allowing legacy readers to read the
+// unencrypted columns. The footer is signed with the footer key so readers
+// holding the key can verify its integrity.
+//
+// Defaults to false.
+func EncryptionWithPlaintextFooter(enabled bool) EncryptionOption {
+	return encryptionOption(func(config *EncryptionConfig) { config.PlaintextFooter = enabled })
+}
+
 // ColumnBufferCapacity creates a configuration option which defines the size of
 // row group column buffers.
 //
@@ -702,6 +1286,10 @@ type sortingOption func(*SortingConfig)
 
 func (opt sortingOption) ConfigureSorting(config *SortingConfig) { opt(config) }
 
+type encryptionOption func(*EncryptionConfig)
+
+func (opt encryptionOption) ConfigureEncryption(config *EncryptionConfig) { opt(config) }
+
 func coalesceBool(i1, i2 bool) bool {
 	return i1 || i2
 }
@@ -741,6 +1329,27 @@ func coalesceBufferPool(p1, p2 BufferPool) BufferPool {
 	return p2
 }
 
+func coalescePredicate(p1, p2 Predicate) Predicate {
+	if p1 != nil {
+		return p1
+	}
+	return p2
+}
+
+func coalesceColumnPaths(p1, p2 [][]string) [][]string {
+	if p1 != nil {
+		return p1
+	}
+	return p2
+}
+
+func coalesceRowSelection(s1, s2 RowSelection) RowSelection {
+	if s1 != nil {
+		return s1
+	}
+	return s2
+}
+
 func coalesceSchema(s1, s2 *Schema) *Schema {
 	if s1 != nil {
 		return s1
@@ -748,6 +1357,13 @@ func coalesceSchema(s1, s2 *Schema) *Schema {
 	return s2
 }
 
+func coalesceKeyRetriever(k1, k2 KeyRetriever) KeyRetriever {
+	if k1 != nil {
+		return k1
+	}
+	return k2
+}
+
 func coalesceSortingColumns(s1, s2 []SortingColumn) []SortingColumn {
 	if s1 != nil {
 		return s1
@@ -763,6 +1379,13 @@ func coalesceSortingConfig(c1, c2 SortingConfig) SortingConfig {
 	}
 }
 
+func coalesceEncryptionConfig(c1, c2 *EncryptionConfig) *EncryptionConfig {
+	if c1 != nil {
+		return c1
+	}
+	return c2
+}
+
 func coalesceBloomFilters(f1, f2 []BloomFilterColumn) []BloomFilterColumn {
 	if f1 != nil {
 		return f1
@@ -800,6 +1423,14 @@ func validateOneOfInt(optionName string, optionValue int, supportedValues ...int
 	return errorInvalidOptionValue(optionName, optionValue)
 }
 
+func validateEncryptionKey(optionName string, key []byte) error {
+	switch len(key) {
+	case 16, 24, 32:
+		return nil
+	}
+	return fmt.Errorf("invalid option value: %s: encryption keys must be 16, 24, or 32 bytes long but got %d", optionName, len(key))
+}
+
 func validateNotNil(optionName string, optionValue interface{}) error {
 	if optionValue != nil {
 		return nil
@@ -853,4 +1484,6 @@ var (
 	_ WriterOption   = (*WriterConfig)(nil)
 	_ RowGroupOption = (*RowGroupConfig)(nil)
 	_ SortingOption  = (*SortingConfig)(nil)
+
+	_ EncryptionOption = (*EncryptionConfig)(nil)
 )
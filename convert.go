@@ -405,14 +405,17 @@ func ConvertRowGroup(rowGroup RowGroup, conv Conversion) RowGroup {
 		// Columns of the source row group which do not exist in the target are
 		// masked to prevent loading unneeded pages when reading rows from the
 		// converted row group.
-		rowGroup: maskMissingRowGroupColumns(rowGroup, len(columns), conv),
+		rowGroup: maskMissingRowGroupColumns(rowGroup, len(columns), conv, nil),
 		columns:  columns,
 		sorting:  sorting,
 		conv:     conv,
 	}
 }
 
-func maskMissingRowGroupColumns(r RowGroup, numColumns int, conv Conversion) RowGroup {
+// maskMissingRowGroupColumns returns a view of r where the columns which are
+// not part of the conversion are replaced with missing columns, unless they
+// are flagged in keep.
+func maskMissingRowGroupColumns(r RowGroup, numColumns int, conv Conversion, keep []bool) RowGroup {
 	rowGroupColumns := r.ColumnChunks()
 	columns := make([]ColumnChunk, len(rowGroupColumns))
 	missing := make([]missingColumnChunk, len(columns))
@@ -429,7 +432,11 @@ func maskMissingRowGroupColumns(r RowGroup, numColumns int, conv Conversion) Row
 	}
 
 	for i := range columns {
-		columns[i] = &missing[i]
+		if i < len(keep) && keep[i] {
+			columns[i] = rowGroupColumns[i]
+		} else {
+			columns[i] = &missing[i]
+		}
 	}
 
 	for i := 0; i < numColumns; i++ {
@@ -784,10 +791,18 @@ func convertFloatToFixedLenByteArray(v Value, size int) (Value, error) {
 	return v.convertToFixedLenByteArray(c), nil
 }
 
+func convertFloatToFloat16(v Value) (Value, error) {
+	return v.convertToFixedLenByteArray(appendFloat16(nil, v.float())), nil
+}
+
 func convertFloatToString(v Value) (Value, error) {
 	return v.convertToByteArray(strconv.AppendFloat(nil, float64(v.float()), 'g', -1, 32)), nil
 }
 
+func convertDoubleToFloat16(v Value) (Value, error) {
+	return v.convertToFixedLenByteArray(appendFloat16(nil, float32(v.double()))), nil
+}
+
 func convertDoubleToBoolean(v Value) (Value, error) {
 	return v.convertToBoolean(v.double() != 0), nil
 }
@@ -858,6 +873,18 @@ func convertByteArrayToFloat(v Value) (Value, error) {
 	return v.convertToFloat(math.Float32frombits(binary.LittleEndian.Uint32(b))), nil
 }
 
+func convertFloat16ToFloat(v Value) (Value, error) {
+	b := make([]byte, float16Size)
+	copy(b, v.byteArray())
+	return v.convertToFloat(float16FromBytes(b)), nil
+}
+
+func convertFloat16ToDouble(v Value) (Value, error) {
+	b := make([]byte, float16Size)
+	copy(b, v.byteArray())
+	return v.convertToDouble(float64(float16FromBytes(b))), nil
+}
+
 func convertByteArrayToDouble(v Value) (Value, error) {
 	b := make([]byte, 8)
 	copy(b, v.byteArray())
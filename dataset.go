@@ -0,0 +1,485 @@
+package parquet
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Dataset represents a collection of parquet files read from a file system.
+//
+// Directories of the dataset which are named after the Hive convention of
+// key=value are interpreted as partitions, the keys are added to the dataset
+// schema as optional string columns, and the values are exposed as the values
+// of these columns in all the rows of the files in the directories.
+//
+// The schemas of the files are merged into a single schema which contains all
+// the columns found in the files. Rows of files missing some of the columns
+// have null values in those columns. When files have different types for the
+// same column, the type of the first file is used, and values of the other
+// files are converted to it.
+//
+// Dataset implements the RowGroup interface. Rows of the dataset can be read
+// with NewGenericDatasetReader, for example:
+//
+//	dataset, err := parquet.OpenDataset(os.DirFS("/data"), "events")
+//	if err != nil {
+//		...
+//	}
+//	defer dataset.Close()
+//
+//	reader, err := parquet.NewGenericDatasetReader[Event](dataset)
+//	if err != nil {
+//		...
+//	}
+//
+// Column chunks of the dataset expose the values of the files as they were
+// written, without applying type conversions. Programs which need to see
+// converted values must read rows of the dataset.
+type Dataset struct {
+	concatRowGroup
+	files      []datasetFile
+	paths      []string
+	partitions []string
+	closers    []io.Closer
+}
+
+type datasetFile struct {
+	file       *File
+	partitions map[string]*string
+}
+
+// OpenDataset opens all the parquet files found under root in fsys, which are
+// the files with the ".parquet" extension. Other files, and files and
+// directories with names starting with "_" or "." are ignored.
+//
+// The file options are applied when opening each file of the dataset.
+func OpenDataset(fsys fs.FS, root string, options ...FileOption) (*Dataset, error) {
+	d := new(Dataset)
+
+	err := fs.WalkDir(fsys, root, func(filePath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if filePath != root && isHiddenDatasetFile(entry.Name()) {
+			if entry.IsDir() {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if entry.IsDir() || path.Ext(entry.Name()) != ".parquet" {
+			return nil
+		}
+
+		partitions, err := parseDatasetPartitions(strings.TrimPrefix(path.Dir(filePath), root))
+		if err != nil {
+			return fmt.Errorf("%s: %w", filePath, err)
+		}
+		f, err := d.openFile(fsys, filePath, options)
+		if err != nil {
+			return fmt.Errorf("%s: %w", filePath, err)
+		}
+		for _, key := range partitions.keys {
+			if !slices.Contains(d.partitions, key) {
+				d.partitions = append(d.partitions, key)
+			}
+		}
+		d.paths = append(d.paths, filePath)
+		d.files = append(d.files, datasetFile{file: f, partitions: partitions.values})
+		return nil
+	})
+	if err != nil {
+		d.Close()
+		return nil, err
+	}
+
+	var rootNode Node
+	for _, f := range d.files {
+		if rootNode == nil {
+			rootNode = f.file.Schema()
+		} else if !nodesAreEqual(rootNode, f.file.Schema()) {
+			rootNode = mergeDatasetNodes(rootNode, f.file.Schema())
+		}
+	}
+	if rootNode == nil {
+		d.schema = NewSchema("", Group{})
+		d.init()
+		return d, nil
+	}
+
+	// Partition keys which are also columns of the files are not added to the
+	// schema since the files already hold their values.
+	partitions := d.partitions[:0]
+	for _, key := range d.partitions {
+		if fieldByName(rootNode, key) == nil {
+			partitions = append(partitions, key)
+		}
+	}
+	d.partitions = partitions
+
+	if len(d.partitions) == 0 {
+		if schema, ok := rootNode.(*Schema); ok {
+			d.schema = schema
+		}
+	} else {
+		fields := slices.Clone(rootNode.Fields())
+		for _, key := range d.partitions {
+			fields = append(fields, &groupField{Node: Optional(String()), name: key})
+		}
+		rootNode = &datasetGroup{fields: fields}
+	}
+	if d.schema == nil {
+		d.schema = NewSchema(d.files[0].file.Schema().Name(), rootNode)
+	}
+
+	if d.rowGroups, err = d.convert(d.schema); err != nil {
+		d.Close()
+		return nil, err
+	}
+	d.init()
+	return d, nil
+}
+
+// convert returns the row groups of the dataset files converted to schema.
+func (d *Dataset) convert(schema *Schema) ([]RowGroup, error) {
+	var rowGroups []RowGroup
+
+	for _, f := range d.files {
+		conv, err := Convert(schema, f.file.Schema())
+		if err != nil {
+			return nil, err
+		}
+
+		// Partition columns which are not part of the schema are ignored.
+		var values []Value
+		var leaves []LeafColumn
+		for _, key := range d.partitions {
+			leaf, ok := schema.Lookup(key)
+			if !ok {
+				continue
+			}
+			value := NullValue().Level(0, 0, leaf.ColumnIndex)
+			if v := f.partitions[key]; v != nil {
+				converted, err := leaf.Node.Type().ConvertValue(ByteArrayValue([]byte(*v)), String().Type())
+				if err != nil {
+					return nil, fmt.Errorf("converting value of partition %s=%s: %w", key, *v, err)
+				}
+				value = converted.Level(0, leaf.MaxDefinitionLevel, leaf.ColumnIndex)
+			}
+			values = append(values, value)
+			leaves = append(leaves, leaf)
+		}
+		if len(values) > 0 {
+			conv = &partitionConversion{Conversion: conv, values: values}
+		}
+
+		for _, rowGroup := range f.file.RowGroups() {
+			if _, ok := conv.(identity); !ok {
+				rowGroup = ConvertRowGroup(rowGroup, conv)
+			}
+			if len(values) > 0 {
+				columns := append([]ColumnChunk{}, rowGroup.ColumnChunks()...)
+				for i, leaf := range leaves {
+					columns[leaf.ColumnIndex] = &partitionColumnChunk{
+						typ:                leaf.Node.Type(),
+						value:              values[i],
+						numRows:            rowGroup.NumRows(),
+						maxDefinitionLevel: byte(leaf.MaxDefinitionLevel),
+					}
+				}
+				rowGroup = &partitionRowGroup{RowGroup: rowGroup, columns: columns}
+			}
+			rowGroups = append(rowGroups, rowGroup)
+		}
+	}
+	return rowGroups, nil
+}
+
+// NewGenericDatasetReader constructs a reader of the rows of a dataset.
+//
+// When the reader has a schema different from the dataset schema, rows of the
+// files are converted directly to the reader schema.
+func NewGenericDatasetReader[T any](d *Dataset, options ...ReaderOption) (*GenericReader[T], error) {
+	c, err := NewReaderConfig(options...)
+	if err != nil {
+		return nil, err
+	}
+	schema := c.Schema
+	if schema == nil {
+		if t := typeOf[T](); t == nil {
+			schema = d.schema
+		} else {
+			schema = schemaWithShreddedVariantsOf(schemaOf(dereference(t)), d.schema)
+		}
+	}
+	if len(c.Projection) > 0 {
+		// Projecting before converting the files of the dataset avoids
+		// opening column chunks of columns that are not projected. The filter
+		// is evaluated on the converted row groups, which must then contain
+		// the columns it depends on; the reader projects them out.
+		projection := append(c.Projection[:len(c.Projection):len(c.Projection)], filterColumnPaths(schema, c.Filter)...)
+		if schema, err = projectSchema(schema, projection); err != nil {
+			return nil, err
+		}
+	}
+	options = append(options[:len(options):len(options)], schema)
+	if schema == d.schema {
+		return NewGenericRowGroupReader[T](d, options...), nil
+	}
+	rowGroups, err := d.convert(schema)
+	if err != nil {
+		return nil, err
+	}
+	return NewGenericRowGroupReader[T](newConcatRowGroup(schema, rowGroups), options...), nil
+}
+
+func (d *Dataset) openFile(fsys fs.FS, filePath string, options []FileOption) (*File, error) {
+	f, err := fsys.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	d.closers = append(d.closers, f)
+
+	s, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	r, ok := f.(io.ReaderAt)
+	if !ok {
+		// The file system does not support random access, the whole file is
+		// loaded in memory.
+		b, err := io.ReadAll(f)
+		if err != nil {
+			return nil, err
+		}
+		r = bytes.NewReader(b)
+	}
+	return OpenFile(r, s.Size(), options...)
+}
+
+// Close closes all the files of the dataset.
+func (d *Dataset) Close() error {
+	var errs []error
+	for _, c := range d.closers {
+		errs = append(errs, c.Close())
+	}
+	d.closers = nil
+	return errors.Join(errs...)
+}
+
+// Files returns the paths of the files in the dataset.
+func (d *Dataset) Files() []string { return d.paths }
+
+// PartitionColumns returns the names of the columns added to the schema from
+// the partitions of the dataset.
+func (d *Dataset) PartitionColumns() []string { return d.partitions }
+
+// RowGroups returns the row groups of all the files in the dataset, converted
+// to the dataset schema.
+func (d *Dataset) RowGroups() []RowGroup { return d.rowGroups }
+
+type partitionRowGroup struct {
+	RowGroup
+	columns []ColumnChunk
+}
+
+func (r *partitionRowGroup) ColumnChunks() []ColumnChunk { return r.columns }
+
+// partitionConversion is a conversion which sets the values of partition
+// columns after converting rows to the dataset schema.
+type partitionConversion struct {
+	Conversion
+	values []Value
+}
+
+func (c *partitionConversion) Convert(rows []Row) (int, error) {
+	n, err := c.Conversion.Convert(rows)
+	for _, row := range rows[:n] {
+		for i, v := range row {
+			for _, value := range c.values {
+				if v.Column() == value.Column() {
+					row[i] = value
+				}
+			}
+		}
+	}
+	return n, err
+}
+
+func (c *partitionConversion) Column(i int) int {
+	for _, value := range c.values {
+		if value.Column() == i {
+			return -1
+		}
+	}
+	return c.Conversion.Column(i)
+}
+
+// partitionColumnChunk is a column chunk holding the same value for all the
+// rows of a row group. The values are only materialized when the column chunk
+// is first read, and shared by all the readers of the column chunk.
+type partitionColumnChunk struct {
+	typ                Type
+	value              Value
+	numRows            int64
+	maxDefinitionLevel byte
+
+	once   sync.Once
+	column ColumnBuffer
+}
+
+func (c *partitionColumnChunk) buffer() ColumnBuffer {
+	c.once.Do(func() {
+		buffer := c.typ.NewColumnBuffer(c.value.Column(), int(c.numRows))
+		if c.maxDefinitionLevel > 0 {
+			buffer = newOptionalColumnBuffer(buffer, c.maxDefinitionLevel, nullsGoLast)
+		}
+		values := make([]Value, min(c.numRows, defaultValueBufferSize))
+		for i := range values {
+			values[i] = c.value
+		}
+		for remain := c.numRows; remain > 0; remain -= int64(len(values)) {
+			buffer.WriteValues(values[:min(remain, int64(len(values)))])
+		}
+		c.column = buffer
+	})
+	return c.column
+}
+
+func (c *partitionColumnChunk) Type() Type                        { return c.typ }
+func (c *partitionColumnChunk) Column() int                       { return c.value.Column() }
+func (c *partitionColumnChunk) Pages() Pages                      { return onePage(c.buffer().Page()) }
+func (c *partitionColumnChunk) ColumnIndex() (ColumnIndex, error) { return c.buffer().ColumnIndex() }
+func (c *partitionColumnChunk) OffsetIndex() (OffsetIndex, error) { return c.buffer().OffsetIndex() }
+func (c *partitionColumnChunk) BloomFilter() BloomFilter          { return nil }
+func (c *partitionColumnChunk) NumValues() int64                  { return c.numRows }
+
+type datasetPartitions struct {
+	keys   []string
+	values map[string]*string
+}
+
+// parseDatasetPartitions parses the key=value segments of a directory path,
+// the values of the default Hive partition are represented by nil pointers.
+func parseDatasetPartitions(dir string) (datasetPartitions, error) {
+	partitions := datasetPartitions{values: make(map[string]*string)}
+	for _, segment := range strings.Split(dir, "/") {
+		key, value, ok := strings.Cut(segment, "=")
+		if !ok {
+			continue
+		}
+		key, err := unescapePartitionValue(key)
+		if err != nil {
+			return partitions, err
+		}
+		if _, exists := partitions.values[key]; exists {
+			return partitions, fmt.Errorf("partition key %q repeated in path", key)
+		}
+		partitions.keys = append(partitions.keys, key)
+		partitions.values[key] = nil
+		if value != hiveDefaultPartition {
+			value, err := unescapePartitionValue(value)
+			if err != nil {
+				return partitions, err
+			}
+			partitions.values[key] = &value
+		}
+	}
+	return partitions, nil
+}
+
+// unescapePartitionValue is the inverse of escapePartitionValue.
+func unescapePartitionValue(s string) (string, error) {
+	if strings.IndexByte(s, '%') < 0 {
+		return s, nil
+	}
+	b := make([]byte, 0, len(s))
+	for i := 0; i < len(s); i++ {
+		if s[i] != '%' {
+			b = append(b, s[i])
+			continue
+		}
+		if i+3 > len(s) {
+			return "", fmt.Errorf("invalid escape sequence in partition value %q", s)
+		}
+		c, err := strconv.ParseUint(s[i+1:i+3], 16, 8)
+		if err != nil {
+			return "", fmt.Errorf("invalid escape sequence in partition value %q", s)
+		}
+		b = append(b, byte(c))
+		i += 2
+	}
+	return string(b), nil
+}
+
+func isHiddenDatasetFile(name string) bool {
+	return strings.HasPrefix(name, "_") || strings.HasPrefix(name, ".")
+}
+
+// mergeDatasetNodes merges the fields of two nodes. Fields which do not exist
+// in one of the nodes become optional in the result.
+func mergeDatasetNodes(node1, node2 Node) Node {
+	var merged Node
+	switch {
+	case nodesAreEqual(node1, node2), node1.Leaf(), node2.Leaf():
+		merged = node1
+	default:
+		group := new(datasetGroup)
+		for _, field := range node1.Fields() {
+			if other := fieldByName(node2, field.Name()); other == nil {
+				group.add(field.Name(), optionalDatasetNode(field))
+			} else {
+				group.add(field.Name(), mergeDatasetNodes(field, other))
+			}
+		}
+		for _, field := range node2.Fields() {
+			if fieldByName(node1, field.Name()) == nil {
+				group.add(field.Name(), optionalDatasetNode(field))
+			}
+		}
+		merged = group
+	}
+
+	switch {
+	case node1.Repeated() || node2.Repeated():
+		return Repeated(merged)
+	case node1.Optional() || node2.Optional():
+		return Optional(merged)
+	default:
+		return Required(merged)
+	}
+}
+
+// datasetGroup is a group node which retains the order of its fields, so the
+// merged schema of datasets keeps the order of the columns of their files,
+// unlike Group which sorts the fields by name.
+type datasetGroup struct {
+	Group
+	fields []Field
+}
+
+func (g *datasetGroup) add(name string, node Node) {
+	g.fields = append(g.fields, &groupField{Node: node, name: name})
+}
+
+func (g *datasetGroup) Fields() []Field { return g.fields }
+
+func (g *datasetGroup) GoType() reflect.Type { return goTypeOfGroup(g) }
+
+func (g *datasetGroup) String() string { return sprint("", g) }
+
+func optionalDatasetNode(node Node) Node {
+	if node.Required() {
+		return Optional(node)
+	}
+	return node
+}
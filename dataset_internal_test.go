@@ -0,0 +1,34 @@
+package parquet
+
+import "testing"
+
+func TestPartitionColumnChunkBuffer(t *testing.T) {
+	c := &partitionColumnChunk{
+		typ:                String().Type(),
+		value:              ValueOf("2024-01-01").Level(0, 1, 0),
+		numRows:            3000,
+		maxDefinitionLevel: 1,
+	}
+	if c.buffer() != c.buffer() {
+		t.Error("the values of the column chunk were materialized more than once")
+	}
+
+	pages := c.Pages()
+	defer pages.Close()
+	page, err := pages.ReadPage()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if page.NumRows() != c.numRows || page.NumNulls() != 0 {
+		t.Fatalf("wrong page: rows=%d nulls=%d", page.NumRows(), page.NumNulls())
+	}
+	values := make([]Value, c.numRows)
+	if n, _ := page.Values().ReadValues(values); n != len(values) {
+		t.Fatalf("wrong number of values: want=%d got=%d", len(values), n)
+	}
+	for i, v := range values {
+		if v.String() != "2024-01-01" {
+			t.Fatalf("wrong value at index %d: %v", i, v)
+		}
+	}
+}
@@ -0,0 +1,195 @@
+package parquet_test
+
+import (
+	"bytes"
+	"io"
+	"reflect"
+	"sort"
+	"testing"
+	"testing/fstest"
+
+	"github.com/parquet-go/parquet-go"
+)
+
+func writeDatasetFile[T any](t *testing.T, fsys fstest.MapFS, name string, rows []T) {
+	t.Helper()
+	b := new(bytes.Buffer)
+	if err := parquet.Write(b, rows); err != nil {
+		t.Fatal(err)
+	}
+	fsys[name] = &fstest.MapFile{Data: b.Bytes()}
+}
+
+func TestDataset(t *testing.T) {
+	type rowV1 struct {
+		ID   int64  `parquet:"id"`
+		Name string `parquet:"name"`
+	}
+	type rowV2 struct {
+		ID    int64  `parquet:"id"`
+		Name  string `parquet:"name"`
+		Score int32  `parquet:"score"`
+	}
+	type datasetRow struct {
+		ID     int64   `parquet:"id"`
+		Name   string  `parquet:"name"`
+		Score  *int32  `parquet:"score,optional"`
+		Date   *string `parquet:"date,optional"`
+		Tenant *string `parquet:"tenant,optional"`
+	}
+
+	fsys := fstest.MapFS{
+		"data/_SUCCESS":          &fstest.MapFile{},
+		"data/.hidden/x.parquet": &fstest.MapFile{Data: []byte("not a parquet file")},
+		"data/README.md":         &fstest.MapFile{Data: []byte("not a parquet file")},
+	}
+	writeDatasetFile(t, fsys, "data/date=2024-01-01/tenant=acme/part-00000.parquet", []rowV1{
+		{ID: 1, Name: "one"},
+		{ID: 2, Name: "two"},
+	})
+	writeDatasetFile(t, fsys, "data/date=2024-01-01/tenant=a%2Fb/part-00000.parquet", []rowV2{
+		{ID: 3, Name: "three", Score: 30},
+	})
+	writeDatasetFile(t, fsys, "data/date=2024-01-02/tenant=__HIVE_DEFAULT_PARTITION__/part-00000.parquet", []rowV2{
+		{ID: 4, Name: "four", Score: 40},
+		{ID: 5, Name: "five", Score: 50},
+	})
+
+	dataset, err := parquet.OpenDataset(fsys, "data")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dataset.Close()
+
+	if len(dataset.Files()) != 3 {
+		t.Errorf("wrong number of files: %q", dataset.Files())
+	}
+	if partitions := dataset.PartitionColumns(); !reflect.DeepEqual(partitions, []string{"date", "tenant"}) {
+		t.Errorf("wrong partition columns: %q", partitions)
+	}
+	if columns := dataset.Schema().Columns(); !reflect.DeepEqual(columns, [][]string{{"id"}, {"name"}, {"score"}, {"date"}, {"tenant"}}) {
+		t.Errorf("wrong columns: %q", columns)
+	}
+	if numRows := dataset.NumRows(); numRows != 5 {
+		t.Errorf("wrong number of rows: want=5 got=%d", numRows)
+	}
+
+	reader, err := parquet.NewGenericDatasetReader[datasetRow](dataset)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rows := make([]datasetRow, 10)
+	n, err := reader.Read(rows)
+	if err != io.EOF {
+		t.Fatalf("expected io.EOF but got %v", err)
+	}
+	rows = rows[:n]
+	sort.Slice(rows, func(i, j int) bool { return rows[i].ID < rows[j].ID })
+
+	ptr := func(v string) *string { return &v }
+	score := func(v int32) *int32 { return &v }
+	want := []datasetRow{
+		{ID: 1, Name: "one", Date: ptr("2024-01-01"), Tenant: ptr("acme")},
+		{ID: 2, Name: "two", Date: ptr("2024-01-01"), Tenant: ptr("acme")},
+		{ID: 3, Name: "three", Score: score(30), Date: ptr("2024-01-01"), Tenant: ptr("a/b")},
+		{ID: 4, Name: "four", Score: score(40), Date: ptr("2024-01-02")},
+		{ID: 5, Name: "five", Score: score(50), Date: ptr("2024-01-02")},
+	}
+	if !reflect.DeepEqual(rows, want) {
+		t.Errorf("wrong rows:\nwant: %+v\ngot:  %+v", want, rows)
+	}
+
+	// The values of partition columns are also exposed by the column chunks
+	// of the dataset.
+	leaf, _ := dataset.Schema().Lookup("tenant")
+	pages := dataset.ColumnChunks()[leaf.ColumnIndex].Pages()
+	defer pages.Close()
+	var tenants []string
+	for {
+		page, err := pages.ReadPage()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		values := make([]parquet.Value, page.NumValues())
+		page.Values().ReadValues(values)
+		for _, v := range values {
+			tenants = append(tenants, v.String())
+		}
+	}
+	sort.Strings(tenants)
+	if want := []string{"<null>", "<null>", "a/b", "acme", "acme"}; !reflect.DeepEqual(tenants, want) {
+		t.Errorf("wrong values of partition column chunks: want=%q got=%q", want, tenants)
+	}
+}
+
+func TestDatasetFieldOrder(t *testing.T) {
+	type rowV1 struct {
+		Name string `parquet:"name"`
+		ID   int64  `parquet:"id"`
+	}
+	type rowV2 struct {
+		Name  string `parquet:"name"`
+		Score int32  `parquet:"score"`
+		ID    int64  `parquet:"id"`
+	}
+
+	fsys := fstest.MapFS{}
+	writeDatasetFile(t, fsys, "data/region=eu/part-00000.parquet", []rowV1{{Name: "one", ID: 1}})
+	writeDatasetFile(t, fsys, "data/region=us/part-00000.parquet", []rowV2{{Name: "two", Score: 2, ID: 2}})
+
+	dataset, err := parquet.OpenDataset(fsys, "data")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dataset.Close()
+
+	// The columns retain the order of the files, columns which only exist in
+	// the following files and partition columns are added after them.
+	want := [][]string{{"name"}, {"id"}, {"score"}, {"region"}}
+	if columns := dataset.Schema().Columns(); !reflect.DeepEqual(columns, want) {
+		t.Errorf("wrong columns: want=%q got=%q", want, columns)
+	}
+}
+
+func TestDatasetRowsSeekToRow(t *testing.T) {
+	type row struct {
+		ID int64 `parquet:"id"`
+	}
+
+	fsys := fstest.MapFS{}
+	writeDatasetFile(t, fsys, "a.parquet", []row{{0}, {1}, {2}})
+	writeDatasetFile(t, fsys, "b.parquet", []row{{3}, {4}})
+
+	dataset, err := parquet.OpenDataset(fsys, ".")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dataset.Close()
+
+	rows := dataset.Rows()
+	defer rows.Close()
+
+	if err := rows.SeekToRow(2); err != nil {
+		t.Fatal(err)
+	}
+	buf := make([]parquet.Row, 10)
+	var ids []int64
+	for {
+		n, err := rows.ReadRows(buf)
+		for _, r := range buf[:n] {
+			ids = append(ids, r[0].Int64())
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if want := []int64{2, 3, 4}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("wrong rows after seek: want=%v got=%v", want, ids)
+	}
+}
@@ -0,0 +1,38 @@
+package deprecated
+
+import "encoding/binary"
+
+// Duration is an implementation of the deprecated INTERVAL parquet converted
+// type.
+//
+// An interval is made of three independent components, a number of months, a
+// number of days, and a number of milliseconds. The components cannot be
+// normalized into a single unit since the duration of months and days varies,
+// which is also why intervals have no defined sort order.
+type Duration struct {
+	Months       uint32
+	Days         uint32
+	Milliseconds uint32
+}
+
+// IntervalSize is the size of INTERVAL values in bytes.
+const IntervalSize = 12
+
+// MakeDuration decodes a Duration from its 12 bytes representation, made of
+// the three little-endian 32 bits components.
+func MakeDuration(b []byte) Duration {
+	_ = b[:IntervalSize]
+	return Duration{
+		Months:       binary.LittleEndian.Uint32(b[0:]),
+		Days:         binary.LittleEndian.Uint32(b[4:]),
+		Milliseconds: binary.LittleEndian.Uint32(b[8:]),
+	}
+}
+
+// Append appends the 12 bytes representation of d to b.
+func (d Duration) Append(b []byte) []byte {
+	b = binary.LittleEndian.AppendUint32(b, d.Months)
+	b = binary.LittleEndian.AppendUint32(b, d.Days)
+	b = binary.LittleEndian.AppendUint32(b, d.Milliseconds)
+	return b
+}
@@ -0,0 +1,21 @@
+package deprecated_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/parquet-go/parquet-go/deprecated"
+)
+
+func TestDuration(t *testing.T) {
+	d := deprecated.Duration{Months: 1, Days: 2, Milliseconds: 0x01020304}
+	b := d.Append(nil)
+
+	want := []byte{1, 0, 0, 0, 2, 0, 0, 0, 4, 3, 2, 1}
+	if !bytes.Equal(b, want) {
+		t.Errorf("wrong encoding: want=%v got=%v", want, b)
+	}
+	if got := deprecated.MakeDuration(b); got != d {
+		t.Errorf("wrong decoding: want=%+v got=%+v", d, got)
+	}
+}
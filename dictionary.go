@@ -901,6 +901,30 @@ func (d *fixedLenByteArrayDictionary) Page() Page {
 	return &d.fixedLenByteArrayPage
 }
 
+type float16Dictionary struct{ fixedLenByteArrayDictionary }
+
+func newFloat16Dictionary(typ Type, columnIndex int16, numValues int32, values encoding.Values) *float16Dictionary {
+	return &float16Dictionary{*newFixedLenByteArrayDictionary(typ, columnIndex, numValues, values)}
+}
+
+func (d *float16Dictionary) Type() Type { return newIndexedType(d.typ, d) }
+
+func (d *float16Dictionary) Bounds(indexes []int32) (min, max Value) {
+	if len(indexes) > 0 {
+		bounds := float16Bounds{}
+		for _, i := range indexes {
+			bounds.observe(d.index(i))
+		}
+		min = d.makeValueBytes(bounds.min)
+		max = d.makeValueBytes(bounds.max)
+	}
+	return min, max
+}
+
+func (d *float16Dictionary) Page() Page {
+	return &float16Page{d.fixedLenByteArrayPage}
+}
+
 type uint32Dictionary struct {
 	uint32Page
 	table *hashprobe.Uint32Table
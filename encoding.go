@@ -42,7 +42,8 @@ var (
 	// DeltaByteArray is the delta byte array parquet encoding.
 	DeltaByteArray delta.ByteArrayEncoding
 
-	// ByteStreamSplit is an encoding for floating-point data.
+	// ByteStreamSplit is an encoding for floating-point, integer, and fixed
+	// length byte array data.
 	ByteStreamSplit bytestreamsplit.Encoding
 
 	// Table indexing the encodings supported by this package.
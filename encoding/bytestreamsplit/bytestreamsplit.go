@@ -8,6 +8,10 @@ import (
 
 // This encoder implements a version of the Byte Stream Split encoding as described
 // in https://github.com/apache/parquet-format/blob/master/Encodings.md#byte-stream-split-byte_stream_split--9
+//
+// The encoding supports FLOAT, DOUBLE, INT32, INT64 and FIXED_LEN_BYTE_ARRAY
+// values. Integers are split the same way as floating point values of the same
+// size, which shares the optimized implementations of the algorithms.
 type Encoding struct {
 	encoding.NotSupported
 }
@@ -32,6 +36,37 @@ func (e *Encoding) EncodeDouble(dst []byte, src []float64) ([]byte, error) {
 	return dst, nil
 }
 
+func (e *Encoding) EncodeInt32(dst []byte, src []int32) ([]byte, error) {
+	dst = resize(dst, 4*len(src))
+	encodeFloat(dst, unsafecast.Slice[byte](src))
+	return dst, nil
+}
+
+func (e *Encoding) EncodeInt64(dst []byte, src []int64) ([]byte, error) {
+	dst = resize(dst, 8*len(src))
+	encodeDouble(dst, unsafecast.Slice[byte](src))
+	return dst, nil
+}
+
+func (e *Encoding) EncodeFixedLenByteArray(dst []byte, src []byte, size int) ([]byte, error) {
+	if size <= 0 || size > encoding.MaxFixedLenByteArraySize {
+		return dst[:0], encoding.Error(e, encoding.ErrInvalidArgument)
+	}
+	if (len(src) % size) != 0 {
+		return dst[:0], encoding.ErrEncodeInvalidInputSize(e, "FIXED_LEN_BYTE_ARRAY", len(src))
+	}
+	dst = resize(dst, len(src))
+	switch size {
+	case 4:
+		encodeFloat(dst, src)
+	case 8:
+		encodeDouble(dst, src)
+	default:
+		encodeFixedLenByteArray(dst, src, size)
+	}
+	return dst, nil
+}
+
 func (e *Encoding) DecodeFloat(dst []float32, src []byte) ([]float32, error) {
 	if (len(src) % 4) != 0 {
 		return dst, encoding.ErrDecodeInvalidInputSize(e, "FLOAT", len(src))
@@ -50,6 +85,66 @@ func (e *Encoding) DecodeDouble(dst []float64, src []byte) ([]float64, error) {
 	return unsafecast.Slice[float64](buf), nil
 }
 
+func (e *Encoding) DecodeInt32(dst []int32, src []byte) ([]int32, error) {
+	if (len(src) % 4) != 0 {
+		return dst, encoding.ErrDecodeInvalidInputSize(e, "INT32", len(src))
+	}
+	buf := resize(unsafecast.Slice[byte](dst), len(src))
+	decodeFloat(buf, src)
+	return unsafecast.Slice[int32](buf), nil
+}
+
+func (e *Encoding) DecodeInt64(dst []int64, src []byte) ([]int64, error) {
+	if (len(src) % 8) != 0 {
+		return dst, encoding.ErrDecodeInvalidInputSize(e, "INT64", len(src))
+	}
+	buf := resize(unsafecast.Slice[byte](dst), len(src))
+	decodeDouble(buf, src)
+	return unsafecast.Slice[int64](buf), nil
+}
+
+func (e *Encoding) DecodeFixedLenByteArray(dst []byte, src []byte, size int) ([]byte, error) {
+	if size <= 0 || size > encoding.MaxFixedLenByteArraySize {
+		return dst, encoding.Error(e, encoding.ErrInvalidArgument)
+	}
+	if (len(src) % size) != 0 {
+		return dst, encoding.ErrDecodeInvalidInputSize(e, "FIXED_LEN_BYTE_ARRAY", len(src))
+	}
+	buf := resize(dst, len(src))
+	switch size {
+	case 4:
+		decodeFloat(buf, src)
+	case 8:
+		decodeDouble(buf, src)
+	default:
+		decodeFixedLenByteArray(buf, src, size)
+	}
+	return buf, nil
+}
+
+// encodeFixedLenByteArray is the generic version of the algorithm for values of
+// arbitrary sizes, the byte at index j of the i-th value is written to index i
+// of the j-th stream.
+func encodeFixedLenByteArray(dst, src []byte, size int) {
+	n := len(src) / size
+	for j := 0; j < size; j++ {
+		stream := dst[j*n : (j+1)*n]
+		for i := range stream {
+			stream[i] = src[i*size+j]
+		}
+	}
+}
+
+func decodeFixedLenByteArray(dst, src []byte, size int) {
+	n := len(src) / size
+	for j := 0; j < size; j++ {
+		stream := src[j*n : (j+1)*n]
+		for i, b := range stream {
+			dst[i*size+j] = b
+		}
+	}
+}
+
 func resize(buf []byte, size int) []byte {
 	if cap(buf) < size {
 		buf = make([]byte, size, 2*size)
@@ -1,6 +1,8 @@
 package bytestreamsplit_test
 
 import (
+	"bytes"
+	"fmt"
 	"testing"
 
 	"github.com/parquet-go/parquet-go/encoding/bytestreamsplit"
@@ -16,6 +18,14 @@ func FuzzEncodeDouble(f *testing.F) {
 	fuzz.EncodeDouble(f, new(bytestreamsplit.Encoding))
 }
 
+func FuzzEncodeInt32(f *testing.F) {
+	fuzz.EncodeInt32(f, new(bytestreamsplit.Encoding))
+}
+
+func FuzzEncodeInt64(f *testing.F) {
+	fuzz.EncodeInt64(f, new(bytestreamsplit.Encoding))
+}
+
 func TestEncodeFloat(t *testing.T) {
 	test.EncodeFloat(t, new(bytestreamsplit.Encoding), 0, 100)
 }
@@ -23,3 +33,55 @@ func TestEncodeFloat(t *testing.T) {
 func TestEncodeDouble(t *testing.T) {
 	test.EncodeDouble(t, new(bytestreamsplit.Encoding), 0, 100)
 }
+
+func TestEncodeInt32(t *testing.T) {
+	test.EncodeInt32(t, new(bytestreamsplit.Encoding), 0, 100, 32)
+}
+
+func TestEncodeInt64(t *testing.T) {
+	test.EncodeInt64(t, new(bytestreamsplit.Encoding), 0, 100, 64)
+}
+
+func TestEncodeFixedLenByteArray(t *testing.T) {
+	e := new(bytestreamsplit.Encoding)
+
+	for _, size := range []int{1, 2, 3, 4, 8, 12, 16} {
+		t.Run(fmt.Sprintf("size=%d", size), func(t *testing.T) {
+			for n := 0; n <= 100; n++ {
+				src := make([]byte, n*size)
+				for i := range src {
+					src[i] = byte(i)
+				}
+
+				buf, err := e.EncodeFixedLenByteArray(nil, src, size)
+				if err != nil {
+					t.Fatal(err)
+				}
+				// The j-th byte of the i-th value must be at index i of the
+				// j-th stream.
+				for i := 0; i < n; i++ {
+					for j := 0; j < size; j++ {
+						if buf[j*n+i] != src[i*size+j] {
+							t.Fatalf("byte %d of value %d was not written to its stream", j, i)
+						}
+					}
+				}
+
+				out, err := e.DecodeFixedLenByteArray(nil, buf, size)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !bytes.Equal(out, src) {
+					t.Fatalf("%d values: decoded output does not match input", n)
+				}
+			}
+		})
+	}
+
+	if _, err := e.EncodeFixedLenByteArray(nil, make([]byte, 5), 2); err == nil {
+		t.Error("expected an error encoding an input which is not a multiple of the value size")
+	}
+	if _, err := e.DecodeFixedLenByteArray(nil, make([]byte, 5), 2); err == nil {
+		t.Error("expected an error decoding an input which is not a multiple of the value size")
+	}
+}
@@ -0,0 +1,639 @@
+package parquet
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/parquet-go/parquet-go/encoding/thrift"
+	"github.com/parquet-go/parquet-go/format"
+)
+
+// Module types used to build the additional authenticated data (AAD) of
+// encrypted modules, as defined by the parquet modular encryption spec.
+//
+// See https://github.com/apache/parquet-format/blob/master/Encryption.md
+const (
+	moduleFooter byte = iota
+	moduleColumnMetaData
+	moduleDataPage
+	moduleDictionaryPage
+	moduleDataPageHeader
+	moduleDictionaryPageHeader
+	moduleColumnIndex
+	moduleOffsetIndex
+	moduleBloomFilterHeader
+	moduleBloomFilterBitset
+)
+
+const (
+	encryptionLengthSize = 4
+	encryptionNonceSize  = 12
+	encryptionTagSize    = 16
+	// The footer signature of files written in plaintext footer mode is made
+	// of the nonce and authentication tag of the footer encrypted with the
+	// footer key.
+	encryptionSignatureSize = encryptionNonceSize + encryptionTagSize
+)
+
+var (
+	// ErrMissingDecryptionKeys is an error returned when opening an encrypted
+	// parquet file without having configured a KeyRetriever.
+	ErrMissingDecryptionKeys = errors.New("missing decryption keys of encrypted parquet file")
+
+	// ErrMissingAADPrefix is an error returned when opening an encrypted
+	// parquet file which requires the AAD prefix to be supplied by the
+	// application, but none were configured.
+	ErrMissingAADPrefix = errors.New("missing AAD prefix of encrypted parquet file")
+
+	// ErrInvalidFooterSignature is an error returned when the signature of a
+	// plaintext footer does not match the signature computed with the footer
+	// key.
+	ErrInvalidFooterSignature = errors.New("invalid footer signature of encrypted parquet file")
+)
+
+// KeyRetriever is an interface implemented by types that give access to the
+// keys used to decrypt parquet files.
+//
+// The key metadata passed to the methods are the values recorded by the writer
+// of the file, they may be nil if the writer did not record any. Keys must be
+// 16, 24, or 32 bytes long to select AES-128, AES-192, or AES-256.
+//
+// When a column key cannot be retrieved, the file can still be opened and the
+// other columns read, but attempting to read pages of the column will return
+// the error.
+type KeyRetriever interface {
+	// Returns the key used to decrypt the footer of a file, or verify the
+	// signature of its plaintext footer.
+	FooterKey(keyMetadata []byte) ([]byte, error)
+
+	// Returns the key used to decrypt the column at the given path.
+	ColumnKey(path []string, keyMetadata []byte) ([]byte, error)
+}
+
+// StaticKeys constructs a KeyRetriever which always returns the same footer
+// key, and looks up column keys by column path in the given map. The keys of
+// the map are the column paths joined with dots (e.g. "a.b.c").
+//
+// The key metadata recorded in parquet files are ignored.
+func StaticKeys(footerKey []byte, columnKeys map[string][]byte) KeyRetriever {
+	return &staticKeys{footerKey: footerKey, columnKeys: columnKeys}
+}
+
+type staticKeys struct {
+	footerKey  []byte
+	columnKeys map[string][]byte
+}
+
+func (k *staticKeys) FooterKey([]byte) ([]byte, error) {
+	if k.footerKey == nil {
+		return nil, fmt.Errorf("no footer key configured")
+	}
+	return k.footerKey, nil
+}
+
+func (k *staticKeys) ColumnKey(path []string, _ []byte) ([]byte, error) {
+	key, ok := k.columnKeys[columnPath(path).String()]
+	if !ok {
+		return nil, fmt.Errorf("no key configured for column %q", columnPath(path))
+	}
+	return key, nil
+}
+
+// moduleAAD builds the AAD of a module by appending the module type and
+// ordinals to the file AAD.
+func moduleAAD(fileAAD []byte, module byte, rowGroup, column, page int) []byte {
+	aad := make([]byte, 0, len(fileAAD)+7)
+	aad = append(aad, fileAAD...)
+	aad = append(aad, module)
+	if module == moduleFooter {
+		return aad
+	}
+	aad = binary.LittleEndian.AppendUint16(aad, uint16(rowGroup))
+	aad = binary.LittleEndian.AppendUint16(aad, uint16(column))
+	if module == moduleDataPage || module == moduleDataPageHeader {
+		aad = binary.LittleEndian.AppendUint16(aad, uint16(page))
+	}
+	return aad
+}
+
+// aesCipher implements the encryption and decryption of parquet modules with
+// a single key.
+type aesCipher struct {
+	block cipher.Block
+	gcm   cipher.AEAD
+}
+
+func newAESCipher(key []byte) (*aesCipher, error) {
+	switch len(key) {
+	case 16, 24, 32:
+	default:
+		return nil, fmt.Errorf("invalid encryption key length: %d", len(key))
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &aesCipher{block: block, gcm: gcm}, nil
+}
+
+// decryptGCM decrypts a length-prefixed AES-GCM module.
+func (c *aesCipher) decryptGCM(dst, module, aad []byte) ([]byte, error) {
+	data, err := moduleData(module, encryptionNonceSize+encryptionTagSize)
+	if err != nil {
+		return nil, err
+	}
+	nonce, ciphertext := data[:encryptionNonceSize], data[encryptionNonceSize:]
+	plaintext, err := c.gcm.Open(dst, nonce, ciphertext, aad)
+	if err != nil {
+		return nil, fmt.Errorf("decrypting parquet module: %w", err)
+	}
+	return plaintext, nil
+}
+
+// decryptCTR decrypts a length-prefixed AES-CTR module.
+func (c *aesCipher) decryptCTR(dst, module []byte) ([]byte, error) {
+	data, err := moduleData(module, encryptionNonceSize)
+	if err != nil {
+		return nil, err
+	}
+	nonce, ciphertext := data[:encryptionNonceSize], data[encryptionNonceSize:]
+	dst = append(dst[:0], ciphertext...)
+	cipher.NewCTR(c.block, ctrIV(nonce)).XORKeyStream(dst, dst)
+	return dst, nil
+}
+
+// encryptGCM appends the length-prefixed AES-GCM module of plaintext to dst.
+func (c *aesCipher) encryptGCM(dst, plaintext, aad []byte) ([]byte, error) {
+	offset := len(dst)
+	dst = append(dst, make([]byte, encryptionLengthSize+encryptionNonceSize)...)
+	nonce := dst[offset+encryptionLengthSize:]
+	if _, err := rand.Read(nonce); err != nil {
+		return dst[:offset], err
+	}
+	dst = c.gcm.Seal(dst, nonce, plaintext, aad)
+	binary.LittleEndian.PutUint32(dst[offset:], uint32(len(dst)-(offset+encryptionLengthSize)))
+	return dst, nil
+}
+
+// encryptCTR appends the length-prefixed AES-CTR module of plaintext to dst.
+func (c *aesCipher) encryptCTR(dst, plaintext []byte) ([]byte, error) {
+	offset := len(dst)
+	dst = append(dst, make([]byte, encryptionLengthSize+encryptionNonceSize+len(plaintext))...)
+	module := dst[offset:]
+	nonce := module[encryptionLengthSize : encryptionLengthSize+encryptionNonceSize]
+	if _, err := rand.Read(nonce); err != nil {
+		return dst[:offset], err
+	}
+	cipher.NewCTR(c.block, ctrIV(nonce)).XORKeyStream(module[encryptionLengthSize+encryptionNonceSize:], plaintext)
+	binary.LittleEndian.PutUint32(module, uint32(len(module)-encryptionLengthSize))
+	return dst, nil
+}
+
+// sign computes the signature of a plaintext footer with the given nonce.
+func (c *aesCipher) sign(nonce, footer, aad []byte) []byte {
+	sealed := c.gcm.Seal(nil, nonce, footer, aad)
+	signature := make([]byte, 0, encryptionSignatureSize)
+	signature = append(signature, nonce...)
+	signature = append(signature, sealed[len(sealed)-encryptionTagSize:]...)
+	return signature
+}
+
+// The counter of AES-CTR modules is initialized with the 12 bytes nonce
+// followed by a 4 bytes big-endian counter starting at 1.
+func ctrIV(nonce []byte) []byte {
+	iv := make([]byte, aes.BlockSize)
+	copy(iv, nonce)
+	iv[aes.BlockSize-1] = 1
+	return iv
+}
+
+func moduleData(module []byte, minSize int) ([]byte, error) {
+	if len(module) < encryptionLengthSize {
+		return nil, fmt.Errorf("encrypted parquet module is too short: %d bytes", len(module))
+	}
+	length := int(binary.LittleEndian.Uint32(module))
+	data := module[encryptionLengthSize:]
+	if length != len(data) || length < minSize {
+		return nil, fmt.Errorf("encrypted parquet module length mismatch: want=%d got=%d: %w", length, len(data), ErrCorrupted)
+	}
+	return data, nil
+}
+
+// readModule reads a length-prefixed module from r, the returned slice
+// includes the length prefix.
+func readModule(r io.Reader) ([]byte, error) {
+	var length [encryptionLengthSize]byte
+	if _, err := io.ReadFull(r, length[:]); err != nil {
+		return nil, err
+	}
+	module := make([]byte, encryptionLengthSize+int(binary.LittleEndian.Uint32(length[:])))
+	copy(module, length[:])
+	if _, err := io.ReadFull(r, module[encryptionLengthSize:]); err != nil {
+		return nil, err
+	}
+	return module, nil
+}
+
+// fileDecryptor carries the state needed to decrypt the modules of a file.
+type fileDecryptor struct {
+	keys   KeyRetriever
+	aad    []byte
+	ctr    bool
+	footer *aesCipher
+}
+
+func newFileDecryptor(config *FileConfig, algorithm *format.EncryptionAlgorithm, footerKeyMetadata []byte) (*fileDecryptor, error) {
+	if config.KeyRetriever == nil {
+		return nil, ErrMissingDecryptionKeys
+	}
+
+	var aadPrefix, aadFileUnique []byte
+	var supplyAADPrefix, ctr bool
+	switch {
+	case algorithm.AesGcmV1 != nil:
+		aadPrefix = algorithm.AesGcmV1.AadPrefix
+		aadFileUnique = algorithm.AesGcmV1.AadFileUnique
+		supplyAADPrefix = algorithm.AesGcmV1.SupplyAadPrefix
+	case algorithm.AesGcmCtrV1 != nil:
+		aadPrefix = algorithm.AesGcmCtrV1.AadPrefix
+		aadFileUnique = algorithm.AesGcmCtrV1.AadFileUnique
+		supplyAADPrefix = algorithm.AesGcmCtrV1.SupplyAadPrefix
+		ctr = true
+	default:
+		return nil, fmt.Errorf("unsupported encryption algorithm of parquet file")
+	}
+
+	if config.AADPrefix != nil {
+		if aadPrefix != nil && !bytes.Equal(aadPrefix, config.AADPrefix) {
+			return nil, fmt.Errorf("AAD prefix stored in the parquet file does not match the configured AAD prefix")
+		}
+		aadPrefix = config.AADPrefix
+	} else if supplyAADPrefix {
+		return nil, ErrMissingAADPrefix
+	}
+
+	footerKey, err := config.KeyRetriever.FooterKey(footerKeyMetadata)
+	if err != nil {
+		return nil, fmt.Errorf("retrieving footer key of parquet file: %w", err)
+	}
+	footer, err := newAESCipher(footerKey)
+	if err != nil {
+		return nil, fmt.Errorf("footer key of parquet file: %w", err)
+	}
+
+	aad := make([]byte, 0, len(aadPrefix)+len(aadFileUnique))
+	aad = append(aad, aadPrefix...)
+	aad = append(aad, aadFileUnique...)
+	return &fileDecryptor{
+		keys:   config.KeyRetriever,
+		aad:    aad,
+		ctr:    ctr,
+		footer: footer,
+	}, nil
+}
+
+func (d *fileDecryptor) decryptFooter(module []byte) ([]byte, error) {
+	return d.footer.decryptGCM(nil, module, moduleAAD(d.aad, moduleFooter, 0, 0, 0))
+}
+
+func (d *fileDecryptor) verifyFooter(footer, signature []byte) error {
+	nonce := signature[:encryptionNonceSize]
+	aad := moduleAAD(d.aad, moduleFooter, 0, 0, 0)
+	if subtle.ConstantTimeCompare(d.footer.sign(nonce, footer, aad), signature) != 1 {
+		return ErrInvalidFooterSignature
+	}
+	return nil
+}
+
+// columnDecryptor returns the decryptor for the column chunk at the given row
+// group and column ordinals. If the chunk is not encrypted, the method returns
+// nil.
+//
+// A zero-value fileDecryptor is used to open files with plaintext footers when
+// no keys were configured, in which case all the decryptors of encrypted
+// columns carry ErrMissingDecryptionKeys.
+//
+// When the column key cannot be retrieved, the returned decryptor carries the
+// error, which is reported when attempting to decrypt modules.
+func (d *fileDecryptor) columnDecryptor(rowGroup, column int, chunk *format.ColumnChunk) *columnDecryptor {
+	crypto := &chunk.CryptoMetadata
+	c := &columnDecryptor{
+		aad:      d.aad,
+		ctr:      d.ctr,
+		rowGroup: rowGroup,
+		column:   column,
+	}
+	switch {
+	case crypto.EncryptionWithFooterKey != nil:
+		if c.cipher = d.footer; c.cipher == nil {
+			c.err = ErrMissingDecryptionKeys
+		}
+	case crypto.EncryptionWithColumnKey != nil:
+		if d.keys == nil {
+			c.err = ErrMissingDecryptionKeys
+			break
+		}
+		path := crypto.EncryptionWithColumnKey.PathInSchema
+		key, err := d.keys.ColumnKey(path, crypto.EncryptionWithColumnKey.KeyMetadata)
+		if err == nil {
+			c.cipher, err = newAESCipher(key)
+		}
+		if err != nil {
+			c.err = fmt.Errorf("retrieving key of encrypted column %q: %w", columnPath(path), err)
+		}
+	default:
+		return nil
+	}
+	return c
+}
+
+// decryptColumnMetaData decrypts the column metadata of all the column chunks
+// of the file, and returns the list of decryptors associated with each chunk,
+// using the same layout as the page index.
+func (d *fileDecryptor) decryptColumnMetaData(protocol thrift.Protocol, metadata *format.FileMetaData) ([]*columnDecryptor, error) {
+	if len(metadata.RowGroups) == 0 {
+		return nil, nil
+	}
+	numColumns := len(metadata.RowGroups[0].Columns)
+	decryptors := make([]*columnDecryptor, len(metadata.RowGroups)*numColumns)
+
+	for i := range metadata.RowGroups {
+		rowGroup := &metadata.RowGroups[i]
+		if len(rowGroup.Columns) != numColumns {
+			return nil, fmt.Errorf("row group at index %d contains %d columns but %d were expected", i, len(rowGroup.Columns), numColumns)
+		}
+		for j := range rowGroup.Columns {
+			chunk := &rowGroup.Columns[j]
+			c := d.columnDecryptor(i, j, chunk)
+			decryptors[(i*numColumns)+j] = c
+
+			if c == nil || c.err != nil || len(chunk.EncryptedColumnMetadata) == 0 {
+				continue
+			}
+			b, err := c.decrypt(moduleColumnMetaData, 0, chunk.EncryptedColumnMetadata)
+			if err != nil {
+				return nil, fmt.Errorf("decrypting column metadata: rowGroup=%d columnChunk=%d/%d: %w", i, j, numColumns, err)
+			}
+			chunk.MetaData = format.ColumnMetaData{}
+			if err := thrift.Unmarshal(protocol, b, &chunk.MetaData); err != nil {
+				return nil, fmt.Errorf("decoding column metadata: rowGroup=%d columnChunk=%d/%d: %w", i, j, numColumns, err)
+			}
+		}
+	}
+
+	return decryptors, nil
+}
+
+// columnDecryptor decrypts the modules of a column chunk.
+type columnDecryptor struct {
+	aad      []byte
+	ctr      bool
+	cipher   *aesCipher
+	err      error
+	rowGroup int
+	column   int
+}
+
+// decrypt decrypts a length-prefixed module of the column chunk. Data and
+// dictionary pages use AES-CTR when the file was encrypted with the
+// AES_GCM_CTR_V1 algorithm, all other modules use AES-GCM.
+func (c *columnDecryptor) decrypt(module byte, page int, data []byte) ([]byte, error) {
+	return c.decryptTo(nil, module, page, data)
+}
+
+// decryptTo is like decrypt but writes the plaintext to the beginning of dst,
+// which must not overlap with data. The returned slice shares the backing
+// array of dst when its capacity is large enough to hold the plaintext, which
+// is always the case when the capacity is at least len(data).
+func (c *columnDecryptor) decryptTo(dst []byte, module byte, page int, data []byte) ([]byte, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	if c.ctr && (module == moduleDataPage || module == moduleDictionaryPage) {
+		return c.cipher.decryptCTR(dst[:0], data)
+	}
+	return c.cipher.decryptGCM(dst[:0], data, moduleAAD(c.aad, module, c.rowGroup, c.column, page))
+}
+
+// readModule reads and decrypts the next module from r.
+func (c *columnDecryptor) readModule(r io.Reader, module byte, page int) ([]byte, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	b, err := readModule(r)
+	if err != nil {
+		return nil, err
+	}
+	return c.decrypt(module, page, b)
+}
+
+// readEncryptedBloomFilter reads and decrypts the header and bitset of the
+// bloom filter of an encrypted column chunk. Since the bitset cannot be read
+// lazily, the filter is loaded in memory.
+func readEncryptedBloomFilter(protocol thrift.Protocol, r io.Reader, c *columnDecryptor) (*bloomFilter, error) {
+	b, err := c.readModule(r, moduleBloomFilterHeader, 0)
+	if err != nil {
+		return nil, err
+	}
+	header := format.BloomFilterHeader{}
+	if err := thrift.Unmarshal(protocol, b, &header); err != nil {
+		return nil, err
+	}
+	bitset, err := c.readModule(r, moduleBloomFilterBitset, 0)
+	if err != nil {
+		return nil, err
+	}
+	if len(bitset) != int(header.NumBytes) {
+		return nil, fmt.Errorf("bloom filter size mismatch: want=%d got=%d: %w", header.NumBytes, len(bitset), ErrCorrupted)
+	}
+	return newBloomFilter(bytes.NewReader(bitset), 0, &header), nil
+}
+
+// fileEncryptor carries the state needed to encrypt the modules of a file.
+type fileEncryptor struct {
+	config    *EncryptionConfig
+	algorithm format.EncryptionAlgorithm
+	aad       []byte
+	ctr       bool
+	footer    *aesCipher
+}
+
+func newFileEncryptor(config *EncryptionConfig) (*fileEncryptor, error) {
+	footer, err := newAESCipher(config.FooterKey)
+	if err != nil {
+		return nil, fmt.Errorf("footer key of parquet file: %w", err)
+	}
+	e := &fileEncryptor{
+		config: config,
+		ctr:    config.Algorithm == AESGCMCTRV1,
+		footer: footer,
+	}
+	return e, e.reset()
+}
+
+// reset generates a new unique file AAD, it must be called before writing each
+// file so modules cannot be swapped between files encrypted with the same keys.
+func (e *fileEncryptor) reset() error {
+	aadFileUnique := make([]byte, 8)
+	if _, err := rand.Read(aadFileUnique); err != nil {
+		return err
+	}
+
+	aadPrefix := e.config.AADPrefix
+	if e.config.SupplyAADPrefix {
+		aadPrefix = nil
+	}
+	if e.ctr {
+		e.algorithm = format.EncryptionAlgorithm{AesGcmCtrV1: &format.AesGcmCtrV1{
+			AadPrefix:       aadPrefix,
+			AadFileUnique:   aadFileUnique,
+			SupplyAadPrefix: e.config.SupplyAADPrefix,
+		}}
+	} else {
+		e.algorithm = format.EncryptionAlgorithm{AesGcmV1: &format.AesGcmV1{
+			AadPrefix:       aadPrefix,
+			AadFileUnique:   aadFileUnique,
+			SupplyAadPrefix: e.config.SupplyAADPrefix,
+		}}
+	}
+
+	e.aad = append(e.aad[:0], e.config.AADPrefix...)
+	e.aad = append(e.aad, aadFileUnique...)
+	return nil
+}
+
+func (e *fileEncryptor) magic() string {
+	if e != nil && !e.config.PlaintextFooter {
+		return "PARE"
+	}
+	return "PAR1"
+}
+
+// columnEncryptor returns the encryptor of the column at the given path, or
+// nil if the column is not encrypted.
+func (e *fileEncryptor) columnEncryptor(path columnPath, column int) (*columnEncryptor, error) {
+	c := &columnEncryptor{file: e, cipher: e.footer, column: column}
+
+	if len(e.config.ColumnKeys) == 0 {
+		c.crypto.EncryptionWithFooterKey = &format.EncryptionWithFooterKey{}
+		return c, nil
+	}
+
+	for _, columnKey := range e.config.ColumnKeys {
+		if !path.equal(columnKey.Path) {
+			continue
+		}
+		if columnKey.Key == nil {
+			c.crypto.EncryptionWithFooterKey = &format.EncryptionWithFooterKey{}
+			return c, nil
+		}
+		var err error
+		if c.cipher, err = newAESCipher(columnKey.Key); err != nil {
+			return nil, fmt.Errorf("key of encrypted column %q: %w", path, err)
+		}
+		c.crypto.EncryptionWithColumnKey = &format.EncryptionWithColumnKey{
+			PathInSchema: path,
+			KeyMetadata:  columnKey.KeyMetadata,
+		}
+		return c, nil
+	}
+
+	return nil, nil
+}
+
+// encryptFooter returns the footer section of an encrypted file given the
+// serialized file metadata. In plaintext footer mode, the footer is followed by
+// its signature.
+func (e *fileEncryptor) encryptFooter(protocol thrift.Protocol, footer []byte) ([]byte, error) {
+	aad := moduleAAD(e.aad, moduleFooter, 0, 0, 0)
+
+	if e.config.PlaintextFooter {
+		nonce := make([]byte, encryptionNonceSize)
+		if _, err := rand.Read(nonce); err != nil {
+			return nil, err
+		}
+		return append(footer, e.footer.sign(nonce, footer, aad)...), nil
+	}
+
+	crypto, err := thrift.Marshal(protocol, &format.FileCryptoMetaData{
+		EncryptionAlgorithm: e.algorithm,
+		KeyMetadata:         e.config.FooterKeyMetadata,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return e.footer.encryptGCM(crypto, footer, aad)
+}
+
+// columnEncryptor encrypts the modules of a column chunk.
+type columnEncryptor struct {
+	file     *fileEncryptor
+	cipher   *aesCipher
+	crypto   format.ColumnCryptoMetaData
+	rowGroup int
+	column   int
+}
+
+// encrypt appends the encrypted module of data to dst. Data and dictionary
+// pages use AES-CTR when the file is encrypted with the AES_GCM_CTR_V1
+// algorithm, all other modules use AES-GCM.
+func (c *columnEncryptor) encrypt(dst []byte, module byte, page int, data []byte) ([]byte, error) {
+	if c.file.ctr && (module == moduleDataPage || module == moduleDictionaryPage) {
+		return c.cipher.encryptCTR(dst, data)
+	}
+	return c.cipher.encryptGCM(dst, data, moduleAAD(c.file.aad, module, c.rowGroup, c.column, page))
+}
+
+// encryptColumnMetaData sets the crypto metadata of a column chunk, and
+// encrypts its column metadata when needed.
+//
+// Column chunks encrypted with the footer key do not need to encrypt their
+// metadata when the footer is encrypted. In plaintext footer mode, a redacted
+// copy of the metadata is retained for legacy readers.
+func (c *columnEncryptor) encryptColumnMetaData(protocol thrift.Protocol, rowGroup int, chunk *format.ColumnChunk) error {
+	chunk.CryptoMetadata = c.crypto
+	plaintextFooter := c.file.config.PlaintextFooter
+	if c.crypto.EncryptionWithFooterKey != nil && !plaintextFooter {
+		return nil
+	}
+
+	b, err := thrift.Marshal(protocol, &chunk.MetaData)
+	if err != nil {
+		return err
+	}
+	aad := moduleAAD(c.file.aad, moduleColumnMetaData, rowGroup, c.column, 0)
+	if chunk.EncryptedColumnMetadata, err = c.cipher.encryptGCM(nil, b, aad); err != nil {
+		return err
+	}
+
+	if plaintextFooter {
+		chunk.MetaData.Statistics = format.Statistics{}
+		chunk.MetaData.EncodingStats = nil
+	} else {
+		chunk.MetaData = format.ColumnMetaData{}
+	}
+	return nil
+}
+
+// decryptor returns a decryptor for the modules written by c, which is used to
+// read back pages that were buffered by the writer.
+func (c *columnEncryptor) decryptor() *columnDecryptor {
+	return &columnDecryptor{
+		aad:      c.file.aad,
+		ctr:      c.file.ctr,
+		cipher:   c.cipher,
+		rowGroup: c.rowGroup,
+		column:   c.column,
+	}
+}
@@ -0,0 +1,638 @@
+package parquet_test
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/parquet-go/parquet-go"
+	"github.com/parquet-go/parquet-go/encoding/thrift"
+	"github.com/parquet-go/parquet-go/format"
+)
+
+var (
+	testFooterKey = []byte("0123456789012345")
+	testColumnKey = []byte("1234567890123450")
+)
+
+type encryptedRow struct {
+	ID    int64  `parquet:"id"`
+	Name  string `parquet:"name,dict"`
+	Email string `parquet:"email"`
+}
+
+func encryptedRows(n int) []encryptedRow {
+	rows := make([]encryptedRow, n)
+	for i := range rows {
+		rows[i] = encryptedRow{
+			ID:    int64(i),
+			Name:  "name-" + string(rune('a'+i%26)),
+			Email: "user" + strings.Repeat("x", i%7) + "@example.com",
+		}
+	}
+	return rows
+}
+
+// testEncryption describes how encryptFile encrypts a plaintext parquet file.
+type testEncryption struct {
+	plaintextFooter bool
+	ctr             bool
+	columnKeys      map[string][]byte
+}
+
+// encryptFile rewrites a plaintext parquet file into a file encrypted with
+// parquet modular encryption. Only the pages and metadata are retained, the
+// page index and bloom filters are dropped.
+func encryptFile(t *testing.T, data []byte, config testEncryption) []byte {
+	t.Helper()
+	f, err := parquet.OpenFile(bytes.NewReader(data), int64(len(data)), parquet.SkipPageIndex(true), parquet.SkipBloomFilters(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	protocol := new(thrift.CompactProtocol)
+	metadata := *f.Metadata()
+	metadata.RowGroups = append([]format.RowGroup{}, metadata.RowGroups...)
+
+	aadFileUnique := make([]byte, 8)
+	rand.Read(aadFileUnique)
+	algorithm := format.EncryptionAlgorithm{}
+	if config.ctr {
+		algorithm.AesGcmCtrV1 = &format.AesGcmCtrV1{AadFileUnique: aadFileUnique}
+	} else {
+		algorithm.AesGcmV1 = &format.AesGcmV1{AadFileUnique: aadFileUnique}
+	}
+
+	magic := "PARE"
+	if config.plaintextFooter {
+		magic = "PAR1"
+	}
+	output := new(bytes.Buffer)
+	output.WriteString(magic)
+
+	for i := range metadata.RowGroups {
+		rowGroup := &metadata.RowGroups[i]
+		rowGroup.Columns = append([]format.ColumnChunk{}, rowGroup.Columns...)
+
+		for j := range rowGroup.Columns {
+			chunk := &rowGroup.Columns[j]
+			path := strings.Join(chunk.MetaData.PathInSchema, ".")
+			key, hasColumnKey := config.columnKeys[path]
+			if !hasColumnKey {
+				key = testFooterKey
+			}
+			if hasColumnKey {
+				chunk.CryptoMetadata.EncryptionWithColumnKey = &format.EncryptionWithColumnKey{
+					PathInSchema: chunk.MetaData.PathInSchema,
+					KeyMetadata:  []byte(path),
+				}
+			} else {
+				chunk.CryptoMetadata.EncryptionWithFooterKey = &format.EncryptionWithFooterKey{}
+			}
+
+			offset := chunk.MetaData.DataPageOffset
+			if chunk.MetaData.DictionaryPageOffset != 0 {
+				offset = chunk.MetaData.DictionaryPageOffset
+			}
+			input := bytes.NewReader(data[offset : offset+chunk.MetaData.TotalCompressedSize])
+			decoder := thrift.NewDecoder(protocol.NewReader(input))
+			chunkOffset := int64(output.Len())
+			chunk.MetaData.DictionaryPageOffset = 0
+			chunk.MetaData.DataPageOffset = 0
+			chunk.ColumnIndexOffset, chunk.ColumnIndexLength = 0, 0
+			chunk.OffsetIndexOffset, chunk.OffsetIndexLength = 0, 0
+
+			for pageOrdinal := 0; input.Len() > 0; {
+				header := new(format.PageHeader)
+				if err := decoder.Decode(header); err != nil {
+					t.Fatal(err)
+				}
+				page := make([]byte, header.CompressedPageSize)
+				if _, err := io.ReadFull(input, page); err != nil {
+					t.Fatal(err)
+				}
+
+				headerModule, pageModule := byte(4), byte(2)
+				if header.Type == format.DictionaryPage {
+					headerModule, pageModule = 5, 3
+					chunk.MetaData.DictionaryPageOffset = int64(output.Len())
+				} else if chunk.MetaData.DataPageOffset == 0 {
+					chunk.MetaData.DataPageOffset = int64(output.Len())
+				}
+
+				if config.ctr {
+					page = encryptCTR(t, key, page)
+				} else {
+					page = encryptGCM(t, key, page, testAAD(aadFileUnique, pageModule, i, j, pageOrdinal))
+				}
+				header.CompressedPageSize = int32(len(page))
+				header.CRC = int32(crc32.ChecksumIEEE(page))
+
+				b, err := thrift.Marshal(protocol, header)
+				if err != nil {
+					t.Fatal(err)
+				}
+				output.Write(encryptGCM(t, key, b, testAAD(aadFileUnique, headerModule, i, j, pageOrdinal)))
+				output.Write(page)
+
+				if header.Type != format.DictionaryPage {
+					pageOrdinal++
+				}
+			}
+
+			chunk.MetaData.TotalCompressedSize = int64(output.Len()) - chunkOffset
+			chunk.MetaData.BloomFilterOffset = 0
+			chunk.FileOffset = chunkOffset
+
+			if hasColumnKey || config.plaintextFooter {
+				b, err := thrift.Marshal(protocol, &chunk.MetaData)
+				if err != nil {
+					t.Fatal(err)
+				}
+				chunk.EncryptedColumnMetadata = encryptGCM(t, key, b, testAAD(aadFileUnique, 1, i, j, 0))
+				if config.plaintextFooter {
+					chunk.MetaData.Statistics = format.Statistics{}
+					chunk.MetaData.EncodingStats = nil
+				} else {
+					chunk.MetaData = format.ColumnMetaData{}
+				}
+			}
+		}
+	}
+
+	footerAAD := testAAD(aadFileUnique, 0, 0, 0, 0)
+	footerOffset := output.Len()
+	if config.plaintextFooter {
+		metadata.EncryptionAlgorithm = algorithm
+		footer, err := thrift.Marshal(protocol, &metadata)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sealed := encryptGCM(t, testFooterKey, footer, footerAAD)
+		output.Write(footer)
+		output.Write(sealed[4 : 4+12])
+		output.Write(sealed[len(sealed)-16:])
+	} else {
+		crypto, err := thrift.Marshal(protocol, &format.FileCryptoMetaData{EncryptionAlgorithm: algorithm})
+		if err != nil {
+			t.Fatal(err)
+		}
+		footer, err := thrift.Marshal(protocol, &metadata)
+		if err != nil {
+			t.Fatal(err)
+		}
+		output.Write(crypto)
+		output.Write(encryptGCM(t, testFooterKey, footer, footerAAD))
+	}
+	binary.Write(output, binary.LittleEndian, uint32(output.Len()-footerOffset))
+	output.WriteString(magic)
+	return output.Bytes()
+}
+
+func testAAD(fileAAD []byte, module byte, rowGroup, column, page int) []byte {
+	aad := append([]byte{}, fileAAD...)
+	aad = append(aad, module)
+	if module == 0 {
+		return aad
+	}
+	aad = binary.LittleEndian.AppendUint16(aad, uint16(rowGroup))
+	aad = binary.LittleEndian.AppendUint16(aad, uint16(column))
+	if module == 2 || module == 4 {
+		aad = binary.LittleEndian.AppendUint16(aad, uint16(page))
+	}
+	return aad
+}
+
+func encryptGCM(t *testing.T, key, plaintext, aad []byte) []byte {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	module := make([]byte, 4+12, 4+12+len(plaintext)+16)
+	rand.Read(module[4:])
+	module = gcm.Seal(module, module[4:], plaintext, aad)
+	binary.LittleEndian.PutUint32(module, uint32(len(module)-4))
+	return module
+}
+
+func encryptCTR(t *testing.T, key, plaintext []byte) []byte {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	module := make([]byte, 4+12+len(plaintext))
+	rand.Read(module[4:16])
+	iv := make([]byte, 16)
+	copy(iv, module[4:16])
+	iv[15] = 1
+	cipher.NewCTR(block, iv).XORKeyStream(module[16:], plaintext)
+	binary.LittleEndian.PutUint32(module, uint32(len(module)-4))
+	return module
+}
+
+func writeEncryptionTestFile(t *testing.T, rows []encryptedRow, options ...parquet.WriterOption) []byte {
+	t.Helper()
+	buffer := new(bytes.Buffer)
+	options = append([]parquet.WriterOption{parquet.PageBufferSize(256)}, options...)
+	writer := parquet.NewGenericWriter[encryptedRow](buffer, options...)
+	if _, err := writer.Write(rows); err != nil {
+		t.Fatal(err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buffer.Bytes()
+}
+
+func readEncryptedRows(data []byte, options ...parquet.FileOption) ([]encryptedRow, error) {
+	f, err := parquet.OpenFile(bytes.NewReader(data), int64(len(data)), options...)
+	if err != nil {
+		return nil, err
+	}
+	reader := parquet.NewGenericReader[encryptedRow](f)
+	defer reader.Close()
+	rows := make([]encryptedRow, f.NumRows())
+	n, err := reader.Read(rows)
+	if err == io.EOF {
+		err = nil
+	}
+	return rows[:n], err
+}
+
+func assertEncryptedRows(t *testing.T, want, got []encryptedRow) {
+	t.Helper()
+	if len(want) != len(got) {
+		t.Fatalf("number of rows mismatch: want=%d got=%d", len(want), len(got))
+	}
+	for i := range want {
+		if want[i] != got[i] {
+			t.Fatalf("row %d mismatch:\nwant = %+v\ngot  = %+v", i, want[i], got[i])
+		}
+	}
+}
+
+func TestOpenEncryptedFile(t *testing.T) {
+	keys := parquet.StaticKeys(testFooterKey, map[string][]byte{"email": testColumnKey})
+
+	tests := []struct {
+		scenario string
+		config   testEncryption
+	}{
+		{
+			scenario: "encrypted footer with AES_GCM_V1",
+			config:   testEncryption{},
+		},
+		{
+			scenario: "encrypted footer with AES_GCM_CTR_V1",
+			config:   testEncryption{ctr: true},
+		},
+		{
+			scenario: "encrypted footer and column key",
+			config:   testEncryption{columnKeys: map[string][]byte{"email": testColumnKey}},
+		},
+		{
+			scenario: "plaintext footer and column key",
+			config:   testEncryption{plaintextFooter: true, columnKeys: map[string][]byte{"email": testColumnKey}},
+		},
+	}
+
+	rows := encryptedRows(100)
+
+	for _, test := range tests {
+		t.Run(test.scenario, func(t *testing.T) {
+			for _, version := range []int{1, 2} {
+				data := encryptFile(t, writeEncryptionTestFile(t, rows, parquet.DataPageVersion(version)), test.config)
+
+				got, err := readEncryptedRows(data, parquet.FileDecryption(keys))
+				if err != nil {
+					t.Fatal(err)
+				}
+				assertEncryptedRows(t, rows, got)
+			}
+		})
+	}
+}
+
+func TestOpenEncryptedFileErrors(t *testing.T) {
+	rows := encryptedRows(10)
+	data := writeEncryptionTestFile(t, rows)
+
+	t.Run("missing keys", func(t *testing.T) {
+		_, err := readEncryptedRows(encryptFile(t, data, testEncryption{}))
+		if !errors.Is(err, parquet.ErrMissingDecryptionKeys) {
+			t.Errorf("expected ErrMissingDecryptionKeys but got %v", err)
+		}
+	})
+
+	t.Run("wrong footer key", func(t *testing.T) {
+		keys := parquet.StaticKeys(testColumnKey, nil)
+		if _, err := readEncryptedRows(encryptFile(t, data, testEncryption{}), parquet.FileDecryption(keys)); err == nil {
+			t.Error("expected an error when decrypting the footer with the wrong key")
+		}
+	})
+
+	t.Run("wrong footer signature key", func(t *testing.T) {
+		keys := parquet.StaticKeys(testColumnKey, nil)
+		encrypted := encryptFile(t, data, testEncryption{plaintextFooter: true})
+		if _, err := readEncryptedRows(encrypted, parquet.FileDecryption(keys)); !errors.Is(err, parquet.ErrInvalidFooterSignature) {
+			t.Errorf("expected ErrInvalidFooterSignature but got %v", err)
+		}
+	})
+
+	t.Run("plaintext footer without column key", func(t *testing.T) {
+		encrypted := encryptFile(t, data, testEncryption{
+			plaintextFooter: true,
+			columnKeys:      map[string][]byte{"email": testColumnKey},
+		})
+		f, err := parquet.OpenFile(bytes.NewReader(encrypted), int64(len(encrypted)),
+			parquet.FileDecryption(parquet.StaticKeys(testFooterKey, nil)),
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		columns := f.RowGroups()[0].ColumnChunks()
+
+		pages := columns[0].Pages()
+		defer pages.Close()
+		if _, err := pages.ReadPage(); err != nil {
+			t.Errorf("reading page of column encrypted with the footer key: %v", err)
+		}
+
+		pages = columns[2].Pages()
+		defer pages.Close()
+		if _, err := pages.ReadPage(); err == nil {
+			t.Error("expected an error when reading a column without its key")
+		}
+	})
+}
+
+func TestWriteEncryptedFile(t *testing.T) {
+	columnKey := parquet.EncryptionWithColumnKey(testColumnKey, []byte("email-key"), "email")
+	footerKey := parquet.EncryptionWithFooterKey(testFooterKey, []byte("footer-key"))
+
+	tests := []struct {
+		scenario string
+		magic    string
+		options  []parquet.EncryptionOption
+		file     []parquet.FileOption
+	}{
+		{
+			scenario: "uniform encryption with AES_GCM_V1",
+			magic:    "PARE",
+			options:  []parquet.EncryptionOption{footerKey},
+		},
+		{
+			scenario: "uniform encryption with AES_GCM_CTR_V1",
+			magic:    "PARE",
+			options:  []parquet.EncryptionOption{footerKey, parquet.EncryptionWithAlgorithm(parquet.AESGCMCTRV1)},
+		},
+		{
+			scenario: "encrypted footer and column key",
+			magic:    "PARE",
+			options:  []parquet.EncryptionOption{footerKey, columnKey, parquet.EncryptionWithColumnKey(nil, nil, "name")},
+		},
+		{
+			scenario: "plaintext footer and column key",
+			magic:    "PAR1",
+			options:  []parquet.EncryptionOption{footerKey, columnKey, parquet.EncryptionWithPlaintextFooter(true)},
+		},
+		{
+			scenario: "stored AAD prefix",
+			magic:    "PARE",
+			options:  []parquet.EncryptionOption{footerKey, parquet.EncryptionWithAADPrefix([]byte("table"), false)},
+		},
+		{
+			scenario: "supplied AAD prefix",
+			magic:    "PARE",
+			options:  []parquet.EncryptionOption{footerKey, parquet.EncryptionWithAADPrefix([]byte("table"), true)},
+			file:     []parquet.FileOption{parquet.FileAADPrefix([]byte("table"))},
+		},
+	}
+
+	keys := keyRetrieverFunc(func(path []string, keyMetadata []byte) ([]byte, error) {
+		switch string(keyMetadata) {
+		case "footer-key":
+			return testFooterKey, nil
+		case "email-key":
+			return testColumnKey, nil
+		}
+		return nil, errors.New("unknown key metadata: " + string(keyMetadata))
+	})
+
+	rows := encryptedRows(200)
+
+	for _, test := range tests {
+		t.Run(test.scenario, func(t *testing.T) {
+			for _, version := range []int{1, 2} {
+				data := writeEncryptionTestFile(t, rows,
+					parquet.DataPageVersion(version),
+					parquet.MaxRowsPerRowGroup(60),
+					parquet.BloomFilters(parquet.SplitBlockFilter(10, "name")),
+					parquet.FileEncryption(test.options...),
+				)
+
+				if magic := string(data[:4]); magic != test.magic {
+					t.Errorf("magic header mismatch: want=%q got=%q", test.magic, magic)
+				}
+
+				options := append([]parquet.FileOption{parquet.FileDecryption(keys)}, test.file...)
+				got, err := readEncryptedRows(data, options...)
+				if err != nil {
+					t.Fatal(err)
+				}
+				assertEncryptedRows(t, rows, got)
+
+				f, err := parquet.OpenFile(bytes.NewReader(data), int64(len(data)), options...)
+				if err != nil {
+					t.Fatal(err)
+				}
+				for _, rowGroup := range f.RowGroups() {
+					for _, chunk := range rowGroup.ColumnChunks() {
+						if _, err := chunk.ColumnIndex(); err != nil {
+							t.Fatal(err)
+						}
+						if _, err := chunk.OffsetIndex(); err != nil {
+							t.Fatal(err)
+						}
+					}
+					filter := rowGroup.ColumnChunks()[1].BloomFilter()
+					if filter == nil {
+						t.Fatal("missing bloom filter")
+					}
+					if ok, err := filter.Check(parquet.ValueOf(rows[0].Name)); err != nil {
+						t.Fatal(err)
+					} else if !ok {
+						t.Errorf("bloom filter does not contain %q", rows[0].Name)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestWriteEncryptedFileWithPlaintextFooterWithoutKeys(t *testing.T) {
+	rows := encryptedRows(50)
+	data := writeEncryptionTestFile(t, rows, parquet.FileEncryption(
+		parquet.EncryptionWithFooterKey(testFooterKey, nil),
+		parquet.EncryptionWithColumnKey(testColumnKey, nil, "email"),
+		parquet.EncryptionWithPlaintextFooter(true),
+	))
+
+	f, err := parquet.OpenFile(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type partialRow struct {
+		ID   int64  `parquet:"id"`
+		Name string `parquet:"name"`
+	}
+	reader := parquet.NewGenericReader[partialRow](f)
+	defer reader.Close()
+
+	got := make([]partialRow, len(rows))
+	if n, err := reader.Read(got); err != nil && err != io.EOF {
+		t.Fatal(err)
+	} else if n != len(rows) {
+		t.Fatalf("wrong number of rows: want=%d got=%d", len(rows), n)
+	}
+	for i := range rows {
+		if got[i].ID != rows[i].ID || got[i].Name != rows[i].Name {
+			t.Fatalf("row %d mismatch: want=%+v got=%+v", i, rows[i], got[i])
+		}
+	}
+
+	pages := f.RowGroups()[0].ColumnChunks()[2].Pages()
+	defer pages.Close()
+	if _, err := pages.ReadPage(); !errors.Is(err, parquet.ErrMissingDecryptionKeys) {
+		t.Errorf("expected ErrMissingDecryptionKeys but got %v", err)
+	}
+}
+
+func TestWriteEncryptedFileUnknownColumnKey(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("expected a panic for a column key which does not match any column")
+		} else if err, ok := r.(error); !ok || !strings.Contains(err.Error(), "emial") {
+			t.Errorf("the error does not mention the column path: %v", r)
+		}
+	}()
+	parquet.NewGenericWriter[encryptedRow](new(bytes.Buffer), parquet.FileEncryption(
+		parquet.EncryptionWithFooterKey(testFooterKey, nil),
+		parquet.EncryptionWithColumnKey(testColumnKey, nil, "emial"),
+	))
+}
+
+func TestEncryptionConfigValidate(t *testing.T) {
+	_, err := parquet.NewWriterConfig(parquet.FileEncryption(
+		parquet.EncryptionWithFooterKey([]byte("too short"), nil),
+	))
+	if err == nil {
+		t.Error("expected an error for an invalid footer key")
+	}
+}
+
+// parquetTestingRow is a subset of the columns of the encrypted files of the
+// apache/parquet-testing repository, which were written by parquet-mr.
+type parquetTestingRow struct {
+	BooleanField bool    `parquet:"boolean_field"`
+	Int32Field   int32   `parquet:"int32_field"`
+	FloatField   float32 `parquet:"float_field"`
+	DoubleField  float64 `parquet:"double_field"`
+}
+
+func TestOpenParquetTestingEncryptedFiles(t *testing.T) {
+	// The keys published with the files, identified by their key metadata.
+	keys := keyRetrieverFunc(func(path []string, keyMetadata []byte) ([]byte, error) {
+		switch string(keyMetadata) {
+		case "kf":
+			return []byte("0123456789012345"), nil
+		case "kc1":
+			return []byte("1234567890123450"), nil
+		case "kc2":
+			return []byte("1234567890123451"), nil
+		}
+		return nil, errors.New("unknown key metadata: " + string(keyMetadata))
+	})
+
+	tests := []struct {
+		file    string
+		options []parquet.FileOption
+	}{
+		{file: "uniform_encryption.parquet.encrypted"},
+		{file: "encrypt_columns_and_footer.parquet.encrypted"},
+		{file: "encrypt_columns_and_footer_aad.parquet.encrypted"},
+		{file: "encrypt_columns_and_footer_ctr.parquet.encrypted"},
+		{file: "encrypt_columns_plaintext_footer.parquet.encrypted"},
+		{
+			file:    "encrypt_columns_and_footer_disable_aad_storage.parquet.encrypted",
+			options: []parquet.FileOption{parquet.FileAADPrefix([]byte("tester"))},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.file, func(t *testing.T) {
+			data, err := os.ReadFile(filepath.Join("testdata", test.file))
+			if errors.Is(err, fs.ErrNotExist) {
+				t.Skipf("%s is not in testdata, copy it from apache/parquet-testing to run this test", test.file)
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			options := append([]parquet.FileOption{parquet.FileDecryption(keys)}, test.options...)
+			f, err := parquet.OpenFile(bytes.NewReader(data), int64(len(data)), options...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if f.NumRows() == 0 {
+				t.Fatal("the file has no rows")
+			}
+
+			reader := parquet.NewGenericReader[parquetTestingRow](f)
+			defer reader.Close()
+			rows := make([]parquetTestingRow, f.NumRows())
+			if n, err := reader.Read(rows); err != nil && err != io.EOF {
+				t.Fatal(err)
+			} else if n != len(rows) {
+				t.Fatalf("wrong number of rows: want=%d got=%d", len(rows), n)
+			}
+
+			for i, row := range rows {
+				want := parquetTestingRow{
+					BooleanField: i%2 == 0,
+					Int32Field:   int32(i),
+					FloatField:   float32(i) * 1.1,
+					DoubleField:  float64(i) * 1.1111111,
+				}
+				if row != want {
+					t.Fatalf("row %d mismatch:\nwant = %+v\ngot  = %+v", i, want, row)
+				}
+			}
+		})
+	}
+}
+
+type keyRetrieverFunc func(path []string, keyMetadata []byte) ([]byte, error)
+
+func (f keyRetrieverFunc) FooterKey(keyMetadata []byte) ([]byte, error) {
+	return f(nil, keyMetadata)
+}
+
+func (f keyRetrieverFunc) ColumnKey(path []string, keyMetadata []byte) ([]byte, error) {
+	return f(path, keyMetadata)
+}
@@ -12,6 +12,11 @@ var (
 	// data.
 	ErrCorrupted = errors.New("corrupted parquet page")
 
+	// ErrMissingPageChecksum is an error returned by the Err method of
+	// ColumnPages instances when they encountered a page without a CRC
+	// checksum while the RequirePageChecksums file option was enabled.
+	ErrMissingPageChecksum = errors.New("missing parquet page checksum")
+
 	// ErrMissingRootColumn is an error returned when opening an invalid parquet
 	// file which does not have a root column.
 	ErrMissingRootColumn = errors.New("parquet file is missing a root column")
@@ -2,6 +2,7 @@ package parquet
 
 import (
 	"bufio"
+	"bytes"
 	"encoding/binary"
 	"fmt"
 	"hash/crc32"
@@ -33,6 +34,8 @@ type File struct {
 	offsetIndexes []format.OffsetIndex
 	rowGroups     []RowGroup
 	config        *FileConfig
+	decryptor     *fileDecryptor
+	decryptors    []*columnDecryptor
 }
 
 // OpenFile opens a parquet file and reads the content between offset 0 and the given
@@ -41,6 +44,9 @@ type File struct {
 // Only the parquet magic bytes and footer are read, column chunks and other
 // parts of the file are left untouched; this means that successfully opening
 // a file does not validate that the pages have valid checksums.
+//
+// Files written with parquet modular encryption can be opened by passing the
+// FileDecryption option to supply the decryption keys.
 func OpenFile(r io.ReaderAt, size int64, options ...FileOption) (*File, error) {
 	b := make([]byte, 8)
 	c, err := NewFileConfig(options...)
@@ -52,17 +58,30 @@ func OpenFile(r io.ReaderAt, size int64, options ...FileOption) (*File, error) {
 	if _, err := readAt(r, b[:4], 0); err != nil {
 		return nil, fmt.Errorf("reading magic header of parquet file: %w", err)
 	}
-	if string(b[:4]) != "PAR1" {
+	magic := string(b[:4])
+	if magic != "PAR1" && magic != "PARE" {
 		return nil, fmt.Errorf("invalid magic header of parquet file: %q", b[:4])
 	}
 
+	var prefetch *prefetchReader
+	if c.Prefetch {
+		// The end of the file is read in a single block which usually contains
+		// the magic footer and the whole footer.
+		prefetch = &prefetchReader{reader: r}
+		tail := min(size, prefetchFooterSize)
+		if err := prefetch.prefetch([]byteRange{{size - tail, tail}}, 0, tail, 1); err != nil {
+			return nil, fmt.Errorf("reading footer of parquet file: %w", err)
+		}
+		f.reader, r = prefetch, prefetch
+	}
+
 	if cast, ok := f.reader.(interface{ SetMagicFooterSection(offset, length int64) }); ok {
 		cast.SetMagicFooterSection(size-8, 8)
 	}
 	if n, err := r.ReadAt(b[:8], size-8); n != 8 {
 		return nil, fmt.Errorf("reading magic footer of parquet file: %w", err)
 	}
-	if string(b[4:8]) != "PAR1" {
+	if string(b[4:8]) != magic {
 		return nil, fmt.Errorf("invalid magic footer of parquet file: %q", b[4:8])
 	}
 
@@ -75,13 +94,28 @@ func OpenFile(r io.ReaderAt, size int64, options ...FileOption) (*File, error) {
 	if _, err := f.readAt(footerData, size-(footerSize+8)); err != nil {
 		return nil, fmt.Errorf("reading footer of parquet file: %w", err)
 	}
-	if err := thrift.Unmarshal(&f.protocol, footerData, &f.metadata); err != nil {
-		return nil, fmt.Errorf("reading parquet file metadata: %w", err)
+	if magic == "PARE" {
+		err = f.readEncryptedFooter(footerData)
+	} else {
+		err = f.readPlaintextFooter(footerData)
+	}
+	if err != nil {
+		return nil, err
 	}
 	if len(f.metadata.Schema) == 0 {
 		return nil, ErrMissingRootColumn
 	}
 
+	if prefetch != nil {
+		ranges, err := f.prefetchRanges()
+		if err != nil {
+			return nil, fmt.Errorf("planning prefetched ranges of parquet file: %w", err)
+		}
+		if err := prefetch.prefetch(ranges, c.PrefetchMaxGap, c.PrefetchMaxRangeSize, c.PrefetchConcurrency); err != nil {
+			return nil, fmt.Errorf("prefetching parquet file: %w", err)
+		}
+	}
+
 	if !c.SkipPageIndex {
 		if f.columnIndexes, f.offsetIndexes, err = f.ReadPageIndex(); err != nil {
 			return nil, fmt.Errorf("reading page index of parquet file: %w", err)
@@ -126,7 +160,16 @@ func OpenFile(r io.ReaderAt, size int64, options ...FileOption) (*File, error) {
 			for j := range g.columns {
 				c := g.columns[j].(*fileColumnChunk)
 
-				if offset := c.chunk.MetaData.BloomFilterOffset; offset > 0 {
+				if offset := c.chunk.MetaData.BloomFilterOffset; offset > 0 && c.decryptor != nil {
+					if c.decryptor.err == nil {
+						section.Seek(offset, io.SeekStart)
+						rbuf.Reset(section)
+
+						if c.bloomFilter, err = readEncryptedBloomFilter(&f.protocol, rbuf, c.decryptor); err != nil {
+							return nil, fmt.Errorf("decoding bloom filter: %w", err)
+						}
+					}
+				} else if offset > 0 {
 					section.Seek(offset, io.SeekStart)
 					rbuf.Reset(section)
 
@@ -154,6 +197,87 @@ func OpenFile(r io.ReaderAt, size int64, options ...FileOption) (*File, error) {
 	return f, nil
 }
 
+func (f *File) readEncryptedFooter(footerData []byte) error {
+	crypto := format.FileCryptoMetaData{}
+	n, err := unmarshalPrefix(&f.protocol, footerData, &crypto)
+	if err != nil {
+		return fmt.Errorf("reading parquet file crypto metadata: %w", err)
+	}
+	if f.decryptor, err = newFileDecryptor(f.config, &crypto.EncryptionAlgorithm, crypto.KeyMetadata); err != nil {
+		return err
+	}
+	if footerData, err = f.decryptor.decryptFooter(footerData[n:]); err != nil {
+		return fmt.Errorf("decrypting parquet file metadata: %w", err)
+	}
+	if err := thrift.Unmarshal(&f.protocol, footerData, &f.metadata); err != nil {
+		return fmt.Errorf("reading parquet file metadata: %w", err)
+	}
+	return f.decryptColumnMetaData()
+}
+
+func (f *File) readPlaintextFooter(footerData []byte) error {
+	n, err := unmarshalPrefix(&f.protocol, footerData, &f.metadata)
+	if err != nil {
+		return fmt.Errorf("reading parquet file metadata: %w", err)
+	}
+
+	algorithm := &f.metadata.EncryptionAlgorithm
+	if algorithm.AesGcmV1 == nil && algorithm.AesGcmCtrV1 == nil {
+		if n != len(footerData) {
+			return fmt.Errorf("reading parquet file metadata: unexpected trailing bytes at the end of thrift input: %d", len(footerData)-n)
+		}
+		return nil
+	}
+
+	// Files with plaintext footers may still contain encrypted columns, in
+	// which case the footer is followed by its signature.
+	signature := footerData[n:]
+	if len(signature) != encryptionSignatureSize {
+		return fmt.Errorf("invalid footer signature length of parquet file: %d: %w", len(signature), ErrCorrupted)
+	}
+	if f.config.KeyRetriever == nil {
+		// Without keys, only the unencrypted columns can be read.
+		f.decryptor = new(fileDecryptor)
+	} else {
+		if f.decryptor, err = newFileDecryptor(f.config, algorithm, f.metadata.FooterSigningKeyMetadata); err != nil {
+			return err
+		}
+		if err := f.decryptor.verifyFooter(footerData[:n], signature); err != nil {
+			return err
+		}
+	}
+	return f.decryptColumnMetaData()
+}
+
+func (f *File) decryptColumnMetaData() (err error) {
+	f.decryptors, err = f.decryptor.decryptColumnMetaData(&f.protocol, &f.metadata)
+	return err
+}
+
+func (f *File) columnDecryptor(rowGroup, column int) *columnDecryptor {
+	if f.decryptors == nil {
+		return nil
+	}
+	return f.decryptors[(rowGroup*len(f.metadata.RowGroups[0].Columns))+column]
+}
+
+func (f *File) decryptIndex(rowGroup, column int, module byte, data []byte) ([]byte, error) {
+	if c := f.columnDecryptor(rowGroup, column); c != nil {
+		return c.decrypt(module, 0, data)
+	}
+	return data, nil
+}
+
+// unmarshalPrefix decodes v from the beginning of b and returns the number of
+// bytes that were consumed.
+func unmarshalPrefix(p thrift.Protocol, b []byte, v interface{}) (int, error) {
+	r := bytes.NewReader(b)
+	if err := thrift.NewDecoder(p.NewReader(r)).Decode(v); err != nil {
+		return 0, err
+	}
+	return len(b) - r.Len(), nil
+}
+
 // ReadPageIndex reads the page index section of the parquet file f.
 //
 // If the file did not contain a page index, the method returns two empty slices
@@ -185,8 +309,8 @@ func (f *File) ReadPageIndex() ([]format.ColumnIndex, []format.OffsetIndex, erro
 		return nil, nil, nil
 	}
 
-	columnIndexOffset := f.metadata.RowGroups[0].Columns[0].ColumnIndexOffset
-	offsetIndexOffset := f.metadata.RowGroups[0].Columns[0].OffsetIndexOffset
+	columnIndexOffset := int64(0)
+	offsetIndexOffset := int64(0)
 	columnIndexLength := int64(0)
 	offsetIndexLength := int64(0)
 
@@ -203,6 +327,15 @@ func (f *File) ReadPageIndex() ([]format.ColumnIndex, []format.OffsetIndex, erro
 	}
 
 	forEachColumnChunk(func(_, _ int, c *format.ColumnChunk) error {
+		// The column index may be missing on some columns, for example when
+		// their values have no defined sort order, so the section starts at
+		// the first column chunk that has an index.
+		if columnIndexOffset == 0 {
+			columnIndexOffset = c.ColumnIndexOffset
+		}
+		if offsetIndexOffset == 0 {
+			offsetIndexOffset = c.OffsetIndexOffset
+		}
 		columnIndexLength += int64(c.ColumnIndexLength)
 		offsetIndexLength += int64(c.OffsetIndexLength)
 		return nil
@@ -235,10 +368,13 @@ func (f *File) ReadPageIndex() ([]format.ColumnIndex, []format.OffsetIndex, erro
 			//
 			// An example of this file is testdata/alltypes_tiny_pages_plain.parquet
 			// which was added in https://github.com/apache/parquet-testing/pull/24.
-			if c.ColumnIndexOffset > 0 {
+			if c.ColumnIndexOffset > 0 && !f.isInaccessible(i, j) {
 				offset := c.ColumnIndexOffset - columnIndexOffset
 				length := int64(c.ColumnIndexLength)
-				buffer := columnIndexData[offset : offset+length]
+				buffer, err := f.decryptIndex(i, j, moduleColumnIndex, columnIndexData[offset:offset+length])
+				if err != nil {
+					return fmt.Errorf("decrypting column index: rowGroup=%d columnChunk=%d/%d: %w", i, j, numColumns, err)
+				}
 				if err := thrift.Unmarshal(&f.protocol, buffer, &columnIndexes[(i*numColumns)+j]); err != nil {
 					return fmt.Errorf("decoding column index: rowGroup=%d columnChunk=%d/%d: %w", i, j, numColumns, err)
 				}
@@ -261,10 +397,13 @@ func (f *File) ReadPageIndex() ([]format.ColumnIndex, []format.OffsetIndex, erro
 		}
 
 		err := forEachColumnChunk(func(i, j int, c *format.ColumnChunk) error {
-			if c.OffsetIndexOffset > 0 {
+			if c.OffsetIndexOffset > 0 && !f.isInaccessible(i, j) {
 				offset := c.OffsetIndexOffset - offsetIndexOffset
 				length := int64(c.OffsetIndexLength)
-				buffer := offsetIndexData[offset : offset+length]
+				buffer, err := f.decryptIndex(i, j, moduleOffsetIndex, offsetIndexData[offset:offset+length])
+				if err != nil {
+					return fmt.Errorf("decrypting offset index: rowGroup=%d columnChunk=%d/%d: %w", i, j, numColumns, err)
+				}
 				if err := thrift.Unmarshal(&f.protocol, buffer, &offsetIndexes[(i*numColumns)+j]); err != nil {
 					return fmt.Errorf("decoding column index: rowGroup=%d columnChunk=%d/%d: %w", i, j, numColumns, err)
 				}
@@ -340,6 +479,13 @@ func (f *File) hasIndexes() bool {
 	return f.columnIndexes != nil && f.offsetIndexes != nil
 }
 
+// isInaccessible returns true if the column chunk is encrypted with a key that
+// could not be retrieved.
+func (f *File) isInaccessible(rowGroup, column int) bool {
+	c := f.columnDecryptor(rowGroup, column)
+	return c != nil && c.err != nil
+}
+
 var _ io.ReaderAt = (*File)(nil)
 
 func sortKeyValueMetadata(keyValueMetadata []format.KeyValue) {
@@ -383,13 +529,14 @@ func (g *fileRowGroup) init(file *File, schema *Schema, columns []*Column, rowGr
 
 	for i := range g.columns {
 		fileColumnChunks[i] = fileColumnChunk{
-			file:     file,
-			column:   columns[i],
-			rowGroup: rowGroup,
-			chunk:    &rowGroup.Columns[i],
+			file:      file,
+			column:    columns[i],
+			rowGroup:  rowGroup,
+			chunk:     &rowGroup.Columns[i],
+			decryptor: file.columnDecryptor(int(rowGroup.Ordinal), i),
 		}
 
-		if file.hasIndexes() {
+		if file.hasIndexes() && !file.isInaccessible(int(rowGroup.Ordinal), i) {
 			j := (int(rowGroup.Ordinal) * len(columns)) + i
 			fileColumnChunks[i].columnIndex.Store(&file.columnIndexes[j])
 			fileColumnChunks[i].offsetIndex.Store(&file.offsetIndexes[j])
@@ -445,6 +592,7 @@ type fileColumnChunk struct {
 	columnIndex atomic.Pointer[format.ColumnIndex]
 	offsetIndex atomic.Pointer[format.OffsetIndex]
 	chunk       *format.ColumnChunk
+	decryptor   *columnDecryptor
 }
 
 func (c *fileColumnChunk) Type() Type {
@@ -509,6 +657,12 @@ func (c *fileColumnChunk) readColumnIndex() (*format.ColumnIndex, error) {
 	if _, err := readAt(c.file.reader, indexData, offset); err != nil {
 		return nil, fmt.Errorf("read %d bytes column index at offset %d: %w", length, offset, err)
 	}
+	if c.decryptor != nil {
+		var err error
+		if indexData, err = c.decryptor.decrypt(moduleColumnIndex, 0, indexData); err != nil {
+			return nil, fmt.Errorf("decrypt column index: rowGroup=%d columnChunk=%d/%d: %w", c.rowGroup.Ordinal, c.Column(), len(c.rowGroup.Columns), err)
+		}
+	}
 	if err := thrift.Unmarshal(&c.file.protocol, indexData, &columnIndex); err != nil {
 		return nil, fmt.Errorf("decode column index: rowGroup=%d columnChunk=%d/%d: %w", c.rowGroup.Ordinal, c.Column(), len(c.rowGroup.Columns), err)
 	}
@@ -538,6 +692,12 @@ func (c *fileColumnChunk) readOffsetIndex() (*format.OffsetIndex, error) {
 	if _, err := readAt(c.file.reader, indexData, offset); err != nil {
 		return nil, fmt.Errorf("read %d bytes offset index at offset %d: %w", length, offset, err)
 	}
+	if c.decryptor != nil {
+		var err error
+		if indexData, err = c.decryptor.decrypt(moduleOffsetIndex, 0, indexData); err != nil {
+			return nil, fmt.Errorf("decrypt offset index: rowGroup=%d columnChunk=%d/%d: %w", c.rowGroup.Ordinal, c.Column(), len(c.rowGroup.Columns), err)
+		}
+	}
 	if err := thrift.Unmarshal(&c.file.protocol, indexData, &offsetIndex); err != nil {
 		return nil, fmt.Errorf("decode offset index: rowGroup=%d columnChunk=%d/%d: %w", c.rowGroup.Ordinal, c.Column(), len(c.rowGroup.Columns), err)
 	}
@@ -555,8 +715,9 @@ type filePages struct {
 	rbufpool *sync.Pool
 	section  io.SectionReader
 
-	protocol thrift.CompactProtocol
-	decoder  thrift.Decoder
+	protocol  thrift.CompactProtocol
+	decoder   thrift.Decoder
+	decryptor *columnDecryptor
 
 	baseOffset int64
 	dataOffset int64
@@ -565,6 +726,12 @@ type filePages struct {
 	skip       int64
 	dictionary Dictionary
 
+	// The ordinal of the next data page and whether the next page header is
+	// expected to be a dictionary page header; both are needed to compute the
+	// AAD of encrypted page headers.
+	pageOrdinal    int
+	dictionaryNext bool
+
 	bufferSize int
 }
 
@@ -573,10 +740,12 @@ func (f *filePages) init(c *fileColumnChunk) {
 	f.baseOffset = c.chunk.MetaData.DataPageOffset
 	f.dataOffset = f.baseOffset
 	f.bufferSize = c.file.config.ReadBufferSize
+	f.decryptor = c.decryptor
 
 	if c.chunk.MetaData.DictionaryPageOffset != 0 {
 		f.baseOffset = c.chunk.MetaData.DictionaryPageOffset
 		f.dictOffset = f.baseOffset
+		f.dictionaryNext = true
 	}
 
 	f.section = *io.NewSectionReader(c.file, f.baseOffset, c.chunk.MetaData.TotalCompressedSize)
@@ -603,13 +772,17 @@ func (f *filePages) ReadPage() (Page, error) {
 		// issues.
 		// https://github.com/parquet-go/parquet-go/issues/70
 		header := new(format.PageHeader)
-		if err := f.decoder.Decode(header); err != nil {
+		if err := f.decodePageHeader(f.rbuf, &f.decoder, header, f.dictionaryNext); err != nil {
 			return nil, err
 		}
+		f.dictionaryNext = false
 		data, err := f.readPage(header, f.rbuf)
 		if err != nil {
 			return nil, err
 		}
+		if header.Type != format.DictionaryPage {
+			f.pageOrdinal++
+		}
 
 		var page Page
 		switch header.Type {
@@ -667,20 +840,34 @@ func (f *filePages) readDictionary() error {
 
 	header := new(format.PageHeader)
 
-	if err := decoder.Decode(header); err != nil {
+	if err := f.decodePageHeader(rbuf, decoder, header, true); err != nil {
 		return err
 	}
 
-	page := buffers.get(int(header.CompressedPageSize))
-	defer page.unref()
-
-	if _, err := io.ReadFull(rbuf, page.data); err != nil {
+	page, err := f.readPage(header, rbuf)
+	if err != nil {
 		return err
 	}
+	defer page.unref()
 
 	return f.readDictionaryPage(header, page)
 }
 
+func (f *filePages) decodePageHeader(r io.Reader, decoder *thrift.Decoder, header *format.PageHeader, dictionary bool) error {
+	if f.decryptor == nil {
+		return decoder.Decode(header)
+	}
+	module := moduleDataPageHeader
+	if dictionary {
+		module = moduleDictionaryPageHeader
+	}
+	b, err := f.decryptor.readModule(r, module, f.pageOrdinal)
+	if err != nil {
+		return err
+	}
+	return thrift.Unmarshal(&f.protocol, b, header)
+}
+
 func (f *filePages) readDictionaryPage(header *format.PageHeader, page *buffer) error {
 	if header.DictionaryPageHeader == nil {
 		return ErrMissingPageHeader
@@ -748,6 +935,23 @@ func (f *filePages) readPage(header *format.PageHeader, reader *bufio.Reader) (*
 				ErrCorrupted,
 			)
 		}
+	} else if f.chunk.file.config.RequirePageChecksums {
+		return nil, fmt.Errorf("crc32 checksum missing in page of column %q: %w", f.columnPath(), ErrMissingPageChecksum)
+	}
+
+	if f.decryptor != nil {
+		module := moduleDataPage
+		if header.Type == format.DictionaryPage {
+			module = moduleDictionaryPage
+		}
+		plaintext := buffers.get(len(page.data))
+		data, err := f.decryptor.decryptTo(plaintext.data, module, f.pageOrdinal, page.data)
+		if err != nil {
+			plaintext.unref()
+			return nil, fmt.Errorf("decrypting page of column %q: %w", f.columnPath(), err)
+		}
+		plaintext.data = data
+		return plaintext, nil
 	}
 
 	page.ref()
@@ -765,6 +969,7 @@ func (f *filePages) SeekToRow(rowIndex int64) (err error) {
 		if f.dictOffset > 0 {
 			f.index = 1
 		}
+		f.pageOrdinal = 0
 	} else {
 		pages := index.PageLocations
 		index := sort.Search(len(pages), func(i int) bool {
@@ -776,7 +981,9 @@ func (f *filePages) SeekToRow(rowIndex int64) (err error) {
 		_, err = f.section.Seek(pages[index].Offset-f.baseOffset, io.SeekStart)
 		f.skip = rowIndex - pages[index].FirstRowIndex
 		f.index = index
+		f.pageOrdinal = index
 	}
+	f.dictionaryNext = false
 	f.rbuf.Reset(&f.section)
 	return err
 }
@@ -793,6 +1000,9 @@ func (f *filePages) Close() error {
 	f.index = 0
 	f.skip = 0
 	f.dictionary = nil
+	f.decryptor = nil
+	f.pageOrdinal = 0
+	f.dictionaryNext = false
 	return nil
 }
 
@@ -0,0 +1,296 @@
+package parquet
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"sync"
+)
+
+// prefetchFooterSize is the size of the block read at the end of files when
+// prefetching is enabled, which usually contains the whole footer.
+const prefetchFooterSize = 64 * 1024
+
+// byteRange is a range of bytes of a file, starting at offset and ending
+// before offset+length.
+type byteRange struct {
+	offset int64
+	length int64
+}
+
+func (r byteRange) end() int64 { return r.offset + r.length }
+
+// prefetchedRange is a range of bytes of a file which was read into memory.
+type prefetchedRange struct {
+	offset int64
+	data   []byte
+}
+
+func (r *prefetchedRange) end() int64 { return r.offset + int64(len(r.data)) }
+
+// prefetchReader is an io.ReaderAt which serves reads from ranges of a file
+// that were read ahead of time, and falls back to the underlying reader for
+// the bytes which are not part of these ranges.
+//
+// The ranges are sorted by offset and never overlap; they are only modified
+// while the file is being opened, after which the reader is safe to use
+// concurrently.
+type prefetchReader struct {
+	reader io.ReaderAt
+	ranges []prefetchedRange
+}
+
+func (r *prefetchReader) ReadAt(b []byte, off int64) (int, error) {
+	n := 0
+	for n < len(b) {
+		i := sort.Search(len(r.ranges), func(i int) bool {
+			return r.ranges[i].end() > off
+		})
+
+		if i < len(r.ranges) && r.ranges[i].offset <= off {
+			c := copy(b[n:], r.ranges[i].data[off-r.ranges[i].offset:])
+			n += c
+			off += int64(c)
+			continue
+		}
+
+		// The bytes at off were not prefetched, they are read from the
+		// underlying reader up to the beginning of the next range.
+		limit := len(b)
+		if i < len(r.ranges) {
+			if gap := r.ranges[i].offset - off; gap < int64(limit-n) {
+				limit = n + int(gap)
+			}
+		}
+		c, err := r.reader.ReadAt(b[n:limit], off)
+		n += c
+		off += int64(c)
+		if n < limit {
+			if err == nil {
+				err = io.ErrUnexpectedEOF
+			}
+			return n, err
+		}
+	}
+	return n, nil
+}
+
+// The section hooks that OpenFile calls on the reader of files are forwarded
+// to the underlying reader, so readers relying on them keep receiving them when
+// prefetching is enabled.
+
+func (r *prefetchReader) SetMagicFooterSection(offset, length int64) {
+	if cast, ok := r.reader.(interface{ SetMagicFooterSection(offset, length int64) }); ok {
+		cast.SetMagicFooterSection(offset, length)
+	}
+}
+
+func (r *prefetchReader) SetFooterSection(offset, length int64) {
+	if cast, ok := r.reader.(interface{ SetFooterSection(offset, length int64) }); ok {
+		cast.SetFooterSection(offset, length)
+	}
+}
+
+func (r *prefetchReader) SetColumnIndexSection(offset, length int64) {
+	if cast, ok := r.reader.(interface{ SetColumnIndexSection(offset, length int64) }); ok {
+		cast.SetColumnIndexSection(offset, length)
+	}
+}
+
+func (r *prefetchReader) SetOffsetIndexSection(offset, length int64) {
+	if cast, ok := r.reader.(interface{ SetOffsetIndexSection(offset, length int64) }); ok {
+		cast.SetOffsetIndexSection(offset, length)
+	}
+}
+
+func (r *prefetchReader) SetBloomFilterSection(offset, length int64) {
+	if cast, ok := r.reader.(interface{ SetBloomFilterSection(offset, length int64) }); ok {
+		cast.SetBloomFilterSection(offset, length)
+	}
+}
+
+// prefetch reads the given ranges of the file concurrently, merging the ranges
+// separated by less than maxGap bytes into reads of at most maxRangeSize bytes.
+func (r *prefetchReader) prefetch(ranges []byteRange, maxGap, maxRangeSize int64, concurrency int) error {
+	reads := coalesceByteRanges(ranges, maxGap, maxRangeSize)
+	if len(reads) == 0 {
+		return nil
+	}
+
+	prefetched := make([]prefetchedRange, len(reads))
+	errs := make([]error, len(reads))
+	next := make(chan int, len(reads))
+	for i := range reads {
+		next <- i
+	}
+	close(next)
+
+	wg := sync.WaitGroup{}
+	for range min(concurrency, len(reads)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				data := make([]byte, reads[i].length)
+				if _, err := readAt(r.reader, data, reads[i].offset); err != nil {
+					errs[i] = fmt.Errorf("prefetching %d bytes at offset %d: %w", reads[i].length, reads[i].offset, err)
+					continue
+				}
+				prefetched[i] = prefetchedRange{offset: reads[i].offset, data: data}
+			}
+		}()
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+
+	// The ranges that were already prefetched take precedence, the new ones
+	// are split around them to only retain the bytes that they did not
+	// contain.
+	existing := r.ranges
+	for _, p := range prefetched {
+		pieces := []prefetchedRange{p}
+		for _, q := range existing {
+			pieces = splitPrefetchedRanges(pieces, q)
+		}
+		r.ranges = append(r.ranges, pieces...)
+	}
+	slices.SortFunc(r.ranges, func(a, b prefetchedRange) int {
+		return compareInt64(a.offset, b.offset)
+	})
+	return nil
+}
+
+// splitPrefetchedRanges returns the parts of the given ranges which are not
+// covered by q.
+func splitPrefetchedRanges(ranges []prefetchedRange, q prefetchedRange) []prefetchedRange {
+	parts := make([]prefetchedRange, 0, len(ranges)+1)
+	for _, p := range ranges {
+		if p.end() <= q.offset || q.end() <= p.offset {
+			parts = append(parts, p)
+			continue
+		}
+		if p.offset < q.offset {
+			parts = append(parts, prefetchedRange{offset: p.offset, data: p.data[:q.offset-p.offset]})
+		}
+		if q.end() < p.end() {
+			parts = append(parts, prefetchedRange{offset: q.end(), data: p.data[q.end()-p.offset:]})
+		}
+	}
+	return parts
+}
+
+// coalesceByteRanges returns the sorted list of reads covering the given
+// ranges, where ranges separated by at most maxGap bytes are merged, and reads
+// are split so they are not larger than maxRangeSize.
+func coalesceByteRanges(ranges []byteRange, maxGap, maxRangeSize int64) []byteRange {
+	ranges = slices.DeleteFunc(slices.Clone(ranges), func(r byteRange) bool {
+		return r.length <= 0
+	})
+	slices.SortFunc(ranges, func(a, b byteRange) int {
+		return compareInt64(a.offset, b.offset)
+	})
+
+	reads := make([]byteRange, 0, len(ranges))
+	for _, r := range ranges {
+		if n := len(reads); n > 0 {
+			last := &reads[n-1]
+			if end := max(last.end(), r.end()); r.offset <= last.end()+maxGap && end-last.offset <= maxRangeSize {
+				last.length = end - last.offset
+				continue
+			}
+			if r.end() <= last.end() {
+				continue
+			}
+			if r.offset < last.end() {
+				r = byteRange{offset: last.end(), length: r.end() - last.end()}
+			}
+		}
+		for r.length > maxRangeSize {
+			reads = append(reads, byteRange{offset: r.offset, length: maxRangeSize})
+			r.offset += maxRangeSize
+			r.length -= maxRangeSize
+		}
+		reads = append(reads, r)
+	}
+	return reads
+}
+
+// prefetchRanges returns the ranges of the file which are read when opening
+// it, and when reading the column chunks of the prefetched columns.
+func (f *File) prefetchRanges() ([]byteRange, error) {
+	var ranges []byteRange
+	var offsets []int64
+
+	var prefetched []bool
+	if len(f.metadata.RowGroups) > 0 {
+		columns := f.metadata.RowGroups[0].Columns
+		prefetched = make([]bool, len(columns))
+		for i := range prefetched {
+			prefetched[i] = len(f.config.PrefetchColumns) == 0
+		}
+		for _, path := range f.config.PrefetchColumns {
+			found := false
+			for i := range columns {
+				if columnPath(path).isPrefixOf(columns[i].MetaData.PathInSchema) {
+					prefetched[i], found = true, true
+				}
+			}
+			if !found {
+				return nil, fmt.Errorf("prefetched column %q does not exist in the schema", columnPath(path))
+			}
+		}
+	}
+
+	for i := range f.metadata.RowGroups {
+		for j := range f.metadata.RowGroups[i].Columns {
+			c := &f.metadata.RowGroups[i].Columns[j]
+			chunkOffset := c.MetaData.DataPageOffset
+			if c.MetaData.DictionaryPageOffset != 0 {
+				chunkOffset = c.MetaData.DictionaryPageOffset
+			}
+			offsets = append(offsets, chunkOffset, c.MetaData.BloomFilterOffset)
+			offsets = append(offsets, c.ColumnIndexOffset, c.OffsetIndexOffset)
+
+			if j < len(prefetched) && prefetched[j] {
+				ranges = append(ranges, byteRange{chunkOffset, c.MetaData.TotalCompressedSize})
+			}
+			if !f.config.SkipPageIndex {
+				ranges = append(ranges,
+					byteRange{c.ColumnIndexOffset, int64(c.ColumnIndexLength)},
+					byteRange{c.OffsetIndexOffset, int64(c.OffsetIndexLength)},
+				)
+			}
+		}
+	}
+
+	if !f.config.SkipBloomFilters {
+		// The length of bloom filters is not recorded in the metadata, they
+		// are assumed to extend up to the next known section of the file.
+		offsets = append(offsets, f.size-int64(len(parquetMagic)))
+		slices.Sort(offsets)
+
+		for i := range f.metadata.RowGroups {
+			for j := range f.metadata.RowGroups[i].Columns {
+				offset := f.metadata.RowGroups[i].Columns[j].MetaData.BloomFilterOffset
+				if offset <= 0 {
+					continue
+				}
+				k, _ := slices.BinarySearch(offsets, offset+1)
+				ranges = append(ranges, byteRange{offset, offsets[k] - offset})
+			}
+		}
+	}
+
+	for i := range ranges {
+		r := &ranges[i]
+		r.offset = min(max(r.offset, 0), f.size)
+		r.length = min(r.length, f.size-r.offset)
+	}
+	return ranges, nil
+}
@@ -0,0 +1,45 @@
+package parquet
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestPrefetchReaderContainedRange(t *testing.T) {
+	data := make([]byte, 1000)
+	for i := range data {
+		data[i] = byte(i)
+	}
+	r := &prefetchReader{reader: bytes.NewReader(data)}
+
+	// The second range contains the first one, it is split around it so the
+	// bytes on both sides are served from memory.
+	if err := r.prefetch([]byteRange{{400, 100}}, 0, 1000, 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.prefetch([]byteRange{{100, 700}}, 0, 1000, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []byteRange{{100, 300}, {400, 100}, {500, 300}}
+	if len(r.ranges) != len(want) {
+		t.Fatalf("wrong number of ranges: want=%d got=%d", len(want), len(r.ranges))
+	}
+	for i, p := range r.ranges {
+		if p.offset != want[i].offset || int64(len(p.data)) != want[i].length {
+			t.Errorf("range %d: want=%+v got={offset:%d length:%d}", i, want[i], p.offset, len(p.data))
+		}
+		if !bytes.Equal(p.data, data[p.offset:p.end()]) {
+			t.Errorf("range %d: wrong data", i)
+		}
+	}
+
+	r.reader = nil // all reads must be served from memory
+	b := make([]byte, 700)
+	if n, err := r.ReadAt(b, 100); n != len(b) || err != nil {
+		t.Fatalf("n=%d err=%v", n, err)
+	}
+	if !bytes.Equal(b, data[100:800]) {
+		t.Error("wrong data read from the prefetched ranges")
+	}
+}
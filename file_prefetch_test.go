@@ -0,0 +1,217 @@
+package parquet_test
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"slices"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+)
+
+// httpRangeReaderAt reads a file served by an HTTP server with range requests,
+// counting the number of requests that were issued.
+type httpRangeReaderAt struct {
+	url      string
+	requests atomic.Int64
+}
+
+func (r *httpRangeReaderAt) ReadAt(b []byte, off int64) (int, error) {
+	r.requests.Add(1)
+	req, err := http.NewRequest(http.MethodGet, r.url, nil)
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Range", fmt.Sprintf("bytes=%d-%d", off, off+int64(len(b))-1))
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusPartialContent {
+		return 0, fmt.Errorf("unexpected status: %s", res.Status)
+	}
+	n, err := io.ReadFull(res.Body, b)
+	if err == io.ErrUnexpectedEOF {
+		err = io.EOF
+	}
+	return n, err
+}
+
+type prefetchRow struct {
+	ID      int64  `parquet:"id"`
+	Name    string `parquet:"name,dict"`
+	Payload []byte `parquet:"payload"`
+}
+
+func prefetchRows() []prefetchRow {
+	rows := make([]prefetchRow, 4000)
+	for i := range rows {
+		rows[i] = prefetchRow{
+			ID:      int64(i),
+			Name:    fmt.Sprintf("name-%d", i%100),
+			Payload: bytes.Repeat([]byte{byte(i)}, 200),
+		}
+	}
+	return rows
+}
+
+func servePrefetchFile(t *testing.T, rows []prefetchRow) (*httptest.Server, int64) {
+	t.Helper()
+	data := writeTestData(t, rows,
+		parquet.MaxRowsPerRowGroup(1000),
+		parquet.DataPageRowCountLimit(100),
+		parquet.BloomFilters(parquet.SplitBlockFilter(10, "name")),
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.ServeContent(w, r, "file.parquet", time.Time{}, bytes.NewReader(data))
+	}))
+	t.Cleanup(server.Close)
+	return server, int64(len(data))
+}
+
+func readPrefetchRows(t *testing.T, file *parquet.File, options ...parquet.ReaderOption) []prefetchRow {
+	t.Helper()
+	reader := parquet.NewGenericReader[prefetchRow](file, options...)
+	defer reader.Close()
+	rows := make([]prefetchRow, file.NumRows()+1)
+	n, err := reader.Read(rows)
+	if err != nil && err != io.EOF {
+		t.Fatal(err)
+	}
+	return rows[:n]
+}
+
+func TestPrefetchColumns(t *testing.T) {
+	rows := prefetchRows()
+	server, size := servePrefetchFile(t, rows)
+
+	want := make([]prefetchRow, len(rows))
+	for i, row := range rows {
+		want[i] = prefetchRow{ID: row.ID, Name: row.Name}
+	}
+
+	input := &httpRangeReaderAt{url: server.URL}
+	file, err := parquet.OpenFile(input, size)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := readPrefetchRows(t, file, parquet.Projection([]string{"id"}, []string{"name"})); !reflect.DeepEqual(got, want) {
+		t.Fatal("wrong rows read without prefetching")
+	}
+	requestsWithoutPrefetch := input.requests.Load()
+
+	input = &httpRangeReaderAt{url: server.URL}
+	file, err = parquet.OpenFile(input, size, parquet.PrefetchColumns([]string{"id"}, []string{"name"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The magic header, the end of the file, and the merged ranges of the
+	// page index, bloom filters, and column chunks.
+	requestsToOpen := input.requests.Load()
+	if requestsToOpen > 4 {
+		t.Errorf("too many requests to open the file: %d", requestsToOpen)
+	}
+	if got := readPrefetchRows(t, file, parquet.Projection([]string{"id"}, []string{"name"})); !reflect.DeepEqual(got, want) {
+		t.Fatal("wrong rows read with prefetching")
+	}
+	if requests := input.requests.Load(); requests != requestsToOpen {
+		t.Errorf("prefetched columns were read from the server: %d requests", requests-requestsToOpen)
+	}
+	if requestsToOpen >= requestsWithoutPrefetch {
+		t.Errorf("prefetching did not reduce the number of requests: with=%d without=%d", requestsToOpen, requestsWithoutPrefetch)
+	}
+
+	// The columns which were not prefetched are still read from the server.
+	if got := readPrefetchRows(t, file); !reflect.DeepEqual(got, rows) {
+		t.Error("wrong rows read from columns which were not prefetched")
+	}
+	if ok, err := file.RowGroups()[0].ColumnChunks()[1].BloomFilter().Check(parquet.ValueOf("name-42")); !ok || err != nil {
+		t.Errorf("bloom filter does not contain name-42: ok=%t err=%v", ok, err)
+	}
+}
+
+func TestPrefetchColumnsSmallRanges(t *testing.T) {
+	rows := prefetchRows()
+	server, size := servePrefetchFile(t, rows)
+
+	input := &httpRangeReaderAt{url: server.URL}
+	file, err := parquet.OpenFile(input, size,
+		parquet.PrefetchColumns(),
+		parquet.PrefetchMaxGap(1),
+		parquet.PrefetchMaxRangeSize(1000),
+		parquet.PrefetchConcurrency(8),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	requestsToOpen := input.requests.Load()
+	if got := readPrefetchRows(t, file); !reflect.DeepEqual(got, rows) {
+		t.Error("wrong rows read with prefetching")
+	}
+	if requests := input.requests.Load(); requests != requestsToOpen {
+		t.Errorf("prefetched columns were read from the server: %d requests", requests-requestsToOpen)
+	}
+}
+
+func TestPrefetchColumnsUnknownColumn(t *testing.T) {
+	server, size := servePrefetchFile(t, prefetchRows())
+	input := &httpRangeReaderAt{url: server.URL}
+	if _, err := parquet.OpenFile(input, size, parquet.PrefetchColumns([]string{"missing"})); err == nil {
+		t.Error("expected an error prefetching a column which does not exist")
+	}
+	if _, err := parquet.OpenFile(input, size, parquet.PrefetchColumns(), parquet.PrefetchConcurrency(-1)); err == nil {
+		t.Error("expected an error for an invalid prefetch concurrency")
+	}
+}
+
+// sectionReaderAt records the sections of the file that are announced by
+// OpenFile before reading them.
+type sectionReaderAt struct {
+	io.ReaderAt
+	sections []string
+}
+
+func (r *sectionReaderAt) SetMagicFooterSection(offset, length int64) {
+	r.sections = append(r.sections, "magic footer")
+}
+
+func (r *sectionReaderAt) SetFooterSection(offset, length int64) {
+	r.sections = append(r.sections, "footer")
+}
+
+func (r *sectionReaderAt) SetColumnIndexSection(offset, length int64) {
+	r.sections = append(r.sections, "column index")
+}
+
+func (r *sectionReaderAt) SetOffsetIndexSection(offset, length int64) {
+	r.sections = append(r.sections, "offset index")
+}
+
+func (r *sectionReaderAt) SetBloomFilterSection(offset, length int64) {
+	r.sections = append(r.sections, "bloom filter")
+}
+
+func TestPrefetchColumnsSections(t *testing.T) {
+	server, size := servePrefetchFile(t, prefetchRows())
+
+	sections := func(options ...parquet.FileOption) []string {
+		input := &sectionReaderAt{ReaderAt: &httpRangeReaderAt{url: server.URL}}
+		if _, err := parquet.OpenFile(input, size, options...); err != nil {
+			t.Fatal(err)
+		}
+		slices.Sort(input.sections)
+		return slices.Compact(input.sections)
+	}
+
+	want := sections()
+	if got := sections(parquet.PrefetchColumns()); !reflect.DeepEqual(got, want) {
+		t.Errorf("wrong sections announced with prefetching:\nwant: %q\ngot:  %q", want, got)
+	}
+}
@@ -1,6 +1,7 @@
 package parquet_test
 
 import (
+	"bytes"
 	"errors"
 	"io"
 	"os"
@@ -212,3 +213,91 @@ func TestFileKeyValueMetadata(t *testing.T) {
 		}
 	}
 }
+
+func TestFilePageChecksums(t *testing.T) {
+	type Row struct {
+		ID   int64  `parquet:"id"`
+		Name string `parquet:"name,dict"`
+	}
+
+	rows := make([]Row, 100)
+	for i := range rows {
+		rows[i] = Row{ID: int64(i), Name: strings.Repeat("x", i%10)}
+	}
+
+	writeFile := func(t *testing.T, options ...parquet.WriterOption) []byte {
+		t.Helper()
+		buffer := new(bytes.Buffer)
+		writer := parquet.NewGenericWriter[Row](buffer, options...)
+		if _, err := writer.Write(rows); err != nil {
+			t.Fatal(err)
+		}
+		if err := writer.Close(); err != nil {
+			t.Fatal(err)
+		}
+		return buffer.Bytes()
+	}
+
+	readPages := func(data []byte, options ...parquet.FileOption) error {
+		f, err := parquet.OpenFile(bytes.NewReader(data), int64(len(data)), options...)
+		if err != nil {
+			return err
+		}
+		for _, rowGroup := range f.RowGroups() {
+			for _, chunk := range rowGroup.ColumnChunks() {
+				pages := chunk.Pages()
+				for {
+					_, err := pages.ReadPage()
+					if err == io.EOF {
+						break
+					}
+					if err != nil {
+						pages.Close()
+						return err
+					}
+				}
+				pages.Close()
+			}
+		}
+		return nil
+	}
+
+	for _, version := range []int{1, 2} {
+		withChecksums := writeFile(t, parquet.DataPageVersion(version))
+		withoutChecksums := writeFile(t, parquet.DataPageVersion(version), parquet.PageChecksums(false))
+
+		if err := readPages(withChecksums, parquet.RequirePageChecksums(true)); err != nil {
+			t.Errorf("reading pages with checksums: %v", err)
+		}
+		if err := readPages(withoutChecksums); err != nil {
+			t.Errorf("reading pages without checksums: %v", err)
+		}
+		if err := readPages(withoutChecksums, parquet.RequirePageChecksums(true)); !errors.Is(err, parquet.ErrMissingPageChecksum) {
+			t.Errorf("expected ErrMissingPageChecksum but got %v", err)
+		}
+
+		// Checksums can also be disabled by passing a writer configuration
+		// as option.
+		withoutChecksums = writeFile(t, parquet.DataPageVersion(version), &parquet.WriterConfig{SkipPageChecksums: true})
+		if err := readPages(withoutChecksums, parquet.RequirePageChecksums(true)); !errors.Is(err, parquet.ErrMissingPageChecksum) {
+			t.Errorf("expected ErrMissingPageChecksum but got %v", err)
+		}
+
+		f, err := parquet.OpenFile(bytes.NewReader(withChecksums), int64(len(withChecksums)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, chunk := range f.RowGroups()[0].ColumnChunks() {
+			offsetIndex, err := chunk.OffsetIndex()
+			if err != nil {
+				t.Fatal(err)
+			}
+			corrupted := bytes.Clone(withChecksums)
+			lastByte := offsetIndex.Offset(0) + offsetIndex.CompressedPageSize(0) - 1
+			corrupted[lastByte] ^= 0xFF
+			if err := readPages(corrupted); !errors.Is(err, parquet.ErrCorrupted) {
+				t.Errorf("expected ErrCorrupted but got %v", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package parquet
+
+import (
+	"encoding/binary"
+	"math"
+)
+
+// The parquet FLOAT16 logical type stores IEEE 754 half-precision floating
+// point values as little-endian FIXED_LEN_BYTE_ARRAY(2). Go has no native
+// half-precision type, values are converted to and from float32, which can
+// represent every half-precision value exactly.
+
+const float16Size = 2
+
+func float16FromBytes(b []byte) float32 {
+	return float16ToFloat32(binary.LittleEndian.Uint16(b))
+}
+
+func appendFloat16(b []byte, f float32) []byte {
+	return binary.LittleEndian.AppendUint16(b, float32ToFloat16(f))
+}
+
+func float16ToFloat32(h uint16) float32 {
+	sign := uint32(h&0x8000) << 16
+	exp := uint32(h>>10) & 0x1F
+	mant := uint32(h & 0x3FF)
+
+	switch exp {
+	case 0x1F: // infinity or NaN
+		return math.Float32frombits(sign | 0x7F800000 | mant<<13)
+	case 0: // zero or subnormal
+		f := float32(mant) / (1 << 24)
+		if sign != 0 {
+			f = -f
+		}
+		return f
+	default:
+		return math.Float32frombits(sign | (exp+(127-15))<<23 | mant<<13)
+	}
+}
+
+func float32ToFloat16(f float32) uint16 {
+	b := math.Float32bits(f)
+	sign := uint16(b>>16) & 0x8000
+	exp := int32(b>>23) & 0xFF
+	mant := b & 0x7FFFFF
+
+	if exp == 0xFF {
+		if mant != 0 {
+			return sign | 0x7E00 // quiet NaN
+		}
+		return sign | 0x7C00
+	}
+
+	switch e := exp - 127 + 15; {
+	case e >= 0x1F: // overflow
+		return sign | 0x7C00
+	case e <= 0: // subnormal or underflow
+		if e < -10 {
+			return sign
+		}
+		mant |= 0x800000
+		shift := uint32(14 - e)
+		return sign | uint16(roundToNearestEven(mant, shift))
+	default:
+		// Rounding may carry into the exponent, which correctly produces the
+		// next power of two or infinity.
+		return sign | uint16(roundToNearestEven(uint32(e)<<23|mant, 13))
+	}
+}
+
+func roundToNearestEven(bits, shift uint32) uint32 {
+	half := uint32(1) << (shift - 1)
+	rem := bits & (1<<shift - 1)
+	bits >>= shift
+	if rem > half || (rem == half && bits&1 != 0) {
+		bits++
+	}
+	return bits
+}
+
+// float16Bounds tracks the min and max values of a sequence of little-endian
+// FLOAT16 values. NaN values are ignored unless all values are NaN.
+type float16Bounds struct {
+	min, max           []byte
+	minValue, maxValue float32
+}
+
+func (b *float16Bounds) observe(v []byte) {
+	f := float16FromBytes(v)
+	switch {
+	case f != f: // NaN
+		if b.min == nil {
+			b.min, b.max = v, v
+			b.minValue, b.maxValue = f, f
+		}
+	case b.min == nil || b.minValue != b.minValue:
+		b.min, b.max = v, v
+		b.minValue, b.maxValue = f, f
+	case f < b.minValue:
+		b.min, b.minValue = v, f
+	case f > b.maxValue:
+		b.max, b.maxValue = v, f
+	}
+}
+
+func boundsFloat16(data []byte) (min, max []byte) {
+	b := float16Bounds{}
+	for i := 0; i < len(data); i += float16Size {
+		b.observe(data[i : i+float16Size])
+	}
+	return b.min, b.max
+}
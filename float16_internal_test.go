@@ -0,0 +1,71 @@
+package parquet
+
+import (
+	"math"
+	"testing"
+)
+
+func TestFloat16Conversion(t *testing.T) {
+	for i := 0; i <= math.MaxUint16; i++ {
+		h := uint16(i)
+		f := float16ToFloat32(h)
+		if f != f {
+			if g := float32ToFloat16(f); g&0x7C00 != 0x7C00 || g&0x3FF == 0 {
+				t.Errorf("0x%04X: NaN converted to 0x%04X", h, g)
+			}
+			continue
+		}
+		if g := float32ToFloat16(f); g != h {
+			t.Errorf("0x%04X: round trip through %g produced 0x%04X", h, f, g)
+		}
+	}
+
+	tests := []struct {
+		value float32
+		bits  uint16
+	}{
+		{0, 0x0000},
+		{1, 0x3C00},
+		{-2, 0xC000},
+		{65504, 0x7BFF},
+		{65520, 0x7C00}, // rounds to infinity
+		{1e10, 0x7C00},
+		{float32(math.Inf(-1)), 0xFC00},
+		{1.0 + 1.0/2048, 0x3C00},        // tie rounds to even
+		{1.0 + 3.0/2048, 0x3C02},        // tie rounds to even
+		{1.0 + 1.0/2048 + 1e-6, 0x3C01}, // above half rounds up
+		{0x1p-24, 0x0001},               // smallest subnormal
+		{0x1p-25, 0x0000},               // tie rounds to even (zero)
+		{0x1p-26, 0x0000},
+	}
+
+	for _, test := range tests {
+		if bits := float32ToFloat16(test.value); bits != test.bits {
+			t.Errorf("%g: want=0x%04X got=0x%04X", test.value, test.bits, bits)
+		}
+	}
+}
+
+func TestBoundsFloat16(t *testing.T) {
+	nan := appendFloat16(nil, float32(math.NaN()))
+	data := appendFloat16(nil, 1)
+	data = append(data, nan...)
+	data = appendFloat16(data, -3)
+	data = appendFloat16(data, 2)
+
+	min, max := boundsFloat16(data)
+	if f := float16FromBytes(min); f != -3 {
+		t.Errorf("wrong min value: want=-3 got=%g", f)
+	}
+	if f := float16FromBytes(max); f != 2 {
+		t.Errorf("wrong max value: want=2 got=%g", f)
+	}
+
+	min, max = boundsFloat16(append(nan, appendFloat16(nil, 5)...))
+	if f := float16FromBytes(min); f != 5 {
+		t.Errorf("wrong min value: want=5 got=%g", f)
+	}
+	if f := float16FromBytes(max); f != 5 {
+		t.Errorf("wrong max value: want=5 got=%g", f)
+	}
+}
@@ -0,0 +1,179 @@
+package parquet_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/parquet-go/parquet-go"
+)
+
+type float16Row struct {
+	ID       int64   `parquet:"id"`
+	Value    float32 `parquet:"value,float16"`
+	Double   float64 `parquet:"double,float16"`
+	Optional float32 `parquet:"optional,float16,optional"`
+	Dict     float32 `parquet:"dict,float16,dict"`
+}
+
+func float16Rows(n int) []float16Row {
+	rows := make([]float16Row, n)
+	for i := range rows {
+		f := float32(i-n/2) / 4
+		rows[i] = float16Row{
+			ID:     int64(i),
+			Value:  f,
+			Double: float64(-f),
+			Dict:   float32(i%5) - 2,
+		}
+		if i%3 != 0 {
+			rows[i].Optional = f
+		}
+	}
+	return rows
+}
+
+func float16Value(b []byte) float32 {
+	h := binary.LittleEndian.Uint16(b)
+	switch exp := h >> 10 & 0x1F; exp {
+	case 0:
+		f := float32(h&0x3FF) / (1 << 24)
+		if h&0x8000 != 0 {
+			f = -f
+		}
+		return f
+	case 0x1F:
+		return float32(math.NaN())
+	default:
+		bits := uint32(h&0x8000)<<16 | uint32(exp+112)<<23 | uint32(h&0x3FF)<<13
+		return math.Float32frombits(bits)
+	}
+}
+
+func TestFloat16(t *testing.T) {
+	rows := float16Rows(100)
+
+	buffer := new(bytes.Buffer)
+	writer := parquet.NewGenericWriter[float16Row](buffer, parquet.PageBufferSize(64))
+	if _, err := writer.Write(rows); err != nil {
+		t.Fatal(err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := parquet.OpenFile(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{"value", "double", "optional", "dict"} {
+		leaf, ok := f.Schema().Lookup(name)
+		if !ok {
+			t.Fatalf("missing column %q", name)
+		}
+		if lt := leaf.Node.Type().LogicalType(); lt == nil || lt.Float16 == nil {
+			t.Errorf("column %q has the wrong logical type: %v", name, lt)
+		}
+		if length := leaf.Node.Type().Length(); length != 2 {
+			t.Errorf("column %q has the wrong length: %d", name, length)
+		}
+	}
+
+	metadata := f.Metadata().RowGroups[0].Columns[1].MetaData
+	if min := float16Value(metadata.Statistics.MinValue); min != rows[0].Value {
+		t.Errorf("wrong min value: want=%g got=%g", rows[0].Value, min)
+	}
+	if max := float16Value(metadata.Statistics.MaxValue); max != rows[len(rows)-1].Value {
+		t.Errorf("wrong max value: want=%g got=%g", rows[len(rows)-1].Value, max)
+	}
+
+	columnIndex, err := f.RowGroups()[0].ColumnChunks()[1].ColumnIndex()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if columnIndex.NumPages() < 2 {
+		t.Fatalf("expected multiple pages but got %d", columnIndex.NumPages())
+	}
+	if !columnIndex.IsAscending() {
+		t.Error("column index of ascending values is not ascending")
+	}
+	if min := float16Value(columnIndex.MinValue(0).ByteArray()); min != rows[0].Value {
+		t.Errorf("wrong min value in column index: want=%g got=%g", rows[0].Value, min)
+	}
+
+	got := make([]float16Row, len(rows))
+	reader := parquet.NewGenericReader[float16Row](f)
+	if n, err := reader.Read(got); err != nil && err != io.EOF {
+		t.Fatal(err)
+	} else if n != len(rows) {
+		t.Fatalf("wrong number of rows: want=%d got=%d", len(rows), n)
+	}
+	reader.Close()
+
+	if !reflect.DeepEqual(rows, got) {
+		t.Error("rows mismatch")
+	}
+}
+
+func TestFloat16Writer(t *testing.T) {
+	type Row struct {
+		Value float32 `parquet:"value,float16"`
+	}
+
+	buffer := new(bytes.Buffer)
+	writer := parquet.NewWriter(buffer, parquet.SchemaOf(Row{}))
+	for _, value := range []float32{1.5, -0.25, 65504} {
+		if err := writer.Write(Row{Value: value}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := parquet.Read[Row](bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Row{{1.5}, {-0.25}, {65504}}
+	if !reflect.DeepEqual(rows, want) {
+		t.Errorf("rows mismatch: want=%v got=%v", want, rows)
+	}
+}
+
+func TestFloat16Compare(t *testing.T) {
+	typ := parquet.Float16().Type()
+	values := []float32{-2, -0.5, 0, 0.5, 2}
+
+	for i := range values {
+		for j := range values {
+			a := parquet.FixedLenByteArrayValue(binary.LittleEndian.AppendUint16(nil, float32ToHalf(values[i])))
+			b := parquet.FixedLenByteArrayValue(binary.LittleEndian.AppendUint16(nil, float32ToHalf(values[j])))
+			want := 0
+			switch {
+			case i < j:
+				want = -1
+			case i > j:
+				want = +1
+			}
+			if cmp := typ.Compare(a, b); cmp != want {
+				t.Errorf("compare(%g, %g): want=%d got=%d", values[i], values[j], want, cmp)
+			}
+		}
+	}
+}
+
+// float32ToHalf converts values that are exactly representable as half-precision
+// floating point numbers.
+func float32ToHalf(f float32) uint16 {
+	if f == 0 {
+		return 0
+	}
+	bits := math.Float32bits(f)
+	exp := (bits>>23)&0xFF - 112
+	return uint16(bits>>16)&0x8000 | uint16(exp)<<10 | uint16(bits>>13)&0x3FF
+}
@@ -70,6 +70,72 @@ func (t FieldRepetitionType) String() string {
 	}
 }
 
+// A structure for capturing metadata for estimating the unencoded,
+// uncompressed size of data written. This is useful for readers to estimate
+// how much memory is needed to reconstruct data in their memory model and for
+// fine-grained filter pushdown on nested structures (the histograms contained
+// in this structure can help determine the number of nulls at a particular
+// nesting level and maximum length of lists).
+type SizeStatistics struct {
+	// The number of physical bytes stored for BYTE_ARRAY data values assuming
+	// no encoding. This is exclusive of the bytes needed to store the length
+	// of each byte array. In other words, this field is equivalent to the
+	// `(size of PLAIN-ENCODING the byte array values) - (4 bytes * number of
+	// values written)`. To determine unencoded sizes of other types readers
+	// can use schema information multiplied by the number of non-null and
+	// null values. The number of null/non-null values can be inferred from
+	// the histograms below.
+	//
+	// For example, if a column chunk is dictionary-encoded with dictionary
+	// ["a", "bc", "cde"], and a data page contains the indices [0, 0, 1, 2],
+	// then this value for that data page should be 7 (1 + 1 + 2 + 3).
+	//
+	// This field should only be set for types that use BYTE_ARRAY as their
+	// physical type.
+	UnencodedByteArrayDataBytes int64 `thrift:"1,optional"`
+
+	// When present, there is expected to be one element corresponding to each
+	// repetition (i.e. size=max repetition_level+1) where each element
+	// represents the number of times the repetition level was observed in the
+	// data.
+	//
+	// This field may be omitted if max_repetition_level is 0 without loss of
+	// information.
+	RepetitionLevelHistogram []int64 `thrift:"2,optional"`
+
+	// Same as repetition_level_histogram except for definition levels.
+	//
+	// This field may be omitted if max_definition_level is 0 or 1 without
+	// loss of information.
+	DefinitionLevelHistogram []int64 `thrift:"3,optional"`
+}
+
+// Bounding box of GEOMETRY or GEOGRAPHY values.
+//
+// The X and Y dimensions are always present, Z and M are only set when the
+// geometries have those dimensions. For GEOGRAPHY values, Xmin may be greater
+// than Xmax when the bounding box wraps around the antimeridian.
+type BoundingBox struct {
+	Xmin float64  `thrift:"1,required"`
+	Xmax float64  `thrift:"2,required"`
+	Ymin float64  `thrift:"3,required"`
+	Ymax float64  `thrift:"4,required"`
+	Zmin *float64 `thrift:"5,optional"`
+	Zmax *float64 `thrift:"6,optional"`
+	Mmin *float64 `thrift:"7,optional"`
+	Mmax *float64 `thrift:"8,optional"`
+}
+
+// Statistics specific to GEOMETRY and GEOGRAPHY logical types.
+type GeospatialStatistics struct {
+	// Bounding box of all the geometries of the column chunk.
+	BBox *BoundingBox `thrift:"1,optional"`
+
+	// Sorted set of the ISO WKB geometry type codes of all the geometries of
+	// the column chunk (e.g. 1 for Point, 1003 for Polygon Z).
+	GeospatialTypes []int32 `thrift:"2,optional"`
+}
+
 // Statistics per row group and per page.
 // All fields are optional.
 type Statistics struct {
@@ -122,6 +188,91 @@ type NullType struct{}
 
 func (*NullType) String() string { return "NULL" }
 
+// Logical type to annotate IEEE 754 half-precision floating point values.
+//
+// Allowed for FIXED[2], values must be encoded as little-endian 2 byte
+// sequences.
+type Float16Type struct{}
+
+func (*Float16Type) String() string { return "FLOAT16" }
+
+// Logical type to annotate groups storing semi-structured values using the
+// parquet variant binary encoding.
+//
+// Allowed for groups with a required binary metadata field, a binary value
+// field, and an optional typed_value field holding shredded values.
+type VariantType struct {
+	SpecificationVersion int8 `thrift:"1,optional"`
+}
+
+func (t *VariantType) String() string {
+	if t.SpecificationVersion == 0 {
+		return "VARIANT"
+	}
+	return fmt.Sprintf("VARIANT(%d)", t.SpecificationVersion)
+}
+
+// Logical type to annotate geospatial features in the Well-Known Binary (WKB)
+// format with linear/planar edges interpolation.
+//
+// Allowed for BINARY, the CRS defaults to OGC:CRS84 when not set.
+type GeometryType struct {
+	CRS string `thrift:"1,optional"`
+}
+
+func (t *GeometryType) String() string {
+	if t.CRS == "" {
+		return "GEOMETRY"
+	}
+	return fmt.Sprintf("GEOMETRY(%s)", t.CRS)
+}
+
+// Interpolation algorithm of edges between the vertices of GEOGRAPHY values.
+type EdgeInterpolationAlgorithm int32
+
+const (
+	Spherical EdgeInterpolationAlgorithm = 0
+	Vincenty  EdgeInterpolationAlgorithm = 1
+	Thomas    EdgeInterpolationAlgorithm = 2
+	Andoyer   EdgeInterpolationAlgorithm = 3
+	Karney    EdgeInterpolationAlgorithm = 4
+)
+
+func (a EdgeInterpolationAlgorithm) String() string {
+	switch a {
+	case Spherical:
+		return "SPHERICAL"
+	case Vincenty:
+		return "VINCENTY"
+	case Thomas:
+		return "THOMAS"
+	case Andoyer:
+		return "ANDOYER"
+	case Karney:
+		return "KARNEY"
+	default:
+		return "EdgeInterpolationAlgorithm(?)"
+	}
+}
+
+// Logical type to annotate geospatial features in the Well-Known Binary (WKB)
+// format with an explicit (non-linear/non-planar) edges interpolation
+// algorithm.
+//
+// Allowed for BINARY, the CRS defaults to OGC:CRS84 when not set and must be
+// a geographic CRS.
+type GeographyType struct {
+	CRS       string                     `thrift:"1,optional"`
+	Algorithm EdgeInterpolationAlgorithm `thrift:"2,optional"`
+}
+
+func (t *GeographyType) String() string {
+	if t.CRS == "" {
+		return fmt.Sprintf("GEOGRAPHY(%s)", t.Algorithm)
+	}
+	return fmt.Sprintf("GEOGRAPHY(%s,%s)", t.CRS, t.Algorithm)
+}
+
 // Decimal logical type annotation
 //
 // To maintain forward-compatibility in v1, implementations using this logical
@@ -240,11 +391,16 @@ type LogicalType struct { // union
 	Timestamp *TimestampType `thrift:"8"`
 
 	// 9: reserved for Interval
-	Integer *IntType  `thrift:"10"` // use ConvertedType Int* or Uint*
-	Unknown *NullType `thrift:"11"` // no compatible ConvertedType
-	Json    *JsonType `thrift:"12"` // use ConvertedType JSON
-	Bson    *BsonType `thrift:"13"` // use ConvertedType BSON
-	UUID    *UUIDType `thrift:"14"` // no compatible ConvertedType
+	Integer *IntType     `thrift:"10"` // use ConvertedType Int* or Uint*
+	Unknown *NullType    `thrift:"11"` // no compatible ConvertedType
+	Json    *JsonType    `thrift:"12"` // use ConvertedType JSON
+	Bson    *BsonType    `thrift:"13"` // use ConvertedType BSON
+	UUID    *UUIDType    `thrift:"14"` // no compatible ConvertedType
+	Float16 *Float16Type `thrift:"15"` // no compatible ConvertedType
+	Variant *VariantType `thrift:"16"` // no compatible ConvertedType
+
+	Geometry  *GeometryType  `thrift:"17"` // no compatible ConvertedType
+	Geography *GeographyType `thrift:"18"` // no compatible ConvertedType
 }
 
 func (t *LogicalType) String() string {
@@ -275,6 +431,14 @@ func (t *LogicalType) String() string {
 		return t.Bson.String()
 	case t.UUID != nil:
 		return t.UUID.String()
+	case t.Float16 != nil:
+		return t.Float16.String()
+	case t.Variant != nil:
+		return t.Variant.String()
+	case t.Geometry != nil:
+		return t.Geometry.String()
+	case t.Geography != nil:
+		return t.Geography.String()
 	default:
 		return ""
 	}
@@ -743,6 +907,15 @@ type ColumnMetaData struct {
 
 	// Byte offset from beginning of file to Bloom filter data.
 	BloomFilterOffset int64 `thrift:"14,optional"`
+
+	// Optional statistics to help estimate total memory when converted to
+	// in-memory representations. The histograms contained in these statistics
+	// can also be useful in some cases for more fine-grained nullability/list
+	// length filter pushdown.
+	SizeStatistics SizeStatistics `thrift:"16,optional"`
+
+	// Optional statistics specific for GEOMETRY and GEOGRAPHY logical types.
+	GeospatialStatistics GeospatialStatistics `thrift:"17,optional"`
 }
 
 type EncryptionWithFooterKey struct{}
@@ -895,6 +1068,12 @@ type OffsetIndex struct {
 	// PageLocations, ordered by increasing PageLocation.offset. It is required
 	// that page_locations[i].first_row_index < page_locations[i+1].first_row_index.
 	PageLocations []PageLocation `thrift:"1,required"`
+
+	// Unencoded/uncompressed size for BYTE_ARRAY types.
+	//
+	// See documentation for unencoded_byte_array_data_bytes in SizeStatistics
+	// for more details on this field.
+	UnencodedByteArrayDataBytes []int64 `thrift:"2,optional"`
 }
 
 // Description for ColumnIndex.
@@ -926,6 +1105,21 @@ type ColumnIndex struct {
 
 	// A list containing the number of null values for each page.
 	NullCounts []int64 `thrift:"5,optional"`
+
+	// Contains repetition level histograms for each page concatenated
+	// together. The repetition_level_histogram field on SizeStatistics
+	// contains more details.
+	//
+	// When present the length should always be (number of pages *
+	// (max_repetition_level + 1)) elements.
+	//
+	// Element 0 is the first element of the histogram for the first page.
+	// Element (max_repetition_level + 1) is the first element of the histogram
+	// for the second page.
+	RepetitionLevelHistograms []int64 `thrift:"6,optional"`
+
+	// Same as repetition_level_histograms except for definitions levels.
+	DefinitionLevelHistograms []int64 `thrift:"7,optional"`
 }
 
 type AesGcmV1 struct {
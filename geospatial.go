@@ -0,0 +1,530 @@
+package parquet
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+
+	"github.com/parquet-go/parquet-go/format"
+)
+
+// BoundingBox represents a rectangle in the X/Y plane of the coordinate
+// reference system of GEOMETRY or GEOGRAPHY columns.
+//
+// For GEOGRAPHY columns, XMin may be greater than XMax to represent a box
+// which wraps around the antimeridian.
+type BoundingBox struct {
+	XMin, YMin, XMax, YMax float64
+}
+
+// Intersects returns true if b and box have at least one point in common.
+func (b BoundingBox) Intersects(box BoundingBox) bool {
+	return b.YMin <= box.YMax && box.YMin <= b.YMax && xRangesIntersect(b.XMin, b.XMax, box.XMin, box.XMax)
+}
+
+func xRangesIntersect(min1, max1, min2, max2 float64) bool {
+	// A range where min > max wraps around, it is the union of [min:+inf] and
+	// [-inf:max].
+	switch wrap1, wrap2 := min1 > max1, min2 > max2; {
+	case wrap1 && wrap2:
+		return true
+	case wrap1:
+		return max2 >= min1 || min2 <= max1
+	case wrap2:
+		return max1 >= min2 || min1 <= max2
+	default:
+		return min1 <= max2 && min2 <= max1
+	}
+}
+
+// GeospatialBoundingBox returns the bounding box of the geometries in the
+// column chunk, as recorded in its geospatial statistics.
+//
+// The method returns false if the column chunk was not read from a parquet
+// file or has no bounding box, for example because it does not contain
+// GEOMETRY or GEOGRAPHY values or the writer did not compute it.
+func GeospatialBoundingBox(chunk ColumnChunk) (BoundingBox, bool) {
+	c, ok := chunk.(*fileColumnChunk)
+	if !ok || c.chunk.MetaData.GeospatialStatistics.BBox == nil {
+		return BoundingBox{}, false
+	}
+	bbox := c.chunk.MetaData.GeospatialStatistics.BBox
+	return BoundingBox{
+		XMin: bbox.Xmin,
+		YMin: bbox.Ymin,
+		XMax: bbox.Xmax,
+		YMax: bbox.Ymax,
+	}, true
+}
+
+// IntersectsBoundingBox returns a row group predicate which reports whether
+// the geometries in the column at path may intersect with bbox.
+//
+// The predicate is conservative: it returns false only when the geospatial
+// statistics of the column chunk prove that none of its geometries intersect
+// with bbox, which makes it suitable to prune row groups before reading them.
+// Row groups missing the column or its statistics are always retained.
+//
+//	for _, rowGroup := range file.RowGroups() {
+//		if !intersects(rowGroup) {
+//			continue
+//		}
+//		...
+//	}
+func IntersectsBoundingBox(path []string, bbox BoundingBox) func(RowGroup) bool {
+	return func(rowGroup RowGroup) bool {
+		leaf, ok := rowGroup.Schema().Lookup(path...)
+		if !ok {
+			return true
+		}
+		chunks := rowGroup.ColumnChunks()
+		if leaf.ColumnIndex >= len(chunks) {
+			return true
+		}
+		chunkBox, ok := GeospatialBoundingBox(chunks[leaf.ColumnIndex])
+		return !ok || chunkBox.Intersects(bbox)
+	}
+}
+
+// Geometry type codes of the Well-Known Binary format.
+const (
+	wkbPoint              = 1
+	wkbLineString         = 2
+	wkbPolygon            = 3
+	wkbMultiPoint         = 4
+	wkbMultiLineString    = 5
+	wkbMultiPolygon       = 6
+	wkbGeometryCollection = 7
+
+	// Flags of the extended WKB format used by PostGIS.
+	ewkbZ    = 0x80000000
+	ewkbM    = 0x40000000
+	ewkbSRID = 0x20000000
+
+	// Limits the nesting of geometry collections to protect against stack
+	// exhaustion on malicious inputs.
+	wkbMaxDepth = 64
+)
+
+// geospatialStatistics accumulates the geospatial statistics of the values
+// written to a column chunk.
+type geospatialStatistics struct {
+	// The edges of GEOGRAPHY values are curves on the surface of the earth,
+	// which may extend past the latitudes of their vertices and cross the
+	// antimeridian; the range of longitudes is then tracked in lng instead of
+	// xmin and xmax.
+	geography bool
+	// Set when a value is not valid WKB, the statistics are then omitted
+	// since they cannot be trusted.
+	invalid bool
+
+	xmin, xmax float64
+	ymin, ymax float64
+	zmin, zmax float64
+	mmin, mmax float64
+	lng        longitudeInterval
+	types      []int32
+}
+
+func newGeospatialStatistics(t Type) *geospatialStatistics {
+	lt := t.LogicalType()
+	switch {
+	case lt == nil:
+		return nil
+	case lt.Geometry != nil, lt.Geography != nil:
+		s := &geospatialStatistics{geography: lt.Geography != nil}
+		s.reset()
+		return s
+	default:
+		return nil
+	}
+}
+
+func (s *geospatialStatistics) reset() {
+	s.invalid = false
+	s.xmin, s.xmax = math.Inf(+1), math.Inf(-1)
+	s.ymin, s.ymax = math.Inf(+1), math.Inf(-1)
+	s.zmin, s.zmax = math.Inf(+1), math.Inf(-1)
+	s.mmin, s.mmax = math.Inf(+1), math.Inf(-1)
+	s.lng = longitudeInterval{}
+	s.types = s.types[:0]
+}
+
+func (s *geospatialStatistics) observePage(page Page) {
+	if s.invalid {
+		return
+	}
+	values := page.Values()
+	buffer := make([]Value, 64)
+	for {
+		n, err := values.ReadValues(buffer)
+		for _, v := range buffer[:n] {
+			if v.IsNull() {
+				continue
+			}
+			if err := s.observe(v.byteArray()); err != nil {
+				s.invalid = true
+				return
+			}
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+func (s *geospatialStatistics) observe(wkb []byte) error {
+	typ, _, err := s.readGeometry(wkb, 0)
+	if err != nil {
+		return err
+	}
+	if i, found := slices.BinarySearch(s.types, typ); !found {
+		s.types = slices.Insert(s.types, i, typ)
+	}
+	return nil
+}
+
+// readGeometry reads the WKB geometry at the beginning of b, returning its
+// ISO type code and the number of bytes it occupied.
+func (s *geospatialStatistics) readGeometry(b []byte, depth int) (typ int32, n int, err error) {
+	if depth > wkbMaxDepth {
+		return 0, 0, fmt.Errorf("invalid WKB: geometry collections nested deeper than %d levels", wkbMaxDepth)
+	}
+	if len(b) < 5 {
+		return 0, 0, fmt.Errorf("invalid WKB: %d bytes are too short to contain a geometry header", len(b))
+	}
+
+	var order binary.ByteOrder
+	switch b[0] {
+	case 0:
+		order = binary.BigEndian
+	case 1:
+		order = binary.LittleEndian
+	default:
+		return 0, 0, fmt.Errorf("invalid WKB: unknown byte order %d", b[0])
+	}
+
+	code := order.Uint32(b[1:])
+	n = 5
+	hasZ, hasM := code&ewkbZ != 0, code&ewkbM != 0
+	if code&ewkbSRID != 0 {
+		n += 4
+	}
+	code &^= ewkbZ | ewkbM | ewkbSRID
+	switch code / 1000 {
+	case 1:
+		hasZ = true
+	case 2:
+		hasM = true
+	case 3:
+		hasZ, hasM = true, true
+	}
+	base := code % 1000
+	typ = int32(base)
+	if hasZ {
+		typ += 1000
+	}
+	if hasM {
+		typ += 2000
+	}
+
+	r := wkbReader{stats: s, order: order, hasZ: hasZ, hasM: hasM, b: b, n: n}
+	switch base {
+	case wkbPoint:
+		r.readPoint()
+	case wkbLineString:
+		r.readPoints()
+	case wkbPolygon:
+		r.readRings()
+	case wkbMultiPoint, wkbMultiLineString, wkbMultiPolygon, wkbGeometryCollection:
+		for i, count := 0, r.readCount(); i < count && r.err == nil; i++ {
+			if r.n > len(b) {
+				r.err = errTruncatedWKB
+				break
+			}
+			_, size, err := s.readGeometry(b[r.n:], depth+1)
+			if err != nil {
+				return 0, 0, err
+			}
+			r.n += size
+		}
+	default:
+		return 0, 0, fmt.Errorf("invalid WKB: unknown geometry type %d", code)
+	}
+	if r.err != nil {
+		return 0, 0, r.err
+	}
+	return typ, r.n, nil
+}
+
+var errTruncatedWKB = errors.New("invalid WKB: truncated geometry")
+
+type wkbReader struct {
+	stats *geospatialStatistics
+	order binary.ByteOrder
+	hasZ  bool
+	hasM  bool
+	b     []byte
+	n     int
+	err   error
+}
+
+func (r *wkbReader) readCount() int {
+	if r.err != nil || len(r.b)-r.n < 4 {
+		r.err = errTruncatedWKB
+		return 0
+	}
+	count := int(r.order.Uint32(r.b[r.n:]))
+	r.n += 4
+	return count
+}
+
+func (r *wkbReader) readFloat() float64 {
+	if r.err != nil || len(r.b)-r.n < 8 {
+		r.err = errTruncatedWKB
+		return math.NaN()
+	}
+	f := math.Float64frombits(r.order.Uint64(r.b[r.n:]))
+	r.n += 8
+	return f
+}
+
+func (r *wkbReader) readPoint() (x, y float64) {
+	x, y = r.readFloat(), r.readFloat()
+	z, m := math.NaN(), math.NaN()
+	if r.hasZ {
+		z = r.readFloat()
+	}
+	if r.hasM {
+		m = r.readFloat()
+	}
+	if r.err == nil {
+		r.stats.observePoint(x, y, z, m)
+	}
+	return x, y
+}
+
+// readPoints reads a sequence of points, returning the sum of the longitudes
+// travelled along the edges between them and the mean of their latitudes, which
+// are only computed for GEOGRAPHY values.
+func (r *wkbReader) readPoints() (winding, latitude float64) {
+	count := r.readCount()
+	// Check the length upfront so a corrupted count cannot keep the loop
+	// spinning on a short input.
+	size := 16
+	if r.hasZ {
+		size += 8
+	}
+	if r.hasM {
+		size += 8
+	}
+	if uint64(count)*uint64(size) > uint64(len(r.b)-r.n) {
+		r.err = errTruncatedWKB
+		return 0, 0
+	}
+	prevX, prevY := math.NaN(), math.NaN()
+	for i := 0; i < count && r.err == nil; i++ {
+		x, y := r.readPoint()
+		if r.err == nil && r.stats.geography {
+			if i > 0 {
+				winding += r.stats.observeEdge(prevX, prevY, x, y)
+			}
+			latitude += y / float64(count)
+		}
+		prevX, prevY = x, y
+	}
+	return winding, latitude
+}
+
+func (r *wkbReader) readRings() {
+	for i, count := 0, r.readCount(); i < count && r.err == nil; i++ {
+		winding, latitude := r.readPoints()
+		// A ring going around the earth encloses one of the poles, the
+		// polygon then covers all longitudes up to the pole on the side of
+		// its vertices.
+		if r.stats.geography && math.Abs(winding) > 180 {
+			r.stats.lng = longitudeInterval{lo: -180, hi: 180, valid: true}
+			if latitude >= 0 {
+				r.stats.ymax = 90
+			} else {
+				r.stats.ymin = -90
+			}
+		}
+	}
+}
+
+// observePoint extends the bounding box to contain the point. NaN coordinates,
+// which represent empty points, are ignored.
+func (s *geospatialStatistics) observePoint(x, y, z, m float64) {
+	if !math.IsNaN(x) && !math.IsNaN(y) {
+		if s.geography {
+			s.lng = s.lng.union(longitudeInterval{lo: x, hi: x, valid: true})
+		} else {
+			s.xmin, s.xmax = min(s.xmin, x), max(s.xmax, x)
+		}
+		s.ymin, s.ymax = min(s.ymin, y), max(s.ymax, y)
+	}
+	if !math.IsNaN(z) {
+		s.zmin, s.zmax = min(s.zmin, z), max(s.zmax, z)
+	}
+	if !math.IsNaN(m) {
+		s.mmin, s.mmax = min(s.mmin, m), max(s.mmax, m)
+	}
+}
+
+// statistics returns the accumulated geospatial statistics in the format of
+// the column chunk metadata.
+func (s *geospatialStatistics) statistics() format.GeospatialStatistics {
+	if s.invalid {
+		return format.GeospatialStatistics{}
+	}
+	stats := format.GeospatialStatistics{
+		GeospatialTypes: slices.Clone(s.types),
+	}
+	xmin, xmax := s.xmin, s.xmax
+	if s.geography {
+		xmin, xmax = math.Inf(+1), math.Inf(-1)
+		if s.lng.valid {
+			xmin, xmax = s.lng.lo, s.lng.hi
+		}
+	}
+	if s.ymin <= s.ymax && !math.IsInf(xmin, 0) {
+		bbox := &format.BoundingBox{
+			Xmin: xmin,
+			Xmax: xmax,
+			Ymin: s.ymin,
+			Ymax: s.ymax,
+		}
+		if zmin, zmax := s.zmin, s.zmax; zmin <= zmax {
+			bbox.Zmin, bbox.Zmax = &zmin, &zmax
+		}
+		if mmin, mmax := s.mmin, s.mmax; mmin <= mmax {
+			bbox.Mmin, bbox.Mmax = &mmin, &mmax
+		}
+		stats.BBox = bbox
+	}
+	return stats
+}
+
+// observeEdge extends the bounding box of GEOGRAPHY values to contain the edge
+// between two vertices, which is the shortest arc of the great circle passing
+// through them. The arc covers the shortest range of longitudes between the
+// vertices, and may reach latitudes higher or lower than theirs.
+//
+// Edges are interpolated on a sphere regardless of the algorithm of the
+// column, which approximates the geodesics of the other algorithms.
+//
+// The method returns the difference of longitude between the vertices, which
+// is negative when the edge goes west.
+func (s *geospatialStatistics) observeEdge(x1, y1, x2, y2 float64) (d float64) {
+	if math.IsNaN(x1) || math.IsNaN(y1) || math.IsNaN(x2) || math.IsNaN(y2) {
+		return 0
+	}
+
+	switch d = math.Mod(x2-x1+540, 360) - 180; {
+	case d == -180 || d == 180:
+		// The arc between points with opposite longitudes goes over a pole,
+		// which is covered by the extent of latitudes computed below.
+	case d >= 0:
+		s.lng = s.lng.union(longitudeInterval{lo: x1, hi: x2, valid: true})
+	default:
+		s.lng = s.lng.union(longitudeInterval{lo: x2, hi: x1, valid: true})
+	}
+
+	p1, p2 := unitVectorOf(x1, y1), unitVectorOf(x2, y2)
+	n := p1.cross(p2)
+	// The points of the great circle with the highest and lowest latitudes
+	// are where its plane is tangent to a parallel, they are only part of the
+	// edge if they are between its vertices.
+	top := vector3{-n.x * n.z, -n.y * n.z, n.x*n.x + n.y*n.y}
+	if top == (vector3{}) {
+		return d
+	}
+	for _, v := range []vector3{top, top.neg()} {
+		if p1.cross(v).dot(n) > 0 && v.cross(p2).dot(n) > 0 {
+			lat := math.Atan2(v.z, math.Hypot(v.x, v.y)) * 180 / math.Pi
+			s.ymin, s.ymax = min(s.ymin, lat), max(s.ymax, lat)
+		}
+	}
+	return d
+}
+
+type vector3 struct{ x, y, z float64 }
+
+func unitVectorOf(lng, lat float64) vector3 {
+	lng, lat = lng*math.Pi/180, lat*math.Pi/180
+	return vector3{
+		x: math.Cos(lat) * math.Cos(lng),
+		y: math.Cos(lat) * math.Sin(lng),
+		z: math.Sin(lat),
+	}
+}
+
+func (a vector3) cross(b vector3) vector3 {
+	return vector3{a.y*b.z - a.z*b.y, a.z*b.x - a.x*b.z, a.x*b.y - a.y*b.x}
+}
+
+func (a vector3) dot(b vector3) float64 { return a.x*b.x + a.y*b.y + a.z*b.z }
+
+func (a vector3) neg() vector3 { return vector3{-a.x, -a.y, -a.z} }
+
+// longitudeInterval is a range of longitudes in degrees, which wraps around the
+// antimeridian when lo is greater than hi. The zero value is the empty range.
+type longitudeInterval struct {
+	lo, hi float64
+	valid  bool
+}
+
+func (i longitudeInterval) isFull() bool { return i.lo == -180 && i.hi == 180 }
+
+func (i longitudeInterval) contains(x float64) bool {
+	if i.lo <= i.hi {
+		return i.lo <= x && x <= i.hi
+	}
+	return x >= i.lo || x <= i.hi
+}
+
+// union returns the smallest range containing both i and j. When they are
+// disjoint, they are joined over the shortest of the two gaps between them.
+func (i longitudeInterval) union(j longitudeInterval) longitudeInterval {
+	switch {
+	case !j.valid:
+		return i
+	case !i.valid:
+		return j
+	}
+	full := longitudeInterval{lo: -180, hi: 180, valid: true}
+	if i.contains(j.lo) {
+		if i.contains(j.hi) {
+			// j is within i unless it goes around the part of the circle that
+			// i does not cover.
+			if i.isFull() || eastwardDistance(i.lo, j.lo) <= eastwardDistance(i.lo, j.hi) {
+				return i
+			}
+			return full
+		}
+		return longitudeInterval{lo: i.lo, hi: j.hi, valid: true}
+	}
+	if i.contains(j.hi) {
+		return longitudeInterval{lo: j.lo, hi: i.hi, valid: true}
+	}
+	if j.contains(i.lo) {
+		return j
+	}
+	if eastwardDistance(i.hi, j.lo) <= eastwardDistance(j.hi, i.lo) {
+		return longitudeInterval{lo: i.lo, hi: j.hi, valid: true}
+	}
+	return longitudeInterval{lo: j.lo, hi: i.hi, valid: true}
+}
+
+// eastwardDistance returns the number of degrees to travel east from one
+// longitude to another, in the range [0:360).
+func eastwardDistance(from, to float64) float64 {
+	d := math.Mod(to-from, 360)
+	if d < 0 {
+		d += 360
+	}
+	return d
+}
@@ -0,0 +1,298 @@
+package parquet_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/parquet-go/parquet-go"
+	"github.com/parquet-go/parquet-go/format"
+)
+
+func wkbPoint(x, y float64) []byte {
+	b := []byte{1}
+	b = binary.LittleEndian.AppendUint32(b, 1)
+	b = binary.LittleEndian.AppendUint64(b, math.Float64bits(x))
+	b = binary.LittleEndian.AppendUint64(b, math.Float64bits(y))
+	return b
+}
+
+func wkbPointZ(x, y, z float64) []byte {
+	b := []byte{0}
+	b = binary.BigEndian.AppendUint32(b, 1001)
+	for _, f := range []float64{x, y, z} {
+		b = binary.BigEndian.AppendUint64(b, math.Float64bits(f))
+	}
+	return b
+}
+
+func wkbLineString(coords ...[2]float64) []byte {
+	b := []byte{1}
+	b = binary.LittleEndian.AppendUint32(b, 2)
+	b = binary.LittleEndian.AppendUint32(b, uint32(len(coords)))
+	for _, c := range coords {
+		b = binary.LittleEndian.AppendUint64(b, math.Float64bits(c[0]))
+		b = binary.LittleEndian.AppendUint64(b, math.Float64bits(c[1]))
+	}
+	return b
+}
+
+func wkbCollection(geometries ...[]byte) []byte {
+	b := []byte{1}
+	b = binary.LittleEndian.AppendUint32(b, 7)
+	b = binary.LittleEndian.AppendUint32(b, uint32(len(geometries)))
+	for _, g := range geometries {
+		b = append(b, g...)
+	}
+	return b
+}
+
+type geometryRow struct {
+	ID    int64  `parquet:"id"`
+	Shape []byte `parquet:"shape,geometry,optional"`
+}
+
+func writeGeometryRows(t *testing.T, rowGroups ...[]geometryRow) *parquet.File {
+	t.Helper()
+	b := new(bytes.Buffer)
+	w := parquet.NewGenericWriter[geometryRow](b)
+	for _, rows := range rowGroups {
+		if _, err := w.Write(rows); err != nil {
+			t.Fatal(err)
+		}
+		if err := w.Flush(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	f, err := parquet.OpenFile(bytes.NewReader(b.Bytes()), int64(b.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return f
+}
+
+func TestGeometryStatistics(t *testing.T) {
+	f := writeGeometryRows(t, []geometryRow{
+		{ID: 0, Shape: wkbPoint(1, 2)},
+		{ID: 1, Shape: wkbLineString([2]float64{-3, 4}, [2]float64{5, -6})},
+		{ID: 2},
+		{ID: 3, Shape: wkbPoint(math.NaN(), math.NaN())},
+		{ID: 4, Shape: wkbCollection(wkbPointZ(0, 10, 7), wkbPoint(0, 0))},
+	})
+
+	if lt := f.Metadata().Schema[2].LogicalType; lt == nil || lt.Geometry == nil {
+		t.Fatalf("wrong logical type of the shape column: %v", lt)
+	}
+
+	column := f.Metadata().RowGroups[0].Columns[1]
+	stats := column.MetaData.GeospatialStatistics
+	if stats.BBox == nil {
+		t.Fatal("missing bounding box")
+	}
+	if want := (format.BoundingBox{Xmin: -3, Xmax: 5, Ymin: -6, Ymax: 10}); stats.BBox.Xmin != want.Xmin || stats.BBox.Xmax != want.Xmax || stats.BBox.Ymin != want.Ymin || stats.BBox.Ymax != want.Ymax {
+		t.Errorf("wrong bounding box: want=%+v got=%+v", want, *stats.BBox)
+	}
+	if stats.BBox.Zmin == nil || *stats.BBox.Zmin != 7 || *stats.BBox.Zmax != 7 {
+		t.Errorf("wrong Z bounds: %v, %v", stats.BBox.Zmin, stats.BBox.Zmax)
+	}
+	if stats.BBox.Mmin != nil || stats.BBox.Mmax != nil {
+		t.Errorf("unexpected M bounds: %v, %v", stats.BBox.Mmin, stats.BBox.Mmax)
+	}
+	if want := []int32{1, 2, 7}; !reflect.DeepEqual(stats.GeospatialTypes, want) {
+		t.Errorf("wrong geometry types: want=%v got=%v", want, stats.GeospatialTypes)
+	}
+
+	if column.MetaData.Statistics.MinValue != nil || column.MetaData.Statistics.MaxValue != nil {
+		t.Errorf("unexpected min/max statistics: %+v", column.MetaData.Statistics)
+	}
+	if column.MetaData.Statistics.NullCount != 1 {
+		t.Errorf("wrong null count: want=1 got=%d", column.MetaData.Statistics.NullCount)
+	}
+	if column.ColumnIndexOffset != 0 {
+		t.Error("unexpected column index for GEOMETRY column")
+	}
+
+	r := parquet.NewGenericReader[geometryRow](f)
+	rows := make([]geometryRow, 5)
+	if n, err := r.Read(rows); n != len(rows) {
+		t.Fatalf("wrong number of rows read: %d/%d: %v", n, len(rows), err)
+	}
+	if !bytes.Equal(rows[0].Shape, wkbPoint(1, 2)) {
+		t.Errorf("wrong shape read back: %x", rows[0].Shape)
+	}
+}
+
+func TestGeometryStatisticsInvalidWKB(t *testing.T) {
+	truncated := wkbLineString([2]float64{1, 2}, [2]float64{3, 4})
+	for _, shape := range [][]byte{
+		{},
+		{2, 0, 0, 0, 1},
+		{1, 42, 0, 0, 0},
+		truncated[:len(truncated)-1],
+	} {
+		f := writeGeometryRows(t, []geometryRow{
+			{ID: 0, Shape: wkbPoint(1, 2)},
+			{ID: 1, Shape: shape},
+		})
+		stats := f.Metadata().RowGroups[0].Columns[1].MetaData.GeospatialStatistics
+		if stats.BBox != nil || stats.GeospatialTypes != nil {
+			t.Errorf("%x: statistics must be omitted for invalid WKB values: %+v", shape, stats)
+		}
+	}
+}
+
+func wkbPolygon(rings ...[][2]float64) []byte {
+	b := []byte{1}
+	b = binary.LittleEndian.AppendUint32(b, 3)
+	b = binary.LittleEndian.AppendUint32(b, uint32(len(rings)))
+	for _, ring := range rings {
+		b = binary.LittleEndian.AppendUint32(b, uint32(len(ring)))
+		for _, c := range ring {
+			b = binary.LittleEndian.AppendUint64(b, math.Float64bits(c[0]))
+			b = binary.LittleEndian.AppendUint64(b, math.Float64bits(c[1]))
+		}
+	}
+	return b
+}
+
+func writeGeographyShapes(t *testing.T, shapes ...[]byte) *parquet.File {
+	t.Helper()
+	schema := parquet.NewSchema("places", parquet.Group{
+		"location": parquet.Geography("", format.Vincenty),
+	})
+
+	b := new(bytes.Buffer)
+	w := parquet.NewWriter(b, schema)
+	for _, shape := range shapes {
+		if err := w.Write(map[string]any{"location": shape}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := parquet.OpenFile(bytes.NewReader(b.Bytes()), int64(b.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return f
+}
+
+func TestGeographyStatistics(t *testing.T) {
+	f := writeGeographyShapes(t, wkbPoint(170, 10), wkbLineString([2]float64{170, 0}, [2]float64{-170, 0}))
+	if got := f.Schema().Columns(); len(got) != 1 {
+		t.Fatalf("wrong columns: %v", got)
+	}
+	if lt := f.Metadata().Schema[1].LogicalType; lt == nil || lt.Geography == nil || lt.Geography.Algorithm != format.Vincenty {
+		t.Fatalf("wrong logical type of the location column: %v", lt)
+	}
+
+	stats := f.Metadata().RowGroups[0].Columns[0].MetaData.GeospatialStatistics
+	if want := []int32{1, 2}; !reflect.DeepEqual(stats.GeospatialTypes, want) {
+		t.Errorf("wrong geometry types: want=%v got=%v", want, stats.GeospatialTypes)
+	}
+
+	for _, test := range []struct {
+		scenario string
+		shapes   [][]byte
+		want     parquet.BoundingBox
+	}{
+		{
+			scenario: "edge crossing the antimeridian",
+			shapes:   [][]byte{wkbPoint(170, 10), wkbLineString([2]float64{170, 0}, [2]float64{-170, 0})},
+			want:     parquet.BoundingBox{XMin: 170, YMin: 0, XMax: -170, YMax: 10},
+		},
+		{
+			scenario: "points on both sides of the antimeridian",
+			shapes:   [][]byte{wkbPoint(-175, 1), wkbPoint(175, 2), wkbPoint(-178, 3)},
+			want:     parquet.BoundingBox{XMin: 175, YMin: 1, XMax: -175, YMax: 3},
+		},
+		{
+			// The great circle between the vertices reaches a latitude of
+			// about 67.79 degrees.
+			scenario: "edge extending past the latitude of its vertices",
+			shapes:   [][]byte{wkbLineString([2]float64{0, 60}, [2]float64{90, 60})},
+			want:     parquet.BoundingBox{XMin: 0, YMin: 60, XMax: 90, YMax: 67.7923},
+		},
+		{
+			scenario: "polygon around the north pole",
+			shapes: [][]byte{wkbPolygon([][2]float64{
+				{0, 80}, {90, 80}, {180, 80}, {-90, 80}, {0, 80},
+			})},
+			want: parquet.BoundingBox{XMin: -180, YMin: 80, XMax: 180, YMax: 90},
+		},
+	} {
+		t.Run(test.scenario, func(t *testing.T) {
+			f := writeGeographyShapes(t, test.shapes...)
+			bbox, ok := parquet.GeospatialBoundingBox(f.RowGroups()[0].ColumnChunks()[0])
+			if !ok {
+				t.Fatal("missing bounding box")
+			}
+			const epsilon = 1e-4
+			if math.Abs(bbox.XMin-test.want.XMin) > epsilon ||
+				math.Abs(bbox.XMax-test.want.XMax) > epsilon ||
+				math.Abs(bbox.YMin-test.want.YMin) > epsilon ||
+				math.Abs(bbox.YMax-test.want.YMax) > epsilon {
+				t.Errorf("wrong bounding box: want=%+v got=%+v", test.want, bbox)
+			}
+		})
+	}
+}
+
+func TestIntersectsBoundingBox(t *testing.T) {
+	f := writeGeometryRows(t,
+		[]geometryRow{{ID: 0, Shape: wkbPoint(0, 0)}, {ID: 1, Shape: wkbPoint(10, 10)}},
+		[]geometryRow{{ID: 2, Shape: wkbPoint(100, 100)}, {ID: 3, Shape: wkbPoint(110, 120)}},
+		[]geometryRow{{ID: 4}},
+	)
+
+	for _, test := range []struct {
+		bbox parquet.BoundingBox
+		want []bool
+	}{
+		{parquet.BoundingBox{XMin: 5, YMin: 5, XMax: 6, YMax: 6}, []bool{true, false, true}},
+		{parquet.BoundingBox{XMin: 105, YMin: 90, XMax: 200, YMax: 100}, []bool{false, true, true}},
+		{parquet.BoundingBox{XMin: 11, YMin: 11, XMax: 99, YMax: 99}, []bool{false, false, true}},
+		{parquet.BoundingBox{XMin: -10, YMin: -10, XMax: 200, YMax: 200}, []bool{true, true, true}},
+	} {
+		intersects := parquet.IntersectsBoundingBox([]string{"shape"}, test.bbox)
+		got := make([]bool, 0, len(test.want))
+		for _, rowGroup := range f.RowGroups() {
+			got = append(got, intersects(rowGroup))
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%+v: want=%v got=%v", test.bbox, test.want, got)
+		}
+	}
+
+	if !parquet.IntersectsBoundingBox([]string{"missing"}, parquet.BoundingBox{})(f.RowGroups()[0]) {
+		t.Error("row groups must be retained when the column does not exist")
+	}
+}
+
+func TestBoundingBoxIntersectsAntimeridian(t *testing.T) {
+	wrapping := parquet.BoundingBox{XMin: 170, YMin: -10, XMax: -170, YMax: 10}
+	for _, test := range []struct {
+		box  parquet.BoundingBox
+		want bool
+	}{
+		{parquet.BoundingBox{XMin: 175, YMin: 0, XMax: 180, YMax: 5}, true},
+		{parquet.BoundingBox{XMin: -180, YMin: 0, XMax: -175, YMax: 5}, true},
+		{parquet.BoundingBox{XMin: -10, YMin: 0, XMax: 10, YMax: 5}, false},
+		{parquet.BoundingBox{XMin: 175, YMin: 20, XMax: 180, YMax: 25}, false},
+		{parquet.BoundingBox{XMin: 160, YMin: 0, XMax: -160, YMax: 5}, true},
+	} {
+		if got := wrapping.Intersects(test.box); got != test.want {
+			t.Errorf("%+v: want=%t got=%t", test.box, test.want, got)
+		}
+		if got := test.box.Intersects(wrapping); got != test.want {
+			t.Errorf("%+v (reversed): want=%t got=%t", test.box, test.want, got)
+		}
+	}
+}
@@ -0,0 +1,539 @@
+// Package httprange implements io.ReaderAt for objects served over HTTP(S),
+// using range requests to read only the parts of the objects that are needed.
+//
+// The package is intended to open parquet files stored in object stores, for
+// example behind presigned URLs, without downloading them entirely:
+//
+//	r, err := httprange.Open(ctx, url)
+//	if err != nil {
+//		...
+//	}
+//	f, err := parquet.OpenFile(r, r.Size())
+//
+// Only GET requests are issued, since presigned URLs are usually only valid
+// for a single method; the size and ETag of the object are obtained from the
+// response to the first range request.
+package httprange
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultMaxRetries  = 3
+	DefaultRetryDelay  = 100 * time.Millisecond
+	DefaultBlockSize   = 64 * 1024
+	DefaultCacheBlocks = 16
+)
+
+var (
+	// ErrModified is returned when the object was modified since the reader
+	// was opened, which is detected by a change of its ETag or size.
+	ErrModified = errors.New("httprange: object was modified")
+
+	// ErrRangeNotSupported is returned when the server responds to range
+	// requests with the full content of the object.
+	ErrRangeNotSupported = errors.New("httprange: server does not support range requests")
+)
+
+// The Config type carries configuration options for readers.
+//
+// Config implements the Option interface so it can be used directly as
+// argument to the Open function when needed, for example:
+//
+//	r, err := httprange.Open(ctx, url, &httprange.Config{
+//		Client: client,
+//	})
+type Config struct {
+	// The client used to issue requests.
+	Client *http.Client
+	// Headers added to all the requests, for example to authenticate them.
+	Header http.Header
+	// Number of times that requests are retried after transient failures.
+	MaxRetries int
+	// Delay before retrying requests, doubled after each attempt.
+	RetryDelay time.Duration
+	// Reads smaller than the block size are served from blocks of this size,
+	// which are kept in a cache of CacheBlocks blocks.
+	BlockSize   int
+	CacheBlocks int
+}
+
+// DefaultConfig returns a new Config value initialized with the default
+// reader configuration.
+func DefaultConfig() *Config {
+	return &Config{
+		Client:      http.DefaultClient,
+		MaxRetries:  DefaultMaxRetries,
+		RetryDelay:  DefaultRetryDelay,
+		BlockSize:   DefaultBlockSize,
+		CacheBlocks: DefaultCacheBlocks,
+	}
+}
+
+// NewConfig constructs a new reader configuration applying the options passed
+// as arguments.
+//
+// The function returns an non-nil error if some of the options carried invalid
+// configuration values.
+func NewConfig(options ...Option) (*Config, error) {
+	config := DefaultConfig()
+	config.Apply(options...)
+	return config, config.Validate()
+}
+
+// Apply applies the given list of options to c.
+func (c *Config) Apply(options ...Option) {
+	for _, opt := range options {
+		opt.Configure(c)
+	}
+}
+
+// Configure applies configuration options from c to config.
+func (c *Config) Configure(config *Config) {
+	*config = Config{
+		Client:      coalesceClient(c.Client, config.Client),
+		Header:      coalesceHeader(c.Header, config.Header),
+		MaxRetries:  coalesceInt(c.MaxRetries, config.MaxRetries),
+		RetryDelay:  time.Duration(coalesceInt(int(c.RetryDelay), int(config.RetryDelay))),
+		BlockSize:   coalesceInt(c.BlockSize, config.BlockSize),
+		CacheBlocks: coalesceInt(c.CacheBlocks, config.CacheBlocks),
+	}
+}
+
+// Validate returns a non-nil error if the configuration of c is invalid.
+func (c *Config) Validate() error {
+	switch {
+	case c.Client == nil:
+		return errors.New("httprange: invalid configuration: Client must not be nil")
+	case c.MaxRetries < 0:
+		return fmt.Errorf("httprange: invalid configuration: MaxRetries must not be negative: %d", c.MaxRetries)
+	case c.RetryDelay < 0:
+		return fmt.Errorf("httprange: invalid configuration: RetryDelay must not be negative: %s", c.RetryDelay)
+	case c.BlockSize <= 0:
+		return fmt.Errorf("httprange: invalid configuration: BlockSize must be positive: %d", c.BlockSize)
+	case c.CacheBlocks < 0:
+		return fmt.Errorf("httprange: invalid configuration: CacheBlocks must not be negative: %d", c.CacheBlocks)
+	}
+	return nil
+}
+
+// Option is an interface implemented by types that carry configuration
+// options for readers.
+type Option interface {
+	Configure(*Config)
+}
+
+type option func(*Config)
+
+func (opt option) Configure(config *Config) { opt(config) }
+
+// Client sets the HTTP client used to issue requests.
+//
+// Defaults to http.DefaultClient.
+func Client(client *http.Client) Option {
+	return option(func(config *Config) { config.Client = client })
+}
+
+// Header adds a header to all the requests issued by readers.
+func Header(key, value string) Option {
+	return option(func(config *Config) {
+		config.Header = config.Header.Clone()
+		if config.Header == nil {
+			config.Header = make(http.Header)
+		}
+		config.Header.Add(key, value)
+	})
+}
+
+// MaxRetries sets the number of times that requests are retried after
+// transient failures, such as network errors, truncated responses, or 5xx
+// status codes. Setting it to zero disables retries.
+//
+// Defaults to 3.
+func MaxRetries(retries int) Option {
+	return option(func(config *Config) { config.MaxRetries = retries })
+}
+
+// RetryDelay sets the delay before retrying failed requests, which is doubled
+// after each attempt. Servers may ask for a longer delay with a Retry-After
+// header.
+//
+// Defaults to 100ms.
+func RetryDelay(delay time.Duration) Option {
+	return option(func(config *Config) { config.RetryDelay = delay })
+}
+
+// BlockSize sets the size of blocks used to serve small reads. Reads smaller
+// than the block size fetch whole blocks, which are retained in the cache so
+// that the next small reads of the same block do not issue requests; larger
+// reads are issued as a single range request and bypass the cache.
+//
+// Defaults to 64 KiB.
+func BlockSize(size int) Option {
+	return option(func(config *Config) { config.BlockSize = size })
+}
+
+// CacheBlocks sets the number of blocks retained in the cache of readers,
+// evicting the least recently used blocks. Setting it to zero disables the
+// cache, in which case each read issues a request.
+//
+// Defaults to 16.
+func CacheBlocks(blocks int) Option {
+	return option(func(config *Config) { config.CacheBlocks = blocks })
+}
+
+// Reader is an io.ReaderAt reading an object served over HTTP(S) with range
+// requests.
+//
+// Readers are safe to use concurrently from multiple goroutines.
+type Reader struct {
+	ctx    context.Context
+	url    string
+	name   string // URL without the query, which may carry credentials
+	config Config
+	size   int64
+	etag   string
+
+	mutex  sync.Mutex
+	blocks map[int64]*block
+	lru    list.List // *block, most recently used first
+}
+
+type block struct {
+	index int64
+	elem  *list.Element
+	ready chan struct{}
+	data  []byte
+	err   error
+}
+
+// Open returns a Reader for the object at the given URL. The first block of
+// the object is read to determine its size and ETag.
+//
+// The context bounds the lifetime of the reader: requests issued by ReadAt
+// are canceled when it is canceled.
+func Open(ctx context.Context, rawURL string, options ...Option) (*Reader, error) {
+	config, err := NewConfig(options...)
+	if err != nil {
+		return nil, err
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		// The error embeds the URL, which may carry credentials.
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return nil, fmt.Errorf("httprange: invalid URL: %w", err)
+	}
+
+	r := &Reader{
+		ctx:    ctx,
+		url:    rawURL,
+		name:   (&url.URL{Scheme: u.Scheme, Host: u.Host, Path: u.Path}).String(),
+		config: *config,
+		size:   -1,
+		blocks: make(map[int64]*block),
+	}
+
+	data := make([]byte, config.BlockSize)
+	n, err := r.readRange(data, 0)
+	if err != nil {
+		return nil, err
+	}
+	if config.CacheBlocks > 0 && n > 0 {
+		b := &block{data: data[:n], ready: make(chan struct{})}
+		close(b.ready)
+		b.elem = r.lru.PushFront(b)
+		r.blocks[0] = b
+	}
+	return r, nil
+}
+
+// Size returns the size of the object in bytes.
+func (r *Reader) Size() int64 { return r.size }
+
+// ETag returns the ETag of the object, or an empty string if the server did
+// not return one.
+func (r *Reader) ETag() string { return r.etag }
+
+// ReadAt reads len(b) bytes of the object starting at offset off.
+//
+// The method satisfies the io.ReaderAt interface. It returns ErrModified if
+// the object was modified since the reader was opened.
+func (r *Reader) ReadAt(b []byte, off int64) (int, error) {
+	if off < 0 {
+		return 0, fmt.Errorf("httprange: negative offset: %d", off)
+	}
+	if len(b) == 0 {
+		return 0, nil
+	}
+	if off >= r.size {
+		return 0, io.EOF
+	}
+
+	var eof error
+	if limit := r.size - off; limit < int64(len(b)) {
+		b, eof = b[:limit], io.EOF
+	}
+
+	if r.config.CacheBlocks == 0 || len(b) >= r.config.BlockSize {
+		n, err := r.readRange(b, off)
+		if err == nil {
+			err = eof
+		}
+		return n, err
+	}
+
+	blockSize := int64(r.config.BlockSize)
+	n := 0
+	for n < len(b) {
+		blk, err := r.block(off / blockSize)
+		if err != nil {
+			return n, err
+		}
+		c := copy(b[n:], blk.data[off-blk.index*blockSize:])
+		n += c
+		off += int64(c)
+	}
+	return n, eof
+}
+
+// block returns the block at the given index, reading it if it was not in the
+// cache. Concurrent reads of the same block wait for a single request.
+func (r *Reader) block(index int64) (*block, error) {
+	r.mutex.Lock()
+	if b := r.blocks[index]; b != nil {
+		r.lru.MoveToFront(b.elem)
+		r.mutex.Unlock()
+		<-b.ready
+		return b, b.err
+	}
+	b := &block{index: index, ready: make(chan struct{})}
+	b.elem = r.lru.PushFront(b)
+	r.blocks[index] = b
+	for r.lru.Len() > r.config.CacheBlocks {
+		evicted := r.lru.Remove(r.lru.Back()).(*block)
+		delete(r.blocks, evicted.index)
+	}
+	r.mutex.Unlock()
+
+	offset := index * int64(r.config.BlockSize)
+	b.data = make([]byte, min(int64(r.config.BlockSize), r.size-offset))
+	_, b.err = r.readRange(b.data, offset)
+	if b.err != nil {
+		// Failed reads are not cached so they can be retried.
+		r.mutex.Lock()
+		if r.blocks[index] == b {
+			r.lru.Remove(b.elem)
+			delete(r.blocks, index)
+		}
+		r.mutex.Unlock()
+	}
+	close(b.ready)
+	return b, b.err
+}
+
+// readRange reads the bytes of the object starting at off into b, retrying
+// the request after transient failures. Before the size of the object is
+// known, fewer bytes than len(b) are read when the end of the object is
+// reached.
+func (r *Reader) readRange(b []byte, off int64) (int, error) {
+	delay := r.config.RetryDelay
+	for attempt := 0; ; attempt++ {
+		n, err := r.tryReadRange(b, off)
+		var retry *retryableError
+		if !errors.As(err, &retry) {
+			return n, err
+		}
+		if attempt == r.config.MaxRetries {
+			return n, retry.err
+		}
+
+		timer := time.NewTimer(max(delay, retry.after))
+		select {
+		case <-timer.C:
+		case <-r.ctx.Done():
+			timer.Stop()
+			return 0, fmt.Errorf("httprange: GET %s: %w", r.name, r.ctx.Err())
+		}
+		delay *= 2
+	}
+}
+
+func (r *Reader) tryReadRange(b []byte, off int64) (int, error) {
+	req, err := http.NewRequestWithContext(r.ctx, http.MethodGet, r.url, nil)
+	if err != nil {
+		return 0, r.error(err)
+	}
+	for key, values := range r.config.Header {
+		req.Header[key] = values
+	}
+	req.Header.Set("Range", fmt.Sprintf("bytes=%d-%d", off, off+int64(len(b))-1))
+	// Weak ETags cannot be used in conditional range requests, the ETag of
+	// responses is compared to the one of the object below.
+	if r.etag != "" && !strings.HasPrefix(r.etag, "W/") {
+		req.Header.Set("If-Match", r.etag)
+	}
+
+	res, err := r.config.Client.Do(req)
+	if err != nil {
+		if r.ctx.Err() != nil {
+			return 0, r.error(err)
+		}
+		return 0, &retryableError{err: r.error(err)}
+	}
+	defer func() {
+		// Draining the body allows the connection to be reused.
+		io.CopyN(io.Discard, res.Body, 4096)
+		res.Body.Close()
+	}()
+
+	switch res.StatusCode {
+	case http.StatusPartialContent:
+	case http.StatusOK:
+		// Empty objects have no satisfiable ranges, some servers respond with
+		// their empty content instead of an error.
+		if r.size < 0 && res.ContentLength == 0 {
+			r.size, r.etag = 0, res.Header.Get("ETag")
+			return 0, nil
+		}
+		return 0, ErrRangeNotSupported
+	case http.StatusPreconditionFailed:
+		return 0, ErrModified
+	case http.StatusRequestedRangeNotSatisfiable:
+		if r.size < 0 && res.Header.Get("Content-Range") == "bytes */0" {
+			r.size, r.etag = 0, res.Header.Get("ETag")
+			return 0, nil
+		}
+		return 0, ErrModified
+	case http.StatusRequestTimeout,
+		http.StatusTooManyRequests,
+		http.StatusInternalServerError,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout:
+		return 0, &retryableError{
+			err:   r.error(errors.New(res.Status)),
+			after: parseRetryAfter(res.Header.Get("Retry-After")),
+		}
+	default:
+		return 0, r.error(errors.New(res.Status))
+	}
+
+	start, end, size, err := parseContentRange(res.Header.Get("Content-Range"))
+	if err != nil {
+		return 0, r.error(err)
+	}
+	etag := res.Header.Get("ETag")
+	if r.size < 0 {
+		r.size, r.etag = size, etag
+	} else if size != r.size || (r.etag != "" && etag != "" && etag != r.etag) {
+		return 0, ErrModified
+	}
+
+	n := min(int64(len(b)), r.size-off)
+	if start != off || end != off+n-1 {
+		return 0, r.error(fmt.Errorf("unexpected content range: %q", res.Header.Get("Content-Range")))
+	}
+	if _, err := io.ReadFull(res.Body, b[:n]); err != nil {
+		return 0, &retryableError{err: r.error(err)}
+	}
+	return int(n), nil
+}
+
+func (r *Reader) error(err error) error {
+	// The errors of the HTTP client embed the URL, which may carry
+	// credentials.
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		err = urlErr.Err
+	}
+	return fmt.Errorf("httprange: GET %s: %w", r.name, err)
+}
+
+type retryableError struct {
+	err   error
+	after time.Duration
+}
+
+func (e *retryableError) Error() string { return e.err.Error() }
+
+func (e *retryableError) Unwrap() error { return e.err }
+
+// parseContentRange parses the value of a Content-Range header of the form
+// "bytes start-end/size".
+func parseContentRange(s string) (start, end, size int64, err error) {
+	value, ok := strings.CutPrefix(s, "bytes ")
+	if !ok {
+		return 0, 0, 0, fmt.Errorf("invalid content range: %q", s)
+	}
+	bounds, total, ok := strings.Cut(value, "/")
+	if !ok {
+		return 0, 0, 0, fmt.Errorf("invalid content range: %q", s)
+	}
+	first, last, ok := strings.Cut(bounds, "-")
+	if !ok {
+		return 0, 0, 0, fmt.Errorf("invalid content range: %q", s)
+	}
+	if start, err = strconv.ParseInt(first, 10, 64); err != nil {
+		return 0, 0, 0, fmt.Errorf("invalid content range: %q", s)
+	}
+	if end, err = strconv.ParseInt(last, 10, 64); err != nil {
+		return 0, 0, 0, fmt.Errorf("invalid content range: %q", s)
+	}
+	if size, err = strconv.ParseInt(total, 10, 64); err != nil {
+		return 0, 0, 0, fmt.Errorf("the size of the object is unknown: %q", s)
+	}
+	if start < 0 || end < start || size <= end {
+		return 0, 0, 0, fmt.Errorf("invalid content range: %q", s)
+	}
+	return start, end, size, nil
+}
+
+// parseRetryAfter parses the value of a Retry-After header, which is either a
+// number of seconds or an HTTP date.
+func parseRetryAfter(s string) time.Duration {
+	if s == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(s); err == nil {
+		return time.Duration(seconds) * time.Second
+	}
+	if t, err := http.ParseTime(s); err == nil {
+		return time.Until(t)
+	}
+	return 0
+}
+
+func coalesceClient(c1, c2 *http.Client) *http.Client {
+	if c1 != nil {
+		return c1
+	}
+	return c2
+}
+
+func coalesceHeader(h1, h2 http.Header) http.Header {
+	if h1 != nil {
+		return h1
+	}
+	return h2
+}
+
+func coalesceInt(i1, i2 int) int {
+	if i1 != 0 {
+		return i1
+	}
+	return i2
+}
@@ -0,0 +1,312 @@
+package httprange_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+	"github.com/parquet-go/parquet-go/httprange"
+)
+
+// object is the content served by the test server, which can be replaced to
+// simulate modifications of the object.
+type object struct {
+	mutex    sync.Mutex
+	data     []byte
+	etag     string
+	requests atomic.Int64
+	failures atomic.Int64 // number of next requests to fail with 503
+}
+
+func (o *object) set(data []byte, etag string) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	o.data, o.etag = data, etag
+}
+
+func (o *object) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	o.requests.Add(1)
+	if o.failures.Add(-1) >= 0 {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+		return
+	}
+	o.mutex.Lock()
+	data, etag := o.data, o.etag
+	o.mutex.Unlock()
+	if etag != "" {
+		w.Header().Set("ETag", etag)
+	}
+	http.ServeContent(w, r, "object", time.Time{}, bytes.NewReader(data))
+}
+
+func serve(t *testing.T, data []byte) (*object, *httptest.Server) {
+	t.Helper()
+	o := &object{data: data, etag: `"v1"`}
+	server := httptest.NewServer(o)
+	t.Cleanup(server.Close)
+	return o, server
+}
+
+func testData(size int) []byte {
+	data := make([]byte, size)
+	for i := range data {
+		data[i] = byte(i * 7)
+	}
+	return data
+}
+
+type row struct {
+	ID   int64  `parquet:"id"`
+	Name string `parquet:"name"`
+}
+
+func TestOpenParquetFile(t *testing.T) {
+	rows := make([]row, 10_000)
+	for i := range rows {
+		rows[i] = row{ID: int64(i), Name: fmt.Sprintf("name-%d", i)}
+	}
+	b := new(bytes.Buffer)
+	if err := parquet.Write(b, rows, parquet.MaxRowsPerRowGroup(3000)); err != nil {
+		t.Fatal(err)
+	}
+	_, server := serve(t, b.Bytes())
+
+	r, err := httprange.Open(context.Background(), server.URL+"/file.parquet?signature=secret", httprange.BlockSize(4096))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Size() != int64(b.Len()) {
+		t.Fatalf("wrong size: want=%d got=%d", b.Len(), r.Size())
+	}
+	if r.ETag() != `"v1"` {
+		t.Errorf("wrong etag: %q", r.ETag())
+	}
+
+	f, err := parquet.OpenFile(r, r.Size())
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := make([]row, len(rows)+1)
+	n, err := parquet.NewGenericReader[row](f).Read(got)
+	if err != nil && err != io.EOF {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got[:n], rows) {
+		t.Error("wrong rows read from the parquet file")
+	}
+}
+
+func TestReadAt(t *testing.T) {
+	data := testData(10_000)
+	o, server := serve(t, data)
+
+	r, err := httprange.Open(context.Background(), server.URL, httprange.BlockSize(1024), httprange.CacheBlocks(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []struct {
+		off, size int
+	}{
+		{0, 10},
+		{1000, 100}, // spans two blocks
+		{5000, 1024},
+		{9990, 10},
+		{0, 10_000},
+	} {
+		b := make([]byte, test.size)
+		n, err := r.ReadAt(b, int64(test.off))
+		if err != nil {
+			t.Fatalf("reading %d bytes at offset %d: %v", test.size, test.off, err)
+		}
+		if !bytes.Equal(b[:n], data[test.off:test.off+test.size]) {
+			t.Errorf("wrong bytes read at offset %d", test.off)
+		}
+	}
+
+	b := make([]byte, 100)
+	if n, err := r.ReadAt(b, 9950); n != 50 || err != io.EOF {
+		t.Errorf("reading past the end: n=%d err=%v", n, err)
+	}
+	if n, err := r.ReadAt(b, 10_000); n != 0 || err != io.EOF {
+		t.Errorf("reading at the end: n=%d err=%v", n, err)
+	}
+
+	// Small reads of the same block are served from the cache.
+	requests := o.requests.Load()
+	for i := range 10 {
+		if _, err := r.ReadAt(b[:10], int64(7168+i*100)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n := o.requests.Load() - requests; n != 1 {
+		t.Errorf("wrong number of requests to read a single block: %d", n)
+	}
+}
+
+func TestReadAtConcurrent(t *testing.T) {
+	data := testData(100_000)
+	_, server := serve(t, data)
+
+	r, err := httprange.Open(context.Background(), server.URL, httprange.BlockSize(1000), httprange.CacheBlocks(4))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wg := sync.WaitGroup{}
+	errs := make(chan error, 8)
+	for i := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			b := make([]byte, 100)
+			for j := range 200 {
+				off := int64((i*7919 + j*104729) % (len(data) - len(b)))
+				if _, err := r.ReadAt(b, off); err != nil {
+					errs <- err
+					return
+				}
+				if !bytes.Equal(b, data[off:off+int64(len(b))]) {
+					errs <- fmt.Errorf("wrong bytes read at offset %d", off)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+}
+
+func TestRetries(t *testing.T) {
+	data := testData(1000)
+	o, server := serve(t, data)
+
+	o.failures.Store(2)
+	r, err := httprange.Open(context.Background(), server.URL, httprange.RetryDelay(time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := o.requests.Load(); n != 3 {
+		t.Errorf("wrong number of requests: %d", n)
+	}
+
+	o.failures.Store(10)
+	_, err = httprange.Open(context.Background(), server.URL,
+		httprange.RetryDelay(time.Millisecond),
+		httprange.MaxRetries(1),
+	)
+	if err == nil || !strings.Contains(err.Error(), "503") {
+		t.Errorf("expected the error of the last attempt: %v", err)
+	}
+
+	o.failures.Store(1)
+	b := make([]byte, 10)
+	if _, err := r.ReadAt(b, 100); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestModified(t *testing.T) {
+	data := testData(10_000)
+	o, server := serve(t, data)
+
+	r, err := httprange.Open(context.Background(), server.URL, httprange.BlockSize(1024))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	o.set(testData(10_000), `"v2"`)
+	b := make([]byte, 100)
+	if _, err := r.ReadAt(b, 5000); !errors.Is(err, httprange.ErrModified) {
+		t.Errorf("expected ErrModified after the ETag changed: %v", err)
+	}
+
+	// The size of objects is also checked when the server does not return
+	// ETags.
+	o.set(testData(5000), "")
+	if _, err := r.ReadAt(b, 2000); !errors.Is(err, httprange.ErrModified) {
+		t.Errorf("expected ErrModified after the size changed: %v", err)
+	}
+}
+
+func TestRangeNotSupported(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(testData(1000))
+	}))
+	defer server.Close()
+
+	if _, err := httprange.Open(context.Background(), server.URL); !errors.Is(err, httprange.ErrRangeNotSupported) {
+		t.Errorf("expected ErrRangeNotSupported: %v", err)
+	}
+}
+
+func TestEmptyObject(t *testing.T) {
+	_, server := serve(t, nil)
+
+	r, err := httprange.Open(context.Background(), server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Size() != 0 {
+		t.Errorf("wrong size: %d", r.Size())
+	}
+	if n, err := r.ReadAt(make([]byte, 1), 0); n != 0 || err != io.EOF {
+		t.Errorf("reading an empty object: n=%d err=%v", n, err)
+	}
+}
+
+func TestClientAndHeader(t *testing.T) {
+	data := testData(1000)
+	o, server := serve(t, data)
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			http.Error(w, "forbidden", http.StatusForbidden)
+			return
+		}
+		o.ServeHTTP(w, r)
+	})
+	server.Config.Handler = handler
+
+	if _, err := httprange.Open(context.Background(), server.URL+"?signature=secret"); err == nil {
+		t.Fatal("expected an error without the authorization header")
+	} else if strings.Contains(err.Error(), "secret") {
+		t.Errorf("the error exposes the query of the URL: %v", err)
+	}
+
+	client := &http.Client{Transport: http.DefaultTransport}
+	r, err := httprange.Open(context.Background(), server.URL,
+		httprange.Client(client),
+		httprange.Header("Authorization", "Bearer token"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b := make([]byte, 10)
+	if _, err := r.ReadAt(b, 500); err != nil || !bytes.Equal(b, data[500:510]) {
+		t.Errorf("wrong read: err=%v", err)
+	}
+}
+
+func TestInvalidConfig(t *testing.T) {
+	if _, err := httprange.NewConfig(httprange.BlockSize(-1)); err == nil {
+		t.Error("expected an error for a negative block size")
+	}
+	if _, err := httprange.Open(context.Background(), "http://localhost", httprange.Client(nil), httprange.MaxRetries(-1)); err == nil {
+		t.Error("expected an error for a negative number of retries")
+	}
+}
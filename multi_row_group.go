@@ -288,3 +288,130 @@ func (m *multiPages) Close() (err error) {
 	m.column = nil
 	return err
 }
+
+// concatRowGroup is a RowGroup exposing a sequence of row groups which have
+// the same schema. Rows are read from each row group in order.
+//
+// Unlike multiRowGroup, rows are read by calling the Rows method of each row
+// group, which preserves the behavior of row groups which do not read rows
+// from their column chunks (e.g. converted or filtered row groups).
+type concatRowGroup struct {
+	schema    *Schema
+	rowGroups []RowGroup
+	columns   []ColumnChunk
+}
+
+func newConcatRowGroup(schema *Schema, rowGroups []RowGroup) *concatRowGroup {
+	r := &concatRowGroup{schema: schema, rowGroups: rowGroups}
+	r.init()
+	return r
+}
+
+func (r *concatRowGroup) init() {
+	r.columns = newMultiRowGroup(ReadModeSync, r.rowGroups...).ColumnChunks()
+}
+
+// NumRows returns the total number of rows in the row groups.
+func (r *concatRowGroup) NumRows() int64 {
+	numRows := int64(0)
+	for _, rowGroup := range r.rowGroups {
+		numRows += rowGroup.NumRows()
+	}
+	return numRows
+}
+
+// ColumnChunks returns the column chunks spanning all the row groups.
+func (r *concatRowGroup) ColumnChunks() []ColumnChunk { return r.columns }
+
+// Schema returns the schema of rows in the row groups.
+func (r *concatRowGroup) Schema() *Schema { return r.schema }
+
+// SortingColumns returns nil since rows of different row groups are not
+// sorted relative to each other.
+func (r *concatRowGroup) SortingColumns() []SortingColumn { return nil }
+
+// Rows returns a reader of the rows of all the row groups.
+func (r *concatRowGroup) Rows() Rows { return &concatRows{rowGroup: r} }
+
+type concatRows struct {
+	rowGroup *concatRowGroup
+	rows     Rows
+	index    int
+	rowIndex int64
+}
+
+func (r *concatRows) ReadRows(rows []Row) (int, error) {
+	if len(rows) == 0 {
+		return 0, nil
+	}
+	for {
+		if r.rows == nil {
+			if r.index == len(r.rowGroup.rowGroups) {
+				return 0, io.EOF
+			}
+			r.rows = r.rowGroup.rowGroups[r.index].Rows()
+			if r.rowIndex > 0 {
+				if err := r.rows.SeekToRow(r.rowIndex); err != nil {
+					return 0, err
+				}
+			}
+		}
+
+		n, err := r.rows.ReadRows(rows)
+		r.rowIndex += int64(n)
+		if err == io.EOF {
+			r.closeRowGroup()
+			r.index++
+			r.rowIndex = 0
+			err = nil
+		}
+		if n > 0 || err != nil {
+			return n, err
+		}
+	}
+}
+
+func (r *concatRows) SeekToRow(rowIndex int64) error {
+	r.closeRowGroup()
+	for r.index = 0; r.index < len(r.rowGroup.rowGroups); r.index++ {
+		rowGroup := r.rowGroup.rowGroups[r.index]
+		if f, ok := rowGroup.(*filteredRowGroup); ok && f.match != nil {
+			// The number of rows matching the predicate of the row group is
+			// unknown, the rows must be read to find the position.
+			r.rows = rowGroup.Rows()
+			skipped, err := skipRows(r.rows, rowIndex)
+			if err != nil {
+				return err
+			}
+			if skipped == rowIndex {
+				r.rowIndex = rowIndex
+				return nil
+			}
+			r.closeRowGroup()
+			rowIndex -= skipped
+			continue
+		}
+		numRows := rowGroup.NumRows()
+		if rowIndex < numRows {
+			break
+		}
+		rowIndex -= numRows
+	}
+	r.rowIndex = rowIndex
+	return nil
+}
+
+func (r *concatRows) Schema() *Schema { return r.rowGroup.schema }
+
+func (r *concatRows) Close() error {
+	r.index = len(r.rowGroup.rowGroups)
+	return r.closeRowGroup()
+}
+
+func (r *concatRows) closeRowGroup() (err error) {
+	if r.rows != nil {
+		err = r.rows.Close()
+		r.rows = nil
+	}
+	return err
+}
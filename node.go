@@ -2,6 +2,7 @@ package parquet
 
 import (
 	"reflect"
+	"slices"
 	"sort"
 	"unicode"
 	"unicode/utf8"
@@ -404,6 +405,11 @@ func isMap(node Node) bool {
 	return logicalType != nil && logicalType.Map != nil
 }
 
+func isVariant(node Node) bool {
+	logicalType := node.Type().LogicalType()
+	return logicalType != nil && logicalType.Variant != nil
+}
+
 func numLeafColumnsOf(node Node) int16 {
 	return makeColumnIndex(numLeafColumns(node, 0))
 }
@@ -477,6 +483,75 @@ func forEachNodeOf(name string, node Node, do func(string, Node)) {
 	}
 }
 
+// withoutColumns returns a copy of the group node without the leaf columns at
+// the given paths, retaining the order of the remaining fields. The function
+// returns nil if no columns remain.
+func withoutColumns(node Node, path columnPath, paths []columnPath) Node {
+	fields := node.Fields()
+	retained := make([]Field, 0, len(fields))
+	for _, field := range fields {
+		fieldPath := path.append(field.Name())
+		switch {
+		case !slices.ContainsFunc(paths, fieldPath.isPrefixOf):
+			retained = append(retained, field)
+		case field.Leaf():
+		default:
+			if fieldNode := withoutColumns(field, fieldPath, paths); fieldNode != nil {
+				retained = append(retained, &projectedField{Node: fieldNode, field: field})
+			}
+		}
+	}
+	if len(retained) == 0 {
+		return nil
+	}
+	return &projectedGroup{Node: node, fields: retained}
+}
+
+type projectedGroup struct {
+	Node
+	fields []Field
+}
+
+func (g *projectedGroup) Fields() []Field { return g.fields }
+
+func (g *projectedGroup) String() string { return sprint("", g) }
+
+type projectedField struct {
+	Node
+	field Field
+}
+
+func (f *projectedField) Name() string { return f.field.Name() }
+
+func (f *projectedField) Value(base reflect.Value) reflect.Value { return f.field.Value(base) }
+
+// projectColumns returns a copy of the group node retaining only the leaf
+// columns which have one of the given paths as prefix, in the order of the
+// original fields. The function returns nil if no columns are retained.
+func projectColumns(node Node, path columnPath, paths []columnPath) Node {
+	fields := node.Fields()
+	retained := make([]Field, 0, len(fields))
+	for _, field := range fields {
+		fieldPath := path.append(field.Name())
+		switch {
+		case slices.ContainsFunc(paths, func(p columnPath) bool { return p.isPrefixOf(fieldPath) }):
+			retained = append(retained, field)
+		case field.Leaf():
+		case slices.ContainsFunc(paths, fieldPath.isPrefixOf):
+			if fieldNode := projectColumns(field, fieldPath, paths); fieldNode != nil {
+				retained = append(retained, &projectedField{Node: fieldNode, field: field})
+			}
+		}
+	}
+	if len(retained) == 0 {
+		return nil
+	}
+	if isVariant(node) {
+		retained = projectVariantFields(node, retained)
+	}
+	return &projectedGroup{Node: node, fields: retained}
+}
+
 func fieldByName(node Node, name string) Field {
 	for _, f := range node.Fields() {
 		if f.Name() == name {
@@ -78,6 +78,9 @@ func nullIndexFuncOf(t reflect.Type) nullIndexFunc {
 	case reflect.Map:
 		return nullIndexPointer
 
+	case reflect.Interface:
+		return nullIndex[any]
+
 	case reflect.Array:
 		if t.Elem().Kind() == reflect.Uint8 {
 			switch size := t.Len(); size {
@@ -49,6 +49,14 @@ func streakOfFalse(data []bool) int {
 	return len(data)
 }
 
+func orderOfFloat16(data [][float16Size]byte) int {
+	values := make([]float32, len(data))
+	for i := range data {
+		values[i] = float16FromBytes(data[i][:])
+	}
+	return orderOfFloat32(values)
+}
+
 func orderOfBytes(data [][]byte) int {
 	switch len(data) {
 	case 0, 1:
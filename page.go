@@ -1158,6 +1158,35 @@ func (page *fixedLenByteArrayPage) makeValueString(v string) Value {
 	return value
 }
 
+type float16Page struct{ fixedLenByteArrayPage }
+
+func newFloat16Page(typ Type, columnIndex int16, numValues int32, values encoding.Values) *float16Page {
+	return &float16Page{*newFixedLenByteArrayPage(typ, columnIndex, numValues, values)}
+}
+
+func (page *float16Page) min() []byte {
+	min, _ := boundsFloat16(page.data)
+	return min
+}
+
+func (page *float16Page) max() []byte {
+	_, max := boundsFloat16(page.data)
+	return max
+}
+
+func (page *float16Page) Bounds() (min, max Value, ok bool) {
+	if ok = len(page.data) > 0; ok {
+		minBytes, maxBytes := boundsFloat16(page.data)
+		min = page.makeValueBytes(minBytes)
+		max = page.makeValueBytes(maxBytes)
+	}
+	return min, max, ok
+}
+
+func (page *float16Page) Slice(i, j int64) Page {
+	return &float16Page{*page.fixedLenByteArrayPage.Slice(i, j).(*fixedLenByteArrayPage)}
+}
+
 type uint32Page struct {
 	typ         Type
 	values      []uint32
@@ -0,0 +1,317 @@
+package parquet
+
+import (
+	"io"
+	"sync"
+	"unsafe"
+)
+
+// parallelRowBatchSize is the number of rows that the goroutines decoding row
+// groups concurrently read at once.
+const parallelRowBatchSize = 1024
+
+// parallelRowGroup is a RowGroup made of a sequence of row groups which are
+// decoded concurrently by a pool of goroutines when reading rows. The rows are
+// returned in the same order as if the row groups were read sequentially.
+type parallelRowGroup struct {
+	RowGroup
+	rowGroups      []RowGroup
+	concurrency    int
+	readAheadBytes int64
+}
+
+// parallelRowGroupOf returns a RowGroup decoding the row groups that rowGroup
+// is made of with the given number of goroutines. The row group is returned
+// unchanged if there is nothing to decode concurrently.
+func parallelRowGroupOf(rowGroup RowGroup, concurrency int, readAheadBytes int64) RowGroup {
+	if concurrency <= 1 {
+		return rowGroup
+	}
+	rowGroups := splitRowGroup(rowGroup)
+	if len(rowGroups) <= 1 {
+		return rowGroup
+	}
+	return &parallelRowGroup{
+		RowGroup:       rowGroup,
+		rowGroups:      rowGroups,
+		concurrency:    concurrency,
+		readAheadBytes: readAheadBytes,
+	}
+}
+
+// splitRowGroup returns the sequence of row groups that rowGroup is made of,
+// which can be read independently from each other.
+func splitRowGroup(rowGroup RowGroup) []RowGroup {
+	switch r := rowGroup.(type) {
+	case *multiRowGroup:
+		return r.rowGroups
+	case *concatRowGroup:
+		return r.rowGroups
+	case *Dataset:
+		return r.rowGroups
+	case *convertedRowGroup:
+		rowGroups := splitRowGroup(r.rowGroup)
+		if len(rowGroups) <= 1 {
+			break
+		}
+		converted := make([]RowGroup, len(rowGroups))
+		for i, rowGroup := range rowGroups {
+			converted[i] = ConvertRowGroup(rowGroup, r.conv)
+		}
+		return converted
+	}
+	return []RowGroup{rowGroup}
+}
+
+func (r *parallelRowGroup) Rows() Rows {
+	rows := &parallelRows{rowGroup: r}
+	rows.cond.L = &rows.mutex
+	return rows
+}
+
+// parallelRows is the implementation of Rows for parallelRowGroup.
+//
+// Each goroutine decodes the next row group which is not being decoded yet, in
+// batches of rows which are queued on the row group's task. The rows are read
+// from the batches of the task at the head of the list, and the tasks after it
+// stop decoding rows when the size of the queued batches exceeds the read-ahead
+// limit. The task at the head is only limited by the size of its own batches,
+// which guarantees progress since they are consumed first.
+type parallelRows struct {
+	rowGroup *parallelRowGroup
+	workers  sync.WaitGroup
+	started  bool
+
+	mutex  sync.Mutex
+	cond   sync.Cond
+	tasks  []parallelRowGroupTask
+	next   int   // index of the next row group to decode
+	head   int   // index of the row group that rows are read from
+	offset int   // index of the next row in the first batch of the head task
+	bytes  int64 // size of the batches queued on all tasks
+	closed bool
+}
+
+type parallelRowGroupTask struct {
+	seek    int64 // row to seek to before reading rows from the row group
+	batches []parallelRowBatch
+	bytes   int64 // size of the batches queued on the task
+	done    bool
+	err     error
+}
+
+type parallelRowBatch struct {
+	rows []Row
+	size int64
+}
+
+func (r *parallelRows) start(rowGroupIndex int, rowIndex int64) {
+	r.tasks = make([]parallelRowGroupTask, len(r.rowGroup.rowGroups))
+	r.next, r.head, r.offset = rowGroupIndex, rowGroupIndex, 0
+	r.bytes = 0
+	r.closed = false
+	r.started = true
+
+	if rowGroupIndex < len(r.tasks) {
+		r.tasks[rowGroupIndex].seek = rowIndex
+	}
+	for range min(r.rowGroup.concurrency, len(r.tasks)-rowGroupIndex) {
+		r.workers.Add(1)
+		go r.decode()
+	}
+}
+
+func (r *parallelRows) stop() {
+	if !r.started {
+		return
+	}
+	r.mutex.Lock()
+	r.closed = true
+	r.cond.Broadcast()
+	r.mutex.Unlock()
+	r.workers.Wait()
+	r.tasks = nil
+	r.started = false
+}
+
+func (r *parallelRows) decode() {
+	defer r.workers.Done()
+	for {
+		r.mutex.Lock()
+		if r.closed || r.next == len(r.tasks) {
+			r.mutex.Unlock()
+			return
+		}
+		i := r.next
+		r.next++
+		seek := r.tasks[i].seek
+		r.mutex.Unlock()
+
+		err := r.decodeRowGroup(i, seek)
+
+		r.mutex.Lock()
+		task := &r.tasks[i]
+		task.done, task.err = true, err
+		r.cond.Broadcast()
+		r.mutex.Unlock()
+	}
+}
+
+func (r *parallelRows) decodeRowGroup(i int, seek int64) error {
+	rows := r.rowGroup.rowGroups[i].Rows()
+	defer rows.Close()
+
+	if seek > 0 {
+		if err := rows.SeekToRow(seek); err != nil {
+			return err
+		}
+	}
+
+	buf := make([]Row, parallelRowBatchSize)
+	for {
+		n, err := rows.ReadRows(buf)
+		if n > 0 {
+			// The values of rows may be backed by the pages of the row group,
+			// which are reused when reading the next rows.
+			batch := parallelRowBatch{rows: make([]Row, n)}
+			for j, row := range buf[:n] {
+				batch.rows[j] = row.Clone()
+				batch.size += sizeOfRow(row)
+			}
+			if !r.queue(i, batch) {
+				return nil
+			}
+		}
+		if err != nil {
+			if err == io.EOF {
+				err = nil
+			}
+			return err
+		}
+	}
+}
+
+// queue appends a batch of rows to the task of row group i, waiting for the
+// size of the queued batches to be below the read-ahead limit. The method
+// returns false if the rows were closed.
+func (r *parallelRows) queue(i int, batch parallelRowBatch) bool {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	task := &r.tasks[i]
+	for !r.closed {
+		queued := r.bytes
+		if i == r.head {
+			queued = task.bytes
+		}
+		if queued == 0 || queued+batch.size <= r.rowGroup.readAheadBytes {
+			break
+		}
+		r.cond.Wait()
+	}
+	if r.closed {
+		return false
+	}
+	task.batches = append(task.batches, batch)
+	task.bytes += batch.size
+	r.bytes += batch.size
+	r.cond.Broadcast()
+	return true
+}
+
+func (r *parallelRows) ReadRows(rows []Row) (int, error) {
+	if !r.started {
+		r.start(0, 0)
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	n := 0
+	for n < len(rows) && r.head < len(r.tasks) {
+		task := &r.tasks[r.head]
+		switch {
+		case len(task.batches) > 0:
+			// The rows of batches are clones owned by the task, they are
+			// handed over to the caller instead of being copied.
+			batch := &task.batches[0]
+			for n < len(rows) && r.offset < len(batch.rows) {
+				rows[n], batch.rows[r.offset] = batch.rows[r.offset], nil
+				r.offset++
+				n++
+			}
+			if r.offset == len(batch.rows) {
+				r.bytes -= batch.size
+				task.bytes -= batch.size
+				task.batches[0] = parallelRowBatch{}
+				task.batches = task.batches[1:]
+				r.offset = 0
+				r.cond.Broadcast()
+			}
+		case task.done:
+			if task.err != nil {
+				return n, task.err
+			}
+			r.head++
+			r.cond.Broadcast()
+		case n > 0:
+			return n, nil
+		default:
+			r.cond.Wait()
+		}
+	}
+
+	if r.head == len(r.tasks) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+func (r *parallelRows) SeekToRow(rowIndex int64) error {
+	r.stop()
+
+	rowGroupIndex := 0
+	for _, rowGroup := range r.rowGroup.rowGroups {
+		if f, ok := rowGroup.(*filteredRowGroup); ok && f.match != nil {
+			// The number of rows matching the predicate is not known until
+			// they are read, the remaining rows are skipped below.
+			break
+		}
+		numRows := rowGroup.NumRows()
+		if rowIndex < numRows {
+			break
+		}
+		rowIndex -= numRows
+		rowGroupIndex++
+	}
+
+	if rowGroupIndex < len(r.rowGroup.rowGroups) {
+		if f, ok := r.rowGroup.rowGroups[rowGroupIndex].(*filteredRowGroup); ok && f.match != nil {
+			r.start(rowGroupIndex, 0)
+			_, err := skipRows(r, rowIndex)
+			return err
+		}
+	}
+	r.start(rowGroupIndex, rowIndex)
+	return nil
+}
+
+func (r *parallelRows) Reset() { r.stop() }
+
+func (r *parallelRows) Close() error {
+	r.stop()
+	return nil
+}
+
+func (r *parallelRows) Schema() *Schema { return r.rowGroup.Schema() }
+
+// sizeOfRow returns the approximate amount of memory held by the values of row.
+func sizeOfRow(row Row) int64 {
+	size := int64(len(row)) * int64(unsafe.Sizeof(Value{}))
+	for i := range row {
+		switch row[i].Kind() {
+		case ByteArray, FixedLenByteArray:
+			size += int64(len(row[i].byteArray()))
+		}
+	}
+	return size
+}
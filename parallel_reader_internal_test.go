@@ -0,0 +1,56 @@
+package parquet
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestParallelRowsReadAheadHead(t *testing.T) {
+	type row struct {
+		ID   int64  `parquet:"id"`
+		Name string `parquet:"name"`
+	}
+	rows := make([]row, 20*parallelRowBatchSize)
+	for i := range rows {
+		rows[i] = row{ID: int64(i), Name: fmt.Sprintf("name-%d", i)}
+	}
+	b := new(bytes.Buffer)
+	w := NewGenericWriter[row](b, MaxRowsPerRowGroup(int64(len(rows)/2)))
+	if _, err := w.Write(rows); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	f, err := OpenFile(bytes.NewReader(b.Bytes()), int64(b.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rowGroup := parallelRowGroupOf(newConcatRowGroup(f.Schema(), f.RowGroups()), 2, 1)
+	r := rowGroup.Rows().(*parallelRows)
+	defer r.Close()
+
+	// Reading a single row starts decoding the row groups, the consumer then
+	// stops reading, which must not let the head task decode the whole row
+	// group ahead of it.
+	buf := make([]Row, 1)
+	if _, err := r.ReadRows(buf); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(100 * time.Millisecond)
+
+	r.mutex.Lock()
+	head := r.tasks[r.head]
+	queued := r.bytes
+	r.mutex.Unlock()
+
+	if len(head.batches) > 1 {
+		t.Errorf("too many batches queued on the head task: %d", len(head.batches))
+	}
+	if maxBytes := 3 * sizeOfRow(buf[0]) * parallelRowBatchSize; queued > maxBytes {
+		t.Errorf("too many bytes queued: %d > %d", queued, maxBytes)
+	}
+}
@@ -0,0 +1,129 @@
+package parquet_test
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"testing"
+
+	"github.com/parquet-go/parquet-go"
+)
+
+type parallelReaderRow struct {
+	ID   int64  `parquet:"id"`
+	Name string `parquet:"name"`
+}
+
+func parallelReaderRows() []parallelReaderRow {
+	rows := make([]parallelReaderRow, 10_000)
+	for i := range rows {
+		rows[i] = parallelReaderRow{ID: int64(i), Name: fmt.Sprintf("name-%d", i)}
+	}
+	return rows
+}
+
+func TestParallelReader(t *testing.T) {
+	rows := parallelReaderRows()
+	file, _ := writeTestFile(t, rows, parquet.MaxRowsPerRowGroup(500), parquet.DataPageRowCountLimit(100))
+
+	for _, readAheadBytes := range []int64{1, 100e3, parquet.DefaultReadAheadBytes} {
+		t.Run(fmt.Sprintf("readAheadBytes=%d", readAheadBytes), func(t *testing.T) {
+			reader := parquet.NewGenericReader[parallelReaderRow](file,
+				parquet.ReadConcurrency(4),
+				parquet.ReadAheadBytes(readAheadBytes),
+			)
+			defer reader.Close()
+
+			var read []parallelReaderRow
+			buf := make([]parallelReaderRow, 333)
+			for {
+				n, err := reader.Read(buf)
+				read = append(read, buf[:n]...)
+				if err == io.EOF {
+					break
+				}
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+			if !reflect.DeepEqual(read, rows) {
+				t.Errorf("wrong rows: want=%d rows got=%d rows", len(rows), len(read))
+			}
+		})
+	}
+}
+
+func TestParallelReaderSeekToRow(t *testing.T) {
+	rows := parallelReaderRows()
+	file, _ := writeTestFile(t, rows, parquet.MaxRowsPerRowGroup(500), parquet.DataPageRowCountLimit(100))
+
+	reader := parquet.NewReader(file, parquet.ReadConcurrency(3))
+	defer reader.Close()
+
+	for _, rowIndex := range []int64{4321, 12, 9999, 500} {
+		if err := reader.SeekToRow(rowIndex); err != nil {
+			t.Fatal(err)
+		}
+		var row parallelReaderRow
+		if err := reader.Read(&row); err != nil {
+			t.Fatal(err)
+		}
+		if row != rows[rowIndex] {
+			t.Errorf("wrong row after seeking to %d: want=%+v got=%+v", rowIndex, rows[rowIndex], row)
+		}
+	}
+
+	reader.Reset()
+	var row parallelReaderRow
+	if err := reader.Read(&row); err != nil {
+		t.Fatal(err)
+	}
+	if row != rows[0] {
+		t.Errorf("wrong row after reset: want=%+v got=%+v", rows[0], row)
+	}
+}
+
+func TestParallelReaderFilter(t *testing.T) {
+	rows := parallelReaderRows()
+	file, _ := writeTestFile(t, rows, parquet.MaxRowsPerRowGroup(500), parquet.DataPageRowCountLimit(100))
+
+	reader := parquet.NewGenericReader[parallelReaderRow](file,
+		parquet.ReadConcurrency(4),
+		parquet.Filter(parquet.Or(
+			parquet.Lt([]string{"id"}, parquet.ValueOf(int64(10))),
+			parquet.Eq([]string{"name"}, parquet.ValueOf("name-7777")),
+			parquet.Ge([]string{"id"}, parquet.ValueOf(int64(9995))),
+		)),
+	)
+	defer reader.Close()
+
+	var want []parallelReaderRow
+	want = append(want, rows[:10]...)
+	want = append(want, rows[7777])
+	want = append(want, rows[9995:]...)
+
+	buf := make([]parallelReaderRow, 100)
+	n, err := reader.Read(buf)
+	if err != nil && err != io.EOF {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(buf[:n], want) {
+		t.Errorf("wrong rows:\nwant: %+v\ngot:  %+v", want, buf[:n])
+	}
+
+	if err := reader.SeekToRow(10); err != nil {
+		t.Fatal(err)
+	}
+	if n, err := reader.Read(buf[:1]); n != 1 || err != nil {
+		t.Fatalf("n=%d err=%v", n, err)
+	}
+	if buf[0] != rows[7777] {
+		t.Errorf("wrong row after seek: want=%+v got=%+v", rows[7777], buf[0])
+	}
+}
+
+func TestReadConcurrencyInvalid(t *testing.T) {
+	if _, err := parquet.NewReaderConfig(parquet.ReadConcurrency(-1)); err == nil {
+		t.Error("expected an error for a negative read concurrency")
+	}
+}
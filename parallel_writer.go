@@ -0,0 +1,335 @@
+package parquet
+
+import (
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/parquet-go/parquet-go/format"
+)
+
+// parquetMagic is the magic header and footer of unencrypted parquet files.
+const parquetMagic = "PAR1"
+
+// ParallelFileWriter writes a parquet file made of row groups which are
+// encoded concurrently by multiple goroutines.
+//
+// Each goroutine obtains a ParallelRowGroupWriter by calling NewRowGroup, and
+// writes rows to it independently of the other row group writers. The pages of
+// the row groups are encoded into buffers acquired from the pool configured
+// with the ColumnPageBuffers option; when a row group writer is closed, the
+// content of its buffer is copied to the output, after the row groups which
+// were previously closed. The footer, column indexes and offset indexes of the
+// file are written when the ParallelFileWriter is closed.
+//
+// Row groups are written to the file in the order in which their writers were
+// closed. Encrypted files cannot be written with ParallelFileWriter.
+type ParallelFileWriter[T any] struct {
+	output  io.WriterAt
+	options []WriterOption
+	schema  *Schema
+	buffers BufferPool
+	footer  *Writer
+
+	mutex         sync.Mutex
+	offset        int64 // offset where the next row group is written
+	open          int   // number of row group writers not closed yet
+	writers       []*GenericWriter[T]
+	rowGroups     []format.RowGroup
+	columnIndexes [][]format.ColumnIndex
+	offsetIndexes [][]format.OffsetIndex
+	closed        bool
+	err           error
+}
+
+// NewParallelFileWriter constructs a ParallelFileWriter writing a parquet file
+// to output. The schema of the file is the one configured in the options, or
+// the schema of T.
+//
+// The function panics if the writer configuration is invalid, if the schema
+// cannot be determined, or if the configuration enables encryption.
+func NewParallelFileWriter[T any](output io.WriterAt, options ...WriterOption) *ParallelFileWriter[T] {
+	config, err := NewWriterConfig(options...)
+	if err != nil {
+		panic(err)
+	}
+	if config.Encryption != nil {
+		panic("parquet.NewParallelFileWriter: encrypted files cannot be written with a ParallelFileWriter")
+	}
+
+	schema := config.Schema
+	if schema == nil {
+		if t := typeOf[T](); t != nil {
+			schema = schemaOf(dereference(t))
+		}
+	}
+	if schema == nil {
+		panic("parquet.NewParallelFileWriter: the schema must be configured with an option when T is an interface type")
+	}
+
+	options = append(options[:len(options):len(options)], schema)
+	return &ParallelFileWriter[T]{
+		output:  output,
+		options: options,
+		schema:  schema,
+		buffers: config.ColumnPageBuffers,
+		footer:  NewWriter(nil, options...),
+		offset:  int64(len(parquetMagic)),
+	}
+}
+
+// Schema returns the schema of rows written to w.
+func (w *ParallelFileWriter[T]) Schema() *Schema { return w.schema }
+
+// SetKeyValueMetadata sets a key/value pair in the metadata of the file.
+func (w *ParallelFileWriter[T]) SetKeyValueMetadata(key, value string) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	w.footer.SetKeyValueMetadata(key, value)
+}
+
+// NewRowGroup returns a new writer for row groups of the file. The row group
+// writer must be closed for its rows to be written to the file.
+//
+// The method is safe to call concurrently from multiple goroutines.
+func (w *ParallelFileWriter[T]) NewRowGroup() *ParallelRowGroupWriter[T] {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if w.closed {
+		panic("parquet: NewRowGroup called on a closed ParallelFileWriter")
+	}
+	w.open++
+
+	var writer *GenericWriter[T]
+	if n := len(w.writers); n > 0 {
+		writer, w.writers = w.writers[n-1], w.writers[:n-1]
+	} else {
+		writer = NewGenericWriter[T](nil, w.options...)
+	}
+
+	buffer := w.buffers.GetBuffer()
+	writer.Reset(buffer)
+	return &ParallelRowGroupWriter[T]{file: w, writer: writer, buffer: buffer}
+}
+
+// Close writes the footer of the file. All the row group writers must have been
+// closed before calling this method.
+func (w *ParallelFileWriter[T]) Close() error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if w.closed {
+		return w.err
+	}
+	if w.open != 0 {
+		return fmt.Errorf("parquet: cannot close ParallelFileWriter with %d row group writers still open", w.open)
+	}
+	w.closed = true
+	w.writers = nil
+	if w.err != nil {
+		return w.err
+	}
+
+	if _, err := w.output.WriteAt([]byte(parquetMagic), 0); err != nil {
+		w.err = err
+		return err
+	}
+
+	footer := w.footer.writer
+	footer.reset(io.NewOffsetWriter(w.output, w.offset))
+	footer.writer.offset = w.offset
+	footer.rowGroups = w.rowGroups
+	footer.columnIndexes = w.columnIndexes
+	footer.offsetIndexes = w.offsetIndexes
+
+	if err := footer.writeFileFooter(); err != nil {
+		w.err = err
+		return err
+	}
+	if footer.buffer != nil {
+		w.err = footer.buffer.Flush()
+	}
+	return w.err
+}
+
+// FileMetaData returns the metadata written in the footer of the file. The
+// method returns nil if the writer has not been closed.
+func (w *ParallelFileWriter[T]) FileMetaData() *format.FileMetaData {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	if !w.closed || w.err != nil {
+		return nil
+	}
+	return &w.footer.writer.fileMetaData
+}
+
+// commit reserves the space of the row groups encoded in the buffer in the
+// output file, returning the offset where they must be copied.
+func (w *ParallelFileWriter[T]) commit(writer *writer, size int64) (int64, error) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if w.err != nil {
+		return 0, w.err
+	}
+	if len(w.rowGroups)+len(writer.rowGroups) > MaxRowGroups {
+		return 0, ErrTooManyRowGroups
+	}
+
+	// The buffer starts with the magic header of the parquet file, which is
+	// not copied to the output.
+	offset := w.offset
+	shift := offset - int64(len(parquetMagic))
+	w.offset += size - int64(len(parquetMagic))
+
+	for i, rowGroup := range writer.rowGroups {
+		rowGroup.FileOffset += shift
+		rowGroup.Ordinal = int16(len(w.rowGroups))
+		for j := range rowGroup.Columns {
+			c := &rowGroup.Columns[j]
+			if c.FileOffset != 0 {
+				c.FileOffset += shift
+			}
+			c.MetaData.DataPageOffset += shift
+			if c.MetaData.DictionaryPageOffset != 0 {
+				c.MetaData.DictionaryPageOffset += shift
+			}
+			if c.MetaData.BloomFilterOffset != 0 {
+				c.MetaData.BloomFilterOffset += shift
+			}
+		}
+		offsetIndexes := writer.offsetIndexes[i]
+		for j := range offsetIndexes {
+			pageLocations := offsetIndexes[j].PageLocations
+			for k := range pageLocations {
+				pageLocations[k].Offset += shift
+			}
+		}
+		w.rowGroups = append(w.rowGroups, rowGroup)
+		w.columnIndexes = append(w.columnIndexes, writer.columnIndexes[i])
+		w.offsetIndexes = append(w.offsetIndexes, offsetIndexes)
+	}
+	return offset, nil
+}
+
+// release returns the writer and buffer of a row group writer which was closed.
+// The error is the one that occurred copying the row groups of the writer to the
+// file, if any.
+func (w *ParallelFileWriter[T]) release(writer *GenericWriter[T], buffer io.ReadWriteSeeker, err error) {
+	writer.Reset(nil)
+	w.buffers.PutBuffer(buffer)
+
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	w.open--
+	w.writers = append(w.writers, writer)
+	if err != nil && w.err == nil {
+		// The file is left with a gap or a partially written row group, it
+		// cannot be completed.
+		w.err = err
+	}
+}
+
+// ParallelRowGroupWriter writes rows to row groups of a file written by a
+// ParallelFileWriter. Row group writers are not safe for concurrent use, but
+// each of them can be used by a different goroutine.
+//
+// Rows are written to a single row group, unless the limits configured with
+// the MaxRowsPerRowGroup or MaxRowGroupBytes options are reached, or Flush is
+// called, in which case a new row group is started.
+type ParallelRowGroupWriter[T any] struct {
+	file   *ParallelFileWriter[T]
+	writer *GenericWriter[T]
+	buffer io.ReadWriteSeeker
+}
+
+// Write writes rows to the row group.
+func (w *ParallelRowGroupWriter[T]) Write(rows []T) (int, error) {
+	if w.writer == nil {
+		return 0, io.ErrClosedPipe
+	}
+	return w.writer.Write(rows)
+}
+
+// WriteRows writes rows to the row group.
+func (w *ParallelRowGroupWriter[T]) WriteRows(rows []Row) (int, error) {
+	if w.writer == nil {
+		return 0, io.ErrClosedPipe
+	}
+	return w.writer.WriteRows(rows)
+}
+
+// WriteRowGroup writes the rows of rowGroup to the row group.
+func (w *ParallelRowGroupWriter[T]) WriteRowGroup(rowGroup RowGroup) (int64, error) {
+	if w.writer == nil {
+		return 0, io.ErrClosedPipe
+	}
+	return w.writer.WriteRowGroup(rowGroup)
+}
+
+// Flush ends the current row group, starting a new one for the rows written
+// after it.
+func (w *ParallelRowGroupWriter[T]) Flush() error {
+	if w.writer == nil {
+		return io.ErrClosedPipe
+	}
+	return w.writer.Flush()
+}
+
+// Schema returns the schema of rows written to w.
+func (w *ParallelRowGroupWriter[T]) Schema() *Schema { return w.file.schema }
+
+// Close encodes the rows written to w and copies the row groups to the file.
+// The method is called concurrently with the Close methods of other row group
+// writers of the same file; the file being written is left incomplete if an
+// error occurs copying the row groups.
+func (w *ParallelRowGroupWriter[T]) Close() (err error) {
+	if w.writer == nil {
+		return nil
+	}
+	writer, buffer := w.writer, w.buffer
+	w.writer, w.buffer = nil, nil
+
+	// Errors that occur before the row groups are committed do not affect the
+	// file, the row groups are discarded.
+	var committed bool
+	defer func() {
+		if !committed {
+			w.file.release(writer, buffer, nil)
+		} else {
+			w.file.release(writer, buffer, err)
+		}
+	}()
+
+	if err := writer.Flush(); err != nil {
+		return err
+	}
+	base := writer.base.writer
+	if base.buffer != nil {
+		if err := base.buffer.Flush(); err != nil {
+			return err
+		}
+	}
+	if len(base.rowGroups) == 0 {
+		return nil
+	}
+
+	size := base.writer.offset
+	offset, err := w.file.commit(base, size)
+	if err != nil {
+		return err
+	}
+	committed = true
+
+	if _, err := buffer.Seek(int64(len(parquetMagic)), io.SeekStart); err != nil {
+		return err
+	}
+	_, err = io.CopyN(io.NewOffsetWriter(w.file.output, offset), buffer, size-int64(len(parquetMagic)))
+	return err
+}
+
+var (
+	_ RowWriter      = (*ParallelRowGroupWriter[any])(nil)
+	_ RowGroupWriter = (*ParallelRowGroupWriter[any])(nil)
+)
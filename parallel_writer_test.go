@@ -0,0 +1,200 @@
+package parquet_test
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/parquet-go/parquet-go"
+)
+
+type parallelWriterRow struct {
+	ID       int64  `parquet:"id"`
+	Name     string `parquet:"name"`
+	Category string `parquet:"category,dict"`
+}
+
+func openParallelWriterFile(t *testing.T, f *os.File) *parquet.File {
+	t.Helper()
+	s, err := f.Stat()
+	if err != nil {
+		t.Fatal(err)
+	}
+	file, err := parquet.OpenFile(f, s.Size())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return file
+}
+
+func TestParallelFileWriter(t *testing.T) {
+	const numWriters = 8
+	const rowsPerWriter = 1000
+
+	f, err := os.Create(filepath.Join(t.TempDir(), "file.parquet"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	w := parquet.NewParallelFileWriter[parallelWriterRow](f,
+		parquet.MaxRowsPerRowGroup(600),
+		parquet.DataPageRowCountLimit(100),
+		parquet.BloomFilters(parquet.SplitBlockFilter(10, "name")),
+		parquet.Compression(&parquet.Snappy),
+	)
+	w.SetKeyValueMetadata("key", "value")
+
+	var want []parallelWriterRow
+	errs := make([]error, numWriters)
+	wg := sync.WaitGroup{}
+	for i := range numWriters {
+		rows := make([]parallelWriterRow, rowsPerWriter)
+		for j := range rows {
+			id := int64(i*rowsPerWriter + j)
+			rows[j] = parallelWriterRow{ID: id, Name: fmt.Sprintf("name-%d", id), Category: fmt.Sprint(id % 5)}
+		}
+		want = append(want, rows...)
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			rowGroup := w.NewRowGroup()
+			if _, err := rowGroup.Write(rows); err != nil {
+				errs[i] = err
+			}
+			if err := rowGroup.Close(); err != nil && errs[i] == nil {
+				errs[i] = err
+			}
+		}()
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	file := openParallelWriterFile(t, f)
+	if numRows := file.NumRows(); numRows != numWriters*rowsPerWriter {
+		t.Errorf("wrong number of rows: want=%d got=%d", numWriters*rowsPerWriter, numRows)
+	}
+	// Each writer produced a row group of 600 rows and one of 400 rows.
+	if numRowGroups := len(file.RowGroups()); numRowGroups != 2*numWriters {
+		t.Errorf("wrong number of row groups: want=%d got=%d", 2*numWriters, numRowGroups)
+	}
+	if value, ok := file.Lookup("key"); !ok || value != "value" {
+		t.Errorf("wrong key/value metadata: %q", value)
+	}
+	if metadata := w.FileMetaData(); metadata == nil || metadata.NumRows != numWriters*rowsPerWriter {
+		t.Errorf("wrong file metadata: %+v", metadata)
+	}
+
+	got := make([]parallelWriterRow, len(want)+1)
+	n, err := parquet.NewGenericReader[parallelWriterRow](file).Read(got)
+	if err != nil && err != io.EOF {
+		t.Fatal(err)
+	}
+	got = got[:n]
+	slices.SortFunc(got, func(a, b parallelWriterRow) int { return int(a.ID - b.ID) })
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("wrong rows: want=%d rows got=%d rows", len(want), len(got))
+	}
+
+	// The page indexes and bloom filters of row groups point to the right
+	// locations of the file.
+	for _, rowGroup := range file.RowGroups() {
+		for _, chunk := range rowGroup.ColumnChunks() {
+			if _, err := chunk.ColumnIndex(); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := chunk.OffsetIndex(); err != nil {
+				t.Fatal(err)
+			}
+		}
+		rows := rowGroup.Rows()
+		buf := make([]parquet.Row, 1)
+		_, err := rows.ReadRows(buf)
+		rows.Close()
+		if err != nil && err != io.EOF {
+			t.Fatal(err)
+		}
+		firstID := buf[0][0].Int64()
+		contains := firstID <= 7123 && 7123 < firstID+rowGroup.NumRows()
+		if ok, err := rowGroup.ColumnChunks()[1].BloomFilter().Check(parquet.ValueOf("name-7123")); err != nil {
+			t.Fatal(err)
+		} else if contains && !ok {
+			t.Errorf("bloom filter of row group starting at row %d does not match row 7123", firstID)
+		}
+	}
+
+	reader := parquet.NewGenericReader[parallelWriterRow](file,
+		parquet.Filter(parquet.Eq([]string{"name"}, parquet.ValueOf("name-4321"))),
+	)
+	defer reader.Close()
+	found := make([]parallelWriterRow, 2)
+	if n, err := reader.Read(found); n != 1 || found[0] != want[4321] {
+		t.Errorf("wrong rows matching the filter: n=%d err=%v rows=%+v", n, err, found[:n])
+	}
+	if stats := reader.FilterStats(); stats.SkippedRows < file.NumRows()-100 {
+		t.Errorf("page index was not used to skip rows: %+v", stats)
+	}
+}
+
+func TestParallelFileWriterEmpty(t *testing.T) {
+	f, err := os.Create(filepath.Join(t.TempDir(), "file.parquet"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	w := parquet.NewParallelFileWriter[parallelWriterRow](f)
+	if err := w.NewRowGroup().Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	file := openParallelWriterFile(t, f)
+	if numRows := file.NumRows(); numRows != 0 {
+		t.Errorf("wrong number of rows: %d", numRows)
+	}
+	if columns := file.Schema().Columns(); len(columns) != 3 {
+		t.Errorf("wrong columns: %q", columns)
+	}
+}
+
+func TestParallelFileWriterOpenRowGroups(t *testing.T) {
+	f, err := os.Create(filepath.Join(t.TempDir(), "file.parquet"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	w := parquet.NewParallelFileWriter[parallelWriterRow](f)
+	rowGroup := w.NewRowGroup()
+	if _, err := rowGroup.Write([]parallelWriterRow{{ID: 1}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err == nil {
+		t.Error("expected an error closing the file with open row group writers")
+	}
+	if err := rowGroup.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if numRows := openParallelWriterFile(t, f).NumRows(); numRows != 1 {
+		t.Errorf("wrong number of rows: %d", numRows)
+	}
+}
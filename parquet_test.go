@@ -504,6 +504,62 @@ func logRows[T any](t *testing.T, rows []T) {
 	}
 }
 
+// writeTestData writes rows to an in-memory parquet file with the given
+// writer options, and returns the content of the file.
+func writeTestData[T any](t *testing.T, rows []T, options ...parquet.WriterOption) []byte {
+	t.Helper()
+	b := new(bytes.Buffer)
+	w := parquet.NewGenericWriter[T](b, options...)
+	if _, err := w.Write(rows); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return b.Bytes()
+}
+
+// writeTestFile writes rows to an in-memory parquet file with the given writer
+// options and opens it. The file is read through the returned reader, which
+// records the reads after the file was opened.
+func writeTestFile[T any](t *testing.T, rows []T, options ...parquet.WriterOption) (*parquet.File, *countingReaderAt) {
+	t.Helper()
+	data := writeTestData(t, rows, options...)
+	input := &countingReaderAt{reader: bytes.NewReader(data)}
+	f, err := parquet.OpenFile(input, int64(len(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	input.bytesRead, input.reads = 0, nil
+	return f, input
+}
+
+// countingReaderAt counts the bytes read from the underlying reader, and
+// records the ranges that were read.
+type countingReaderAt struct {
+	reader    io.ReaderAt
+	bytesRead int64
+	reads     [][2]int64
+}
+
+func (r *countingReaderAt) ReadAt(b []byte, off int64) (int, error) {
+	n, err := r.reader.ReadAt(b, off)
+	r.bytesRead += int64(n)
+	r.reads = append(r.reads, [2]int64{off, off + int64(n)})
+	return n, err
+}
+
+// overlaps returns true if some of the bytes between offset and offset+length
+// were read.
+func (r *countingReaderAt) overlaps(offset, length int64) bool {
+	for _, read := range r.reads {
+		if read[0] < offset+length && offset < read[1] {
+			return true
+		}
+	}
+	return false
+}
+
 func TestNestedPointer(t *testing.T) {
 	type InnerStruct struct {
 		InnerField string
@@ -0,0 +1,395 @@
+package parquet
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// hiveDefaultPartition is the name of the partition that rows with null or
+// empty partition values are written to.
+const hiveDefaultPartition = "__HIVE_DEFAULT_PARTITION__"
+
+// OutputFS is an interface representing file systems that writers create
+// files in.
+//
+// File names are slash-separated paths, following the conventions of the
+// io/fs package. Implementations must create the parent directories of the
+// files if needed.
+type OutputFS interface {
+	Create(name string) (io.WriteCloser, error)
+}
+
+// OutputDir returns an OutputFS creating files in the local directory dir.
+func OutputDir(dir string) OutputFS { return outputDir(dir) }
+
+type outputDir string
+
+func (dir outputDir) Create(name string) (io.WriteCloser, error) {
+	filePath := filepath.Join(string(dir), filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+		return nil, err
+	}
+	return os.Create(filePath)
+}
+
+// PartitionedWriterConfig carries the configuration of a PartitionedWriter.
+type PartitionedWriterConfig struct {
+	// Output is the file system that the partitioned dataset is written to.
+	Output OutputFS
+
+	// PartitionColumns are the paths of the columns that rows are partitioned
+	// by. The columns must be leaf columns which are not repeated.
+	PartitionColumns [][]string
+
+	// When DropPartitionColumns is true, the partition columns are removed
+	// from the schema of the files, their values are only present in the
+	// directory names.
+	DropPartitionColumns bool
+
+	// MaxOpenPartitions is the maximum number of partitions that files are
+	// being written to at the same time. When it is reached, the file of the
+	// least recently written partition is closed before opening a new one,
+	// and rows written to that partition afterwards are written to a new
+	// file. Zero means that there are no limits.
+	MaxOpenPartitions int
+}
+
+// Validate returns a non-nil error if the configuration of c is invalid.
+func (c *PartitionedWriterConfig) Validate() error {
+	const baseName = "parquet.(*PartitionedWriterConfig)."
+	var reasons []error
+	if c.Output == nil {
+		reasons = append(reasons, errorInvalidOptionValue(baseName+"Output", nil))
+	}
+	if len(c.PartitionColumns) == 0 {
+		reasons = append(reasons, errorInvalidOptionValue(baseName+"PartitionColumns", c.PartitionColumns))
+	}
+	if c.MaxOpenPartitions < 0 {
+		reasons = append(reasons, errorInvalidOptionValue(baseName+"MaxOpenPartitions", c.MaxOpenPartitions))
+	}
+	return errorInvalidConfiguration(reasons...)
+}
+
+// PartitionedWriter writes rows to a dataset partitioned by the values of a
+// set of columns, using the Hive layout where each partition is a directory
+// named after the column values, for example:
+//
+//	date=2024-01-01/tenant=acme/part-00000.parquet
+//
+// Null and empty values are written to the __HIVE_DEFAULT_PARTITION__
+// partition. Characters which are not safe to use in file names are escaped
+// with their hexadecimal representation, for example "a/b" becomes "a%2Fb".
+type PartitionedWriter[T any] struct {
+	config   PartitionedWriterConfig
+	options  []WriterOption
+	schema   *Schema
+	columns  []partitionColumn
+	mapping  []int // column indexes in the file schema, -1 for dropped columns
+	rowbuf   []Row
+	files    map[string]int
+	open     map[string]*partition
+	lastUsed int64
+}
+
+type partitionColumn struct {
+	name        string
+	columnIndex int
+	typ         Type
+}
+
+type partition struct {
+	writer   *Writer
+	output   io.WriteCloser
+	rows     []Row
+	lastUsed int64
+}
+
+// NewPartitionedWriter constructs a new partitioned writer. The writer options
+// are applied to every file written by the writer.
+//
+// The function panics if the configuration is invalid, or if the partition
+// columns cannot be found in the schema.
+func NewPartitionedWriter[T any](config *PartitionedWriterConfig, options ...WriterOption) *PartitionedWriter[T] {
+	if err := config.Validate(); err != nil {
+		panic(err)
+	}
+	writerConfig, err := NewWriterConfig(options...)
+	if err != nil {
+		panic(err)
+	}
+	schema := writerConfig.Schema
+	if schema == nil {
+		if t := typeOf[T](); t != nil {
+			schema = schemaOf(dereference(t))
+		}
+	}
+	if schema == nil {
+		panic("partitioned writer must be instantiated with schema or concrete type.")
+	}
+
+	w := &PartitionedWriter[T]{
+		config:  *config,
+		options: slices.Clone(options),
+		schema:  schema,
+		files:   make(map[string]int),
+		open:    make(map[string]*partition),
+	}
+
+	partitionPaths := make([]columnPath, len(config.PartitionColumns))
+	for i, columnPath := range config.PartitionColumns {
+		leaf, ok := schema.Lookup(columnPath...)
+		if !ok {
+			panic(fmt.Sprintf("partition column %q not found in schema", strings.Join(columnPath, ".")))
+		}
+		if leaf.MaxRepetitionLevel > 0 {
+			panic(fmt.Sprintf("partition column %q must not be repeated", strings.Join(columnPath, ".")))
+		}
+		w.columns = append(w.columns, partitionColumn{
+			name:        columnPath[len(columnPath)-1],
+			columnIndex: leaf.ColumnIndex,
+			typ:         leaf.Node.Type(),
+		})
+		partitionPaths[i] = columnPath
+	}
+
+	if config.DropPartitionColumns {
+		root := withoutColumns(schema, nil, partitionPaths)
+		if root == nil {
+			panic("partitioned writer cannot drop all the columns of the schema")
+		}
+		fileSchema := NewSchema(schema.Name(), root)
+		w.mapping = make([]int, len(schema.Columns()))
+		for i, columnPath := range schema.Columns() {
+			w.mapping[i] = -1
+			if leaf, ok := fileSchema.Lookup(columnPath...); ok {
+				w.mapping[i] = leaf.ColumnIndex
+			}
+		}
+		schema = fileSchema
+	}
+
+	w.options = append(w.options, schema)
+	return w
+}
+
+// Close closes the files of all the partitions being written.
+func (w *PartitionedWriter[T]) Close() error {
+	var errs []error
+	for key, p := range w.open {
+		errs = append(errs, w.closePartition(key, p))
+	}
+	return errors.Join(errs...)
+}
+
+// Flush flushes the buffered rows of all the partitions being written to row
+// groups of their files.
+func (w *PartitionedWriter[T]) Flush() error {
+	for _, p := range w.open {
+		if p.writer == nil {
+			continue
+		}
+		if err := p.writer.Flush(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Schema returns the schema of rows written to w.
+func (w *PartitionedWriter[T]) Schema() *Schema { return w.schema }
+
+// Write writes rows to the files of the partitions that they belong to.
+func (w *PartitionedWriter[T]) Write(rows []T) (int, error) {
+	w.rowbuf = slices.Grow(w.rowbuf[:0], len(rows))[:len(rows)]
+	defer clearRows(w.rowbuf)
+
+	for i := range rows {
+		w.rowbuf[i] = w.schema.Deconstruct(w.rowbuf[i][:0], &rows[i])
+	}
+	return w.WriteRows(w.rowbuf)
+}
+
+// WriteRows writes rows to the files of the partitions that they belong to.
+//
+// The rows are expected to match the full schema, including the partition
+// columns.
+//
+// The rows of each partition are written together, in the order they appear
+// in rows, so writing rows of partitions in alternation does not open a new
+// file on every change of partition when MaxOpenPartitions is set.
+//
+// When writing to some partitions fails, the rows of the other partitions are
+// still written. The method returns the total number of rows written, which
+// is not the length of a prefix of rows, and an error for each partition that
+// could not be written, naming the partition.
+func (w *PartitionedWriter[T]) WriteRows(rows []Row) (int, error) {
+	var partitions []string
+
+	for _, row := range rows {
+		key := w.partitionOf(row)
+		p := w.open[key]
+		if p == nil {
+			p = new(partition)
+			w.open[key] = p
+		}
+		if len(p.rows) == 0 {
+			partitions = append(partitions, key)
+		}
+		if w.mapping != nil {
+			row = w.dropPartitionColumns(row)
+		}
+		p.rows = append(p.rows, row)
+	}
+
+	written := 0
+	var errs []error
+	for _, key := range partitions {
+		n, err := w.writePartition(key, w.open[key])
+		written += n
+		if err != nil {
+			errs = append(errs, fmt.Errorf("writing partition %q: %w", key, err))
+		}
+	}
+	return written, errors.Join(errs...)
+}
+
+func (w *PartitionedWriter[T]) writePartition(key string, p *partition) (int, error) {
+	defer func() {
+		if w.mapping != nil {
+			clearRows(p.rows)
+		}
+		p.rows = p.rows[:0]
+	}()
+
+	if p.writer == nil {
+		if err := w.openPartition(key, p); err != nil {
+			return 0, err
+		}
+	}
+	w.lastUsed++
+	p.lastUsed = w.lastUsed
+	return p.writer.WriteRows(p.rows)
+}
+
+func (w *PartitionedWriter[T]) openPartition(key string, p *partition) error {
+	if w.config.MaxOpenPartitions > 0 {
+		for w.numOpenPartitions() >= w.config.MaxOpenPartitions {
+			lruKey, lru := "", (*partition)(nil)
+			for k, q := range w.open {
+				if q.writer != nil && (lru == nil || q.lastUsed < lru.lastUsed) {
+					lruKey, lru = k, q
+				}
+			}
+			if err := w.closePartition(lruKey, lru); err != nil {
+				return err
+			}
+		}
+	}
+
+	index := w.files[key]
+	output, err := w.config.Output.Create(path.Join(key, fmt.Sprintf("part-%05d.parquet", index)))
+	if err != nil {
+		return err
+	}
+	w.files[key] = index + 1
+	p.output = output
+	p.writer = NewWriter(output, w.options...)
+	return nil
+}
+
+func (w *PartitionedWriter[T]) numOpenPartitions() int {
+	n := 0
+	for _, p := range w.open {
+		if p.writer != nil {
+			n++
+		}
+	}
+	return n
+}
+
+func (w *PartitionedWriter[T]) closePartition(key string, p *partition) error {
+	if len(p.rows) == 0 {
+		delete(w.open, key)
+	}
+	if p.writer == nil {
+		return nil
+	}
+	writer, output := p.writer, p.output
+	p.writer, p.output = nil, nil
+	if err := writer.Close(); err != nil {
+		output.Close()
+		return err
+	}
+	return output.Close()
+}
+
+func (w *PartitionedWriter[T]) partitionOf(row Row) string {
+	key := new(strings.Builder)
+	for i, column := range w.columns {
+		if i > 0 {
+			key.WriteByte('/')
+		}
+		key.WriteString(escapePartitionValue(column.name))
+		key.WriteByte('=')
+		key.WriteString(w.partitionValueOf(row, column))
+	}
+	return key.String()
+}
+
+func (w *PartitionedWriter[T]) partitionValueOf(row Row, column partitionColumn) string {
+	for _, v := range row {
+		if v.Column() != column.columnIndex {
+			continue
+		}
+		if v.IsNull() {
+			break
+		}
+		s, err := String().Type().ConvertValue(v, column.typ)
+		if err != nil || len(s.ByteArray()) == 0 {
+			break
+		}
+		return escapePartitionValue(string(s.ByteArray()))
+	}
+	return hiveDefaultPartition
+}
+
+func (w *PartitionedWriter[T]) dropPartitionColumns(row Row) Row {
+	values := make(Row, 0, len(row))
+	for _, v := range row {
+		if columnIndex := w.mapping[v.Column()]; columnIndex >= 0 {
+			values = append(values, v.Level(v.RepetitionLevel(), v.DefinitionLevel(), columnIndex))
+		}
+	}
+	return values
+}
+
+// escapePartitionValue escapes the characters of s which are not allowed in
+// Hive partition names.
+func escapePartitionValue(s string) string {
+	const hex = "0123456789ABCDEF"
+	var b []byte
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c < 0x20, c == 0x7F, strings.IndexByte("\"#%'*/:=?\\{[]^", c) >= 0:
+			if b == nil {
+				b = append(make([]byte, 0, len(s)+8), s[:i]...)
+			}
+			b = append(b, '%', hex[c>>4], hex[c&0xF])
+		default:
+			if b != nil {
+				b = append(b, c)
+			}
+		}
+	}
+	if b == nil {
+		return s
+	}
+	return string(b)
+}
@@ -0,0 +1,293 @@
+package parquet_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/parquet-go/parquet-go"
+)
+
+type memoryOutputFS map[string]*rollingWriterFile
+
+func (fsys memoryOutputFS) Create(name string) (io.WriteCloser, error) {
+	f := new(rollingWriterFile)
+	fsys[name] = f
+	return f, nil
+}
+
+func (fsys memoryOutputFS) names() []string {
+	names := make([]string, 0, len(fsys))
+	for name := range fsys {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+type partitionedRow struct {
+	Date   string  `parquet:"date"`
+	Tenant *string `parquet:"tenant,optional"`
+	Value  int64   `parquet:"value"`
+}
+
+func partitionedRows() []partitionedRow {
+	acme, other, empty := "acme", "a/b=c", ""
+	tenants := []*string{&acme, &other, nil, &empty}
+	rows := make([]partitionedRow, 100)
+	for i := range rows {
+		rows[i] = partitionedRow{
+			Date:   []string{"2024-01-01", "2024-01-02"}[i%2],
+			Tenant: tenants[(i/2)%len(tenants)],
+			Value:  int64(i),
+		}
+	}
+	return rows
+}
+
+func TestPartitionedWriter(t *testing.T) {
+	fsys := make(memoryOutputFS)
+	w := parquet.NewPartitionedWriter[partitionedRow](&parquet.PartitionedWriterConfig{
+		Output:           fsys,
+		PartitionColumns: [][]string{{"date"}, {"tenant"}},
+	})
+
+	rows := partitionedRows()
+	if n, err := w.Write(rows[:50]); err != nil {
+		t.Fatal(err)
+	} else if n != 50 {
+		t.Fatalf("wrong number of rows written: want=50 got=%d", n)
+	}
+	if _, err := w.Write(rows[50:]); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{
+		"date=2024-01-01/tenant=__HIVE_DEFAULT_PARTITION__/part-00000.parquet",
+		"date=2024-01-01/tenant=a%2Fb%3Dc/part-00000.parquet",
+		"date=2024-01-01/tenant=acme/part-00000.parquet",
+		"date=2024-01-02/tenant=__HIVE_DEFAULT_PARTITION__/part-00000.parquet",
+		"date=2024-01-02/tenant=a%2Fb%3Dc/part-00000.parquet",
+		"date=2024-01-02/tenant=acme/part-00000.parquet",
+	}
+	if got := fsys.names(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("wrong files:\nwant: %q\ngot:  %q", want, got)
+	}
+
+	numRows := 0
+	for name, file := range fsys {
+		values, err := parquet.Read[partitionedRow](bytes.NewReader(file.Bytes()), int64(file.Len()))
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		for _, row := range values {
+			if !reflect.DeepEqual(row, rows[row.Value]) {
+				t.Errorf("%s: wrong row: want=%+v got=%+v", name, rows[row.Value], row)
+			}
+			if want := "date=" + row.Date + "/"; name[:len(want)] != want {
+				t.Errorf("%s: row written to the wrong partition: %+v", name, row)
+			}
+		}
+		numRows += len(values)
+	}
+	if numRows != len(rows) {
+		t.Errorf("wrong number of rows: want=%d got=%d", len(rows), numRows)
+	}
+}
+
+func TestPartitionedWriterDropPartitionColumns(t *testing.T) {
+	fsys := make(memoryOutputFS)
+	w := parquet.NewPartitionedWriter[partitionedRow](&parquet.PartitionedWriterConfig{
+		Output:               fsys,
+		PartitionColumns:     [][]string{{"date"}},
+		DropPartitionColumns: true,
+	})
+
+	rows := partitionedRows()
+	if _, err := w.Write(rows); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	type fileRow struct {
+		Tenant *string `parquet:"tenant,optional"`
+		Value  int64   `parquet:"value"`
+	}
+
+	for _, date := range []string{"2024-01-01", "2024-01-02"} {
+		file := fsys["date="+date+"/part-00000.parquet"]
+		if file == nil {
+			t.Fatalf("missing file for partition %s: %q", date, fsys.names())
+		}
+		f, err := parquet.OpenFile(bytes.NewReader(file.Bytes()), int64(file.Len()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if columns := f.Schema().Columns(); !reflect.DeepEqual(columns, [][]string{{"tenant"}, {"value"}}) {
+			t.Errorf("wrong columns: %q", columns)
+		}
+
+		values, err := parquet.Read[fileRow](bytes.NewReader(file.Bytes()), int64(file.Len()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(values) != len(rows)/2 {
+			t.Errorf("wrong number of rows: want=%d got=%d", len(rows)/2, len(values))
+		}
+		for _, row := range values {
+			want := rows[row.Value]
+			if want.Date != date || !reflect.DeepEqual(row.Tenant, want.Tenant) {
+				t.Errorf("wrong row: want=%+v got=%+v", want, row)
+			}
+		}
+	}
+}
+
+func TestPartitionedWriterMaxOpenPartitions(t *testing.T) {
+	fsys := make(memoryOutputFS)
+	w := parquet.NewPartitionedWriter[partitionedRow](&parquet.PartitionedWriterConfig{
+		Output:            fsys,
+		PartitionColumns:  [][]string{{"date"}},
+		MaxOpenPartitions: 1,
+	})
+
+	rows := partitionedRows()
+	for i := range rows {
+		if _, err := w.Write(rows[i : i+1]); err != nil {
+			t.Fatal(err)
+		}
+		for name, file := range fsys {
+			// All files but the one being written must have been closed.
+			if !file.closed && name[:len("date=")+len(rows[i].Date)] != "date="+rows[i].Date {
+				t.Fatalf("%s: file of a least recently used partition was not closed", name)
+			}
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(fsys) != len(rows) {
+		t.Fatalf("wrong number of files: want=%d got=%d", len(rows), len(fsys))
+	}
+	if fsys["date=2024-01-01/part-00049.parquet"] == nil {
+		t.Errorf("missing file: %q", fsys.names())
+	}
+}
+
+func TestPartitionedWriterMaxOpenPartitionsSingleWrite(t *testing.T) {
+	fsys := make(memoryOutputFS)
+	w := parquet.NewPartitionedWriter[partitionedRow](&parquet.PartitionedWriterConfig{
+		Output:            fsys,
+		PartitionColumns:  [][]string{{"date"}},
+		MaxOpenPartitions: 1,
+	})
+
+	// The rows of a single write alternate between the partitions, they are
+	// grouped so each partition is written to a single file.
+	rows := partitionedRows()
+	if n, err := w.Write(rows); err != nil {
+		t.Fatal(err)
+	} else if n != len(rows) {
+		t.Fatalf("wrong number of rows written: want=%d got=%d", len(rows), n)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{
+		"date=2024-01-01/part-00000.parquet",
+		"date=2024-01-02/part-00000.parquet",
+	}
+	if got := fsys.names(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("wrong files:\nwant: %q\ngot:  %q", want, got)
+	}
+}
+
+type failingOutputFS struct {
+	memoryOutputFS
+	fail string
+}
+
+func (fsys failingOutputFS) Create(name string) (io.WriteCloser, error) {
+	if filepath.Dir(name) == fsys.fail {
+		return nil, errors.New("cannot create " + name)
+	}
+	return fsys.memoryOutputFS.Create(name)
+}
+
+func TestPartitionedWriterError(t *testing.T) {
+	fsys := failingOutputFS{memoryOutputFS: make(memoryOutputFS), fail: "date=2024-01-02"}
+	w := parquet.NewPartitionedWriter[partitionedRow](&parquet.PartitionedWriterConfig{
+		Output:           fsys,
+		PartitionColumns: [][]string{{"date"}},
+	})
+
+	// The rows of the partitions which can be written are written even if
+	// another partition fails, the error names the partition that failed.
+	rows := []partitionedRow{
+		{Date: "2024-01-01", Value: 0},
+		{Date: "2024-01-01", Value: 1},
+		{Date: "2024-01-02", Value: 2},
+		{Date: "2024-01-01", Value: 3},
+	}
+	n, err := w.Write(rows)
+	if err == nil {
+		t.Fatal("expected an error creating the file of a partition")
+	}
+	if !strings.Contains(err.Error(), "date=2024-01-02") {
+		t.Errorf("the error does not name the partition: %v", err)
+	}
+	if n != 3 {
+		t.Errorf("wrong number of rows written: want=3 got=%d", n)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []partitionedRow{rows[0], rows[1], rows[3]}
+	data := fsys.memoryOutputFS["date=2024-01-01/part-00000.parquet"].Bytes()
+	values, err := parquet.Read[partitionedRow](bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(values, want) {
+		t.Errorf("wrong rows: want=%+v got=%+v", want, values)
+	}
+}
+
+func TestPartitionedWriterOutputDir(t *testing.T) {
+	dir := t.TempDir()
+	w := parquet.NewPartitionedWriter[partitionedRow](&parquet.PartitionedWriterConfig{
+		Output:           parquet.OutputDir(dir),
+		PartitionColumns: [][]string{{"date"}},
+	})
+	if _, err := w.Write(partitionedRows()); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	values, err := parquet.ReadFile[partitionedRow](filepath.Join(dir, "date=2024-01-02", "part-00000.parquet"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(values) != 50 {
+		t.Errorf("wrong number of rows: want=50 got=%d", len(values))
+	}
+	if _, err := os.Stat(filepath.Join(dir, "date=2024-01-01", "part-00000.parquet")); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,942 @@
+package parquet
+
+import (
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/parquet-go/parquet-go/format"
+)
+
+// Predicate is an expression on the values of columns which is used to select
+// rows read from parquet files.
+//
+// Predicates are constructed by calling functions like Eq, Lt, or IsNull on
+// column paths, and composed with And, Or, and Not. Readers configured with a
+// predicate using the Filter option evaluate it against the statistics, page
+// indexes, bloom filters, and dictionaries of row groups, and skip row groups
+// and pages which cannot contain rows matching the predicate before decoding
+// their values.
+//
+// Comparisons are made with the ordering of the column types, after the values
+// of predicates were converted to the column types. Null values never match
+// comparisons, they only match IsNull. Columns that do not exist in the schema
+// of a row group are considered to contain only null values. When the column
+// is repeated, a row matches a comparison if any of its values does.
+type Predicate interface {
+	// Returns a human-readable representation of the predicate.
+	String() string
+
+	// Returns the ranges of rows in the row group which may match the
+	// predicate, and the ranges of rows which are known to match it.
+	rowRanges(rowGroup RowGroup) (maybe, must rowRanges)
+
+	// Returns a function evaluating the predicate on rows of the given schema.
+	// The method sets to true the elements of columns at the indexes of the
+	// columns that the predicate reads.
+	matchRowFunc(schema *Schema, columns []bool) func(Row) bool
+}
+
+type predicateOp int
+
+const (
+	opEq predicateOp = iota
+	opIn
+	opLt
+	opLe
+	opGt
+	opGe
+	opBetween
+	opIsNull
+)
+
+var predicateOpNames = [...]string{
+	opEq:      "eq",
+	opIn:      "in",
+	opLt:      "lt",
+	opLe:      "le",
+	opGt:      "gt",
+	opGe:      "ge",
+	opBetween: "between",
+	opIsNull:  "is_null",
+}
+
+// Eq constructs a predicate matching rows where the column at path is equal
+// to value.
+func Eq(path []string, value Value) Predicate {
+	return newColumnPredicate(opEq, path, value)
+}
+
+// In constructs a predicate matching rows where the column at path is equal to
+// one of the values.
+func In(path []string, values ...Value) Predicate {
+	return newColumnPredicate(opIn, path, values...)
+}
+
+// Lt constructs a predicate matching rows where the column at path is less
+// than value.
+func Lt(path []string, value Value) Predicate {
+	return newColumnPredicate(opLt, path, value)
+}
+
+// Le constructs a predicate matching rows where the column at path is less
+// than or equal to value.
+func Le(path []string, value Value) Predicate {
+	return newColumnPredicate(opLe, path, value)
+}
+
+// Gt constructs a predicate matching rows where the column at path is greater
+// than value.
+func Gt(path []string, value Value) Predicate {
+	return newColumnPredicate(opGt, path, value)
+}
+
+// Ge constructs a predicate matching rows where the column at path is greater
+// than or equal to value.
+func Ge(path []string, value Value) Predicate {
+	return newColumnPredicate(opGe, path, value)
+}
+
+// Between constructs a predicate matching rows where the column at path is
+// within the inclusive range [lower, upper].
+func Between(path []string, lower, upper Value) Predicate {
+	return newColumnPredicate(opBetween, path, lower, upper)
+}
+
+// IsNull constructs a predicate matching rows where the column at path is
+// null.
+func IsNull(path []string) Predicate {
+	return newColumnPredicate(opIsNull, path)
+}
+
+// And constructs a predicate matching rows which match all the predicates.
+//
+// And with no arguments matches all rows.
+func And(predicates ...Predicate) Predicate {
+	return &andPredicate{predicates: slices.Clone(predicates)}
+}
+
+// Or constructs a predicate matching rows which match any of the predicates.
+//
+// Or with no arguments matches no rows.
+func Or(predicates ...Predicate) Predicate {
+	return &orPredicate{predicates: slices.Clone(predicates)}
+}
+
+// Not constructs a predicate matching rows which do not match p.
+func Not(p Predicate) Predicate {
+	return &notPredicate{predicate: p}
+}
+
+type andPredicate struct{ predicates []Predicate }
+
+func (p *andPredicate) String() string { return formatPredicates("and", p.predicates) }
+
+func (p *andPredicate) rowRanges(rowGroup RowGroup) (maybe, must rowRanges) {
+	maybe = allRowRanges(rowGroup.NumRows())
+	must = maybe
+	for _, predicate := range p.predicates {
+		if len(maybe) == 0 {
+			break
+		}
+		m, n := predicate.rowRanges(rowGroup)
+		maybe = maybe.intersect(m)
+		must = must.intersect(n)
+	}
+	return maybe, must
+}
+
+func (p *andPredicate) matchRowFunc(schema *Schema, columns []bool) func(Row) bool {
+	match := matchRowFuncs(p.predicates, schema, columns)
+	return func(row Row) bool {
+		for _, f := range match {
+			if !f(row) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+type orPredicate struct{ predicates []Predicate }
+
+func (p *orPredicate) String() string { return formatPredicates("or", p.predicates) }
+
+func (p *orPredicate) rowRanges(rowGroup RowGroup) (maybe, must rowRanges) {
+	for _, predicate := range p.predicates {
+		m, n := predicate.rowRanges(rowGroup)
+		maybe = maybe.union(m)
+		must = must.union(n)
+	}
+	return maybe, must
+}
+
+func (p *orPredicate) matchRowFunc(schema *Schema, columns []bool) func(Row) bool {
+	match := matchRowFuncs(p.predicates, schema, columns)
+	return func(row Row) bool {
+		for _, f := range match {
+			if f(row) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+type notPredicate struct{ predicate Predicate }
+
+func (p *notPredicate) String() string { return "not(" + p.predicate.String() + ")" }
+
+func (p *notPredicate) rowRanges(rowGroup RowGroup) (maybe, must rowRanges) {
+	numRows := rowGroup.NumRows()
+	m, n := p.predicate.rowRanges(rowGroup)
+	return n.complement(numRows), m.complement(numRows)
+}
+
+func (p *notPredicate) matchRowFunc(schema *Schema, columns []bool) func(Row) bool {
+	match := p.predicate.matchRowFunc(schema, columns)
+	return func(row Row) bool { return !match(row) }
+}
+
+func matchRowFuncs(predicates []Predicate, schema *Schema, columns []bool) []func(Row) bool {
+	match := make([]func(Row) bool, len(predicates))
+	for i, p := range predicates {
+		match[i] = p.matchRowFunc(schema, columns)
+	}
+	return match
+}
+
+func formatPredicates(name string, predicates []Predicate) string {
+	s := new(strings.Builder)
+	s.WriteString(name)
+	s.WriteByte('(')
+	for i, p := range predicates {
+		if i > 0 {
+			s.WriteString(", ")
+		}
+		s.WriteString(p.String())
+	}
+	s.WriteByte(')')
+	return s.String()
+}
+
+type columnPredicate struct {
+	op     predicateOp
+	path   columnPath
+	values []Value
+}
+
+func newColumnPredicate(op predicateOp, path []string, values ...Value) *columnPredicate {
+	p := &columnPredicate{
+		op:     op,
+		path:   columnPath(slices.Clone(path)),
+		values: make([]Value, len(values)),
+	}
+	for i, v := range values {
+		p.values[i] = v.Clone()
+	}
+	return p
+}
+
+func (p *columnPredicate) String() string {
+	s := new(strings.Builder)
+	s.WriteString(predicateOpNames[p.op])
+	s.WriteByte('(')
+	s.WriteString(p.path.String())
+	for _, v := range p.values {
+		s.WriteString(", ")
+		s.WriteString(v.String())
+	}
+	s.WriteByte(')')
+	return s.String()
+}
+
+// columnValues returns the values of the predicate converted to typ. Null
+// values are removed since they never match comparisons. The boolean is false
+// if the values could not be converted, in which case the predicate cannot be
+// evaluated on the column.
+func (p *columnPredicate) columnValues(typ Type) ([]Value, bool) {
+	values := make([]Value, 0, len(p.values))
+	for _, v := range p.values {
+		if v.IsNull() {
+			continue
+		}
+		if v.Kind() != typ.Kind() {
+			converted, err := typ.ConvertValue(v, primitiveTypeOf(v))
+			if err != nil {
+				return nil, false
+			}
+			v = converted
+		}
+		values = append(values, v)
+	}
+	return values, true
+}
+
+// matchesNothing returns true if the predicate cannot match any values after
+// null values were removed from the list of values it compares with.
+func (p *columnPredicate) matchesNothing(values []Value) bool {
+	switch p.op {
+	case opIsNull:
+		return false
+	case opIn:
+		return len(values) == 0
+	default:
+		return len(values) < len(p.values)
+	}
+}
+
+// matchValue returns true if v matches the predicate.
+func (p *columnPredicate) matchValue(typ Type, values []Value, v Value) bool {
+	if v.IsNull() {
+		return p.op == opIsNull
+	}
+	switch p.op {
+	case opEq, opIn:
+		for _, value := range values {
+			if typ.Compare(v, value) == 0 {
+				return true
+			}
+		}
+		return false
+	case opLt:
+		return typ.Compare(v, values[0]) < 0
+	case opLe:
+		return typ.Compare(v, values[0]) <= 0
+	case opGt:
+		return typ.Compare(v, values[0]) > 0
+	case opGe:
+		return typ.Compare(v, values[0]) >= 0
+	case opBetween:
+		return typ.Compare(v, values[0]) >= 0 && typ.Compare(v, values[1]) <= 0
+	default:
+		return false
+	}
+}
+
+// matchBounds returns whether some of the non-null values within the bounds
+// [min, max] may match the predicate, and whether all of them do.
+func (p *columnPredicate) matchBounds(typ Type, values []Value, min, max Value) (maybe, must bool) {
+	switch p.op {
+	case opEq, opIn:
+		for _, v := range values {
+			if typ.Compare(min, v) <= 0 && typ.Compare(max, v) >= 0 {
+				maybe = true
+				must = must || (typ.Compare(min, v) == 0 && typ.Compare(max, v) == 0)
+			}
+		}
+		return maybe, must
+	case opLt:
+		return typ.Compare(min, values[0]) < 0, typ.Compare(max, values[0]) < 0
+	case opLe:
+		return typ.Compare(min, values[0]) <= 0, typ.Compare(max, values[0]) <= 0
+	case opGt:
+		return typ.Compare(max, values[0]) > 0, typ.Compare(min, values[0]) > 0
+	case opGe:
+		return typ.Compare(max, values[0]) >= 0, typ.Compare(min, values[0]) >= 0
+	case opBetween:
+		maybe = typ.Compare(max, values[0]) >= 0 && typ.Compare(min, values[1]) <= 0
+		must = typ.Compare(min, values[0]) >= 0 && typ.Compare(max, values[1]) <= 0
+		return maybe, must
+	default:
+		return false, false
+	}
+}
+
+func (p *columnPredicate) matchRowFunc(schema *Schema, columns []bool) func(Row) bool {
+	leaf, ok := schema.Lookup(p.path...)
+	if !ok {
+		match := p.op == opIsNull
+		return func(Row) bool { return match }
+	}
+	typ := leaf.Node.Type()
+	values, ok := p.columnValues(typ)
+	if !ok || p.matchesNothing(values) {
+		// Values which cannot be converted to the column type never compare
+		// equal to values of the column.
+		return func(Row) bool { return false }
+	}
+	columnIndex := leaf.ColumnIndex
+	columns[columnIndex] = true
+	return func(row Row) bool {
+		for _, v := range row {
+			if v.Column() == columnIndex && p.matchValue(typ, values, v) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// pageStatistics is the information about the values of a page (or column
+// chunk) that predicates are evaluated against.
+type pageStatistics struct {
+	nullPage       bool
+	nullCount      int64
+	nullCountKnown bool
+	minValue       Value
+	maxValue       Value
+	hasBounds      bool
+}
+
+func (p *columnPredicate) matchPage(typ Type, values []Value, leaf LeafColumn, page pageStatistics) (maybe, must bool) {
+	if p.op == opIsNull {
+		if leaf.MaxDefinitionLevel == 0 {
+			return false, false
+		}
+		maybe = page.nullPage || !page.nullCountKnown || page.nullCount > 0
+		must = page.nullPage && leaf.MaxRepetitionLevel == 0
+		return maybe, must
+	}
+	if page.nullPage {
+		return false, false
+	}
+	if !page.hasBounds {
+		return true, false
+	}
+	maybe, must = p.matchBounds(typ, values, page.minValue, page.maxValue)
+	// Rows with null values do not match comparisons, the predicate is only
+	// known to match all the rows if the page has no nulls. Repeated columns
+	// may also have rows with no values (e.g. empty lists).
+	must = must && leaf.MaxRepetitionLevel == 0 &&
+		(leaf.MaxDefinitionLevel == 0 || (page.nullCountKnown && page.nullCount == 0))
+	return maybe, must
+}
+
+func (p *columnPredicate) rowRanges(rowGroup RowGroup) (maybe, must rowRanges) {
+	numRows := rowGroup.NumRows()
+	all := allRowRanges(numRows)
+
+	leaf, ok := rowGroup.Schema().Lookup(p.path...)
+	if !ok {
+		// The column does not exist, all its values are null.
+		if p.op == opIsNull {
+			return all, all
+		}
+		return nil, nil
+	}
+
+	chunk := rowGroup.ColumnChunks()[leaf.ColumnIndex]
+	typ := leaf.Node.Type()
+	if !typesAreEqual(chunk.Type(), typ) {
+		// The values of the column chunk are converted when rows are read,
+		// its statistics cannot be compared with the predicate values.
+		return all, nil
+	}
+
+	values, ok := p.columnValues(typ)
+	if !ok {
+		return all, nil
+	}
+	if p.matchesNothing(values) {
+		return nil, nil
+	}
+	if p.op != opIsNull && hasUndefinedOrder(typ) {
+		// Without an ordering, only bloom filters and dictionaries can be
+		// used to prune values.
+		if p.mayMatchChunk(chunk, typ, values) {
+			return all, nil
+		}
+		return nil, nil
+	}
+
+	columnIndex, err1 := chunk.ColumnIndex()
+	offsetIndex, err2 := chunk.OffsetIndex()
+
+	switch {
+	case err1 == nil && err2 == nil && columnIndex.NumPages() == offsetIndex.NumPages():
+		nullCountKnown := columnIndexHasNullCounts(columnIndex)
+		numPages := columnIndex.NumPages()
+
+		for i := 0; i < numPages; i++ {
+			firstRow := offsetIndex.FirstRowIndex(i)
+			lastRow := numRows
+			if i+1 < numPages {
+				lastRow = offsetIndex.FirstRowIndex(i + 1)
+			}
+			nullPage := columnIndex.NullPage(i)
+			pageMaybe, pageMust := p.matchPage(typ, values, leaf, pageStatistics{
+				nullPage:       nullPage,
+				nullCount:      columnIndex.NullCount(i),
+				nullCountKnown: nullCountKnown,
+				minValue:       columnIndex.MinValue(i),
+				maxValue:       columnIndex.MaxValue(i),
+				hasBounds:      !nullPage && columnIndexHasBounds(columnIndex, typ.Kind(), i),
+			})
+			if pageMaybe {
+				maybe = maybe.append(firstRow, lastRow)
+			}
+			if pageMust {
+				must = must.append(firstRow, lastRow)
+			}
+		}
+
+	default:
+		stats, ok := chunkStatisticsOf(chunk)
+		if !ok {
+			maybe = all
+			break
+		}
+		chunkMaybe, chunkMust := p.matchPage(typ, values, leaf, stats)
+		if chunkMaybe {
+			maybe = all
+		}
+		if chunkMust {
+			must = all
+		}
+	}
+
+	if len(maybe) > 0 && p.op != opIsNull && !p.mayMatchChunk(chunk, typ, values) {
+		return nil, nil
+	}
+	return maybe, must
+}
+
+// mayMatchChunk uses the bloom filter and dictionary of the column chunk to
+// determine whether it may contain values matching the predicate.
+func (p *columnPredicate) mayMatchChunk(chunk ColumnChunk, typ Type, values []Value) bool {
+	if p.op == opEq || p.op == opIn {
+		if bloomFilter := chunk.BloomFilter(); bloomFilter != nil {
+			mayContain := false
+			for _, v := range values {
+				ok, err := bloomFilter.Check(v)
+				if err != nil || ok {
+					mayContain = true
+					break
+				}
+			}
+			if !mayContain {
+				return false
+			}
+		}
+	}
+
+	if fileChunk, ok := chunk.(*fileColumnChunk); ok {
+		dict, err := fileChunk.readDictionaryOfDataPages()
+		if err != nil || dict == nil {
+			return true
+		}
+		for i, n := 0, dict.Len(); i < n; i++ {
+			if p.matchValue(typ, values, dict.Index(int32(i))) {
+				return true
+			}
+		}
+		return false
+	}
+
+	return true
+}
+
+// readDictionaryOfDataPages returns the dictionary of the column chunk if all
+// its data pages are dictionary-encoded, or nil otherwise. When the method
+// returns a dictionary, it holds all the non-null values of the column chunk.
+func (c *fileColumnChunk) readDictionaryOfDataPages() (Dictionary, error) {
+	metadata := &c.chunk.MetaData
+	if metadata.DictionaryPageOffset == 0 || len(metadata.EncodingStats) == 0 {
+		return nil, nil
+	}
+	for _, stats := range metadata.EncodingStats {
+		switch stats.PageType {
+		case format.DataPage, format.DataPageV2:
+			if !isDictionaryFormat(stats.Encoding) {
+				return nil, nil
+			}
+		}
+	}
+	pages := new(filePages)
+	pages.init(c)
+	defer pages.Close()
+	if err := pages.readDictionary(); err != nil {
+		return nil, err
+	}
+	return pages.dictionary, nil
+}
+
+// chunkStatisticsOf returns the statistics recorded in the metadata of a
+// column chunk, if any.
+func chunkStatisticsOf(chunk ColumnChunk) (pageStatistics, bool) {
+	fileChunk, ok := chunk.(*fileColumnChunk)
+	if !ok {
+		return pageStatistics{}, false
+	}
+	metadata := &fileChunk.chunk.MetaData
+	stats := &metadata.Statistics
+	// The null count is optional in the statistics, the only case where we
+	// can rely on it is when it indicates that all values are null.
+	nullPage := metadata.NumValues > 0 && stats.NullCount == metadata.NumValues
+	kind := fileChunk.Type().Kind()
+	hasBounds := boundsAreKnown(kind, stats.MinValue, stats.MaxValue)
+	if !nullPage && !hasBounds {
+		return pageStatistics{}, false
+	}
+	return pageStatistics{
+		nullPage:  nullPage,
+		minValue:  kind.Value(stats.MinValue),
+		maxValue:  kind.Value(stats.MaxValue),
+		hasBounds: hasBounds,
+	}, true
+}
+
+// columnIndexHasBounds returns true if the bounds of page i were recorded in
+// the column index. Writers leave the bounds empty when they are not computed,
+// for example for columns configured with the SkipPageBounds option.
+func columnIndexHasBounds(index ColumnIndex, kind Kind, i int) bool {
+	switch c := index.(type) {
+	case fileColumnIndex:
+		columnIndex := c.columnIndex()
+		return boundsAreKnown(kind, columnIndex.MinValues[i], columnIndex.MaxValues[i])
+	case *formatColumnIndex:
+		return boundsAreKnown(kind, c.index.MinValues[i], c.index.MaxValues[i])
+	default:
+		return true
+	}
+}
+
+// boundsAreKnown returns true if the encoded min and max values represent
+// bounds of values of the given kind. Empty byte arrays are valid values, but
+// both bounds being empty is indistinguishable from missing bounds, in which
+// case they are considered unknown.
+func boundsAreKnown(kind Kind, minValue, maxValue []byte) bool {
+	switch kind {
+	case ByteArray:
+		return len(maxValue) > 0
+	default:
+		return len(minValue) > 0 && len(maxValue) > 0
+	}
+}
+
+// makeColumnIndexValue decodes a bound of a column index. Bounds which were
+// not recorded by the writer are empty, they are returned as null values.
+func makeColumnIndexValue(kind Kind, b []byte) Value {
+	switch kind {
+	case ByteArray, FixedLenByteArray:
+	default:
+		if len(b) == 0 {
+			return Value{}
+		}
+	}
+	return kind.Value(b)
+}
+
+// columnIndexHasNullCounts returns true if the null counts of the column index
+// were recorded by the writer. The field is optional in parquet files, column
+// indexes of files written by older writers may omit it.
+func columnIndexHasNullCounts(index ColumnIndex) bool {
+	switch i := index.(type) {
+	case fileColumnIndex:
+		return len(i.columnIndex().NullCounts) > 0
+	case *formatColumnIndex:
+		return len(i.index.NullCounts) > 0
+	default:
+		return true
+	}
+}
+
+// primitiveTypeOf returns the physical type of v, which is used as source type
+// when converting the values of predicates to the types of columns.
+func primitiveTypeOf(v Value) Type {
+	switch v.Kind() {
+	case Boolean:
+		return BooleanType
+	case Int32:
+		return Int32Type
+	case Int64:
+		return Int64Type
+	case Int96:
+		return Int96Type
+	case Float:
+		return FloatType
+	case Double:
+		return DoubleType
+	case FixedLenByteArray:
+		return FixedLenByteArrayType(len(v.byteArray()))
+	default:
+		return ByteArrayType
+	}
+}
+
+// rowRange is a range of rows [start, end) of a row group.
+type rowRange struct{ start, end int64 }
+
+// rowRanges is a sorted list of non-overlapping and non-adjacent row ranges.
+type rowRanges []rowRange
+
+func allRowRanges(numRows int64) rowRanges {
+	if numRows <= 0 {
+		return nil
+	}
+	return rowRanges{{0, numRows}}
+}
+
+func (r rowRanges) numRows() (numRows int64) {
+	for _, rr := range r {
+		numRows += rr.end - rr.start
+	}
+	return numRows
+}
+
+// append adds the range [start, end) to r, which must start at or after the
+// end of the last range of r.
+func (r rowRanges) append(start, end int64) rowRanges {
+	if start >= end {
+		return r
+	}
+	if n := len(r); n > 0 && r[n-1].end >= start {
+		r[n-1].end = max(r[n-1].end, end)
+		return r
+	}
+	return append(r, rowRange{start, end})
+}
+
+func (r rowRanges) intersect(s rowRanges) (ranges rowRanges) {
+	for i, j := 0, 0; i < len(r) && j < len(s); {
+		ranges = ranges.append(max(r[i].start, s[j].start), min(r[i].end, s[j].end))
+		if r[i].end < s[j].end {
+			i++
+		} else {
+			j++
+		}
+	}
+	return ranges
+}
+
+func (r rowRanges) union(s rowRanges) (ranges rowRanges) {
+	for i, j := 0, 0; i < len(r) || j < len(s); {
+		var next rowRange
+		if j == len(s) || (i < len(r) && r[i].start < s[j].start) {
+			next, i = r[i], i+1
+		} else {
+			next, j = s[j], j+1
+		}
+		if n := len(ranges); n > 0 && ranges[n-1].end >= next.start {
+			ranges[n-1].end = max(ranges[n-1].end, next.end)
+		} else {
+			ranges = append(ranges, next)
+		}
+	}
+	return ranges
+}
+
+func (r rowRanges) complement(numRows int64) (ranges rowRanges) {
+	start := int64(0)
+	for _, rr := range r {
+		ranges = ranges.append(start, rr.start)
+		start = rr.end
+	}
+	return ranges.append(start, numRows)
+}
+
+// FilterStats carries statistics about the rows pruned by the predicate of a
+// reader configured with the Filter option.
+type FilterStats struct {
+	// Number of row groups that the predicate was evaluated on.
+	RowGroups int
+	// Number of row groups that were skipped because none of their rows could
+	// match the predicate.
+	SkippedRowGroups int
+	// Total number of rows in the row groups.
+	Rows int64
+	// Number of rows that were skipped, including the rows of skipped row
+	// groups.
+	SkippedRows int64
+}
+
+// filterRowGroups converts the row groups to schema and evaluates the
+// predicate on each of them, if it is not nil. The returned row group only
+// exposes the rows which match the predicate and, if the selection is not nil,
+// are within the selection.
+func filterRowGroups(rowGroups []RowGroup, schema *Schema, predicate Predicate, selection RowSelection) (RowGroup, FilterStats) {
+	var stats FilterStats
+	var filtered []RowGroup
+	var offset int64
+
+	for _, source := range rowGroups {
+		// The predicate and the row selection are evaluated on the source row
+		// group so they can depend on columns which are not part of the schema
+		// that the rows are converted to.
+		rowGroup := convertRowGroupTo(source, schema)
+		numRows := source.NumRows()
+		maybe, must := allRowRanges(numRows), allRowRanges(numRows)
+		if predicate != nil {
+			maybe, must = predicate.rowRanges(source)
+		}
+		if selection != nil {
+			selected := selection.rowRanges(offset, numRows)
+			maybe, must = maybe.intersect(selected), must.intersect(selected)
+		}
+		offset += numRows
+		numSelectedRows := maybe.numRows()
+
+		stats.RowGroups++
+		stats.Rows += numRows
+		stats.SkippedRows += numRows - numSelectedRows
+
+		if numSelectedRows == 0 {
+			stats.SkippedRowGroups++
+			continue
+		}
+
+		// When all the selected rows are known to match the predicate, there
+		// is no need to evaluate it on each row.
+		exact := must.intersect(maybe).numRows() == numSelectedRows
+		if exact && numSelectedRows == numRows {
+			filtered = append(filtered, rowGroup)
+			continue
+		}
+
+		f := &filteredRowGroup{RowGroup: rowGroup, source: source, ranges: maybe}
+		if c, ok := rowGroup.(*convertedRowGroup); ok {
+			f.conv = c.conv
+		}
+		if !exact {
+			f.columns = make([]bool, len(source.ColumnChunks()))
+			f.match = predicate.matchRowFunc(source.Schema(), f.columns)
+		}
+		filtered = append(filtered, f)
+	}
+
+	switch len(filtered) {
+	case 0:
+		return newEmptyRowGroup(schema), stats
+	case 1:
+		return filtered[0], stats
+	default:
+		return newConcatRowGroup(schema, filtered), stats
+	}
+}
+
+// filteredRowGroup is a RowGroup exposing only the rows of the row group that
+// it wraps which are within a set of ranges, and match a predicate if match is
+// not nil. The number of rows reported by the row group is an upper bound of
+// the number of rows it contains. The column chunks are those of the
+// underlying row group.
+//
+// The ranges and the predicate apply to the source row group, which the rows
+// are read from before being converted when conv is not nil.
+type filteredRowGroup struct {
+	RowGroup
+	source  RowGroup
+	conv    Conversion
+	ranges  rowRanges
+	match   func(Row) bool
+	columns []bool // columns of the source that the predicate depends on
+}
+
+func (r *filteredRowGroup) NumRows() int64 { return r.ranges.numRows() }
+
+func (r *filteredRowGroup) Rows() Rows {
+	rows := r.source.Rows()
+	if rowGroupRows, ok := rows.(*rowGroupRows); ok {
+		// Reading directly from the column chunks of the row group allows
+		// decoding the values of columns which are not read by the predicate
+		// only for rows that matched. Columns of the source which are neither
+		// converted nor read by the predicate are masked so their pages are
+		// never read.
+		if r.conv != nil {
+			rowGroupRows.rowGroup = maskMissingRowGroupColumns(r.source, len(r.ColumnChunks()), r.conv, r.columns)
+		}
+		rowGroupRows.filter = newRowFilter(rowGroupRows.rowGroup, r.ranges, r.match, r.columns)
+	} else {
+		rows = &filteredRows{rowGroup: r, rows: rows}
+	}
+	if r.conv != nil {
+		rows = &convertedRows{Closer: rows, rows: rows, conv: r.conv}
+	}
+	return rows
+}
+
+// filteredRows is the implementation of Rows for filtered row groups which do
+// not read rows directly from their column chunks (e.g. merged row groups).
+type filteredRows struct {
+	rowGroup *filteredRowGroup
+	rows     Rows
+	index    int   // index of the current range
+	rowIndex int64 // index of the next row in the underlying row group
+	seek     bool
+}
+
+func (r *filteredRows) ReadRows(rows []Row) (int, error) {
+	for {
+		n, err := r.readRows(rows)
+		if match := r.rowGroup.match; match != nil {
+			i := 0
+			for j, row := range rows[:n] {
+				if match(row) {
+					rows[i], rows[j] = rows[j], rows[i]
+					i++
+				}
+			}
+			n = i
+		}
+		if n > 0 || err != nil {
+			return n, err
+		}
+	}
+}
+
+func (r *filteredRows) readRows(rows []Row) (int, error) {
+	if len(rows) == 0 {
+		return 0, nil
+	}
+	ranges := r.rowGroup.ranges
+
+	for r.index < len(ranges) {
+		rr := ranges[r.index]
+		if r.rowIndex < rr.start {
+			r.rowIndex, r.seek = rr.start, true
+		}
+		if r.rowIndex >= rr.end {
+			r.index++
+			continue
+		}
+		if r.seek {
+			if err := r.rows.SeekToRow(r.rowIndex); err != nil {
+				return 0, err
+			}
+			r.seek = false
+		}
+
+		limit := min(int64(len(rows)), rr.end-r.rowIndex)
+		n, err := r.rows.ReadRows(rows[:limit])
+		r.rowIndex += int64(n)
+		if err == io.EOF {
+			if r.rowIndex < rr.end {
+				return n, io.ErrUnexpectedEOF
+			}
+			err = nil
+		}
+		if n > 0 || err != nil {
+			return n, err
+		}
+	}
+	return 0, io.EOF
+}
+
+func (r *filteredRows) SeekToRow(rowIndex int64) error {
+	if r.rowGroup.match != nil {
+		// The row index is relative to the rows matching the predicate, which
+		// are only known after evaluating it on the rows.
+		r.index, r.rowIndex, r.seek = 0, 0, true
+		_, err := skipRows(r, rowIndex)
+		return err
+	}
+
+	ranges := r.rowGroup.ranges
+	for r.index = 0; r.index < len(ranges); r.index++ {
+		rr := ranges[r.index]
+		numRows := rr.end - rr.start
+		if rowIndex < numRows {
+			r.rowIndex, r.seek = rr.start+rowIndex, true
+			return nil
+		}
+		rowIndex -= numRows
+	}
+	return nil
+}
+
+func (r *filteredRows) Schema() *Schema { return r.rows.Schema() }
+
+func (r *filteredRows) Close() error {
+	r.index = len(r.rowGroup.ranges)
+	return r.rows.Close()
+}
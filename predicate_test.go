@@ -0,0 +1,449 @@
+package parquet_test
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"reflect"
+	"testing"
+	"testing/fstest"
+
+	"github.com/parquet-go/parquet-go"
+)
+
+type predicateRow struct {
+	ID       int64  `parquet:"id"`
+	Value    *int32 `parquet:"value,optional"`
+	Name     string `parquet:"name"`
+	Category string `parquet:"category,dict"`
+}
+
+func predicateRows() []predicateRow {
+	rows := make([]predicateRow, 1000)
+	for i := range rows {
+		rows[i] = predicateRow{
+			ID:       int64(i),
+			Name:     fmt.Sprintf("name-%d", (i*7919)%1000),
+			Category: []string{"a", "c", "e"}[i%3],
+		}
+		if i < 900 {
+			value := int32(i)
+			rows[i].Value = &value
+		}
+	}
+	return rows
+}
+
+// predicateFileOptions write rows in row groups of 250 rows, with pages of 50
+// rows.
+var predicateFileOptions = []parquet.WriterOption{
+	parquet.MaxRowsPerRowGroup(250),
+	parquet.DataPageRowCountLimit(50),
+	parquet.BloomFilters(parquet.SplitBlockFilter(10, "name")),
+}
+
+func TestPredicatePruning(t *testing.T) {
+	rows := predicateRows()
+	file, _ := writeTestFile(t, rows, predicateFileOptions...)
+
+	tests := []struct {
+		predicate        parquet.Predicate
+		match            func(predicateRow) bool
+		skippedRows      int64
+		skippedRowGroups int
+	}{
+		{
+			predicate:        parquet.Eq([]string{"id"}, parquet.ValueOf(123)),
+			match:            func(r predicateRow) bool { return r.ID == 123 },
+			skippedRows:      950,
+			skippedRowGroups: 3,
+		},
+		{
+			predicate:        parquet.Eq([]string{"id"}, parquet.ValueOf(int32(123))),
+			match:            func(r predicateRow) bool { return r.ID == 123 },
+			skippedRows:      950,
+			skippedRowGroups: 3,
+		},
+		{
+			predicate:        parquet.In([]string{"id"}, parquet.ValueOf(10), parquet.ValueOf(990)),
+			match:            func(r predicateRow) bool { return r.ID == 10 || r.ID == 990 },
+			skippedRows:      900,
+			skippedRowGroups: 2,
+		},
+		{
+			predicate:        parquet.Lt([]string{"id"}, parquet.ValueOf(100)),
+			match:            func(r predicateRow) bool { return r.ID < 100 },
+			skippedRows:      900,
+			skippedRowGroups: 3,
+		},
+		{
+			predicate:        parquet.Ge([]string{"id"}, parquet.ValueOf(900)),
+			match:            func(r predicateRow) bool { return r.ID >= 900 },
+			skippedRows:      900,
+			skippedRowGroups: 3,
+		},
+		{
+			predicate:        parquet.Between([]string{"id"}, parquet.ValueOf(240), parquet.ValueOf(260)),
+			match:            func(r predicateRow) bool { return r.ID >= 240 && r.ID <= 260 },
+			skippedRows:      900,
+			skippedRowGroups: 2,
+		},
+		{
+			predicate:        parquet.Gt([]string{"value"}, parquet.ValueOf(850)),
+			match:            func(r predicateRow) bool { return r.Value != nil && *r.Value > 850 },
+			skippedRows:      950,
+			skippedRowGroups: 3,
+		},
+		{
+			predicate:        parquet.IsNull([]string{"value"}),
+			match:            func(r predicateRow) bool { return r.Value == nil },
+			skippedRows:      900,
+			skippedRowGroups: 3,
+		},
+		{
+			predicate:   parquet.Not(parquet.IsNull([]string{"value"})),
+			match:       func(r predicateRow) bool { return r.Value != nil },
+			skippedRows: 100,
+		},
+		{
+			predicate:        parquet.Not(parquet.Lt([]string{"id"}, parquet.ValueOf(500))),
+			match:            func(r predicateRow) bool { return r.ID >= 500 },
+			skippedRows:      500,
+			skippedRowGroups: 2,
+		},
+		{
+			predicate: parquet.And(
+				parquet.Ge([]string{"id"}, parquet.ValueOf(100)),
+				parquet.Lt([]string{"id"}, parquet.ValueOf(200)),
+			),
+			match:            func(r predicateRow) bool { return r.ID >= 100 && r.ID < 200 },
+			skippedRows:      900,
+			skippedRowGroups: 3,
+		},
+		{
+			predicate: parquet.Or(
+				parquet.Eq([]string{"id"}, parquet.ValueOf(0)),
+				parquet.Eq([]string{"id"}, parquet.ValueOf(999)),
+			),
+			match:            func(r predicateRow) bool { return r.ID == 0 || r.ID == 999 },
+			skippedRows:      900,
+			skippedRowGroups: 2,
+		},
+		{
+			// The value is within the min/max bounds of all pages, only the
+			// bloom filters can be used to prune the row groups.
+			predicate:        parquet.Eq([]string{"name"}, parquet.ValueOf("name-55x")),
+			match:            func(r predicateRow) bool { return false },
+			skippedRows:      1000,
+			skippedRowGroups: 4,
+		},
+		{
+			// The value is within the min/max bounds of all pages, only the
+			// dictionaries can be used to prune the row groups.
+			predicate:        parquet.Eq([]string{"category"}, parquet.ValueOf("b")),
+			match:            func(r predicateRow) bool { return false },
+			skippedRows:      1000,
+			skippedRowGroups: 4,
+		},
+		{
+			predicate:        parquet.Eq([]string{"missing"}, parquet.ValueOf(1)),
+			match:            func(r predicateRow) bool { return false },
+			skippedRows:      1000,
+			skippedRowGroups: 4,
+		},
+		{
+			predicate: parquet.IsNull([]string{"missing"}),
+			match:     func(r predicateRow) bool { return true },
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.predicate.String(), func(t *testing.T) {
+			reader := parquet.NewGenericReader[predicateRow](file, parquet.Filter(test.predicate))
+			defer reader.Close()
+
+			stats := reader.FilterStats()
+			if stats.RowGroups != 4 || stats.Rows != 1000 {
+				t.Errorf("wrong totals: %+v", stats)
+			}
+			if stats.SkippedRows != test.skippedRows {
+				t.Errorf("wrong number of skipped rows: want=%d got=%d", test.skippedRows, stats.SkippedRows)
+			}
+			if stats.SkippedRowGroups != test.skippedRowGroups {
+				t.Errorf("wrong number of skipped row groups: want=%d got=%d", test.skippedRowGroups, stats.SkippedRowGroups)
+			}
+			// The number of rows reported by the reader is an upper bound since
+			// the predicate is evaluated on each row when they are read.
+			if numRows := reader.NumRows(); numRows != stats.Rows-stats.SkippedRows {
+				t.Errorf("wrong number of rows: want=%d got=%d", stats.Rows-stats.SkippedRows, numRows)
+			}
+
+			read := make([]predicateRow, 1000)
+			n, err := reader.Read(read)
+			if err != nil && err != io.EOF {
+				t.Fatal(err)
+			}
+			var want []predicateRow
+			for _, row := range rows {
+				if test.match(row) {
+					want = append(want, row)
+				}
+			}
+			if !reflect.DeepEqual(read[:n], want) && (n != 0 || len(want) != 0) {
+				t.Errorf("wrong rows: want=%d rows got=%d rows", len(want), n)
+			}
+		})
+	}
+}
+
+func TestPredicateSeekToRow(t *testing.T) {
+	file, _ := writeTestFile(t, predicateRows(), predicateFileOptions...)
+
+	reader := parquet.NewReader(file, parquet.Filter(parquet.Or(
+		parquet.Lt([]string{"id"}, parquet.ValueOf(50)),
+		parquet.Ge([]string{"id"}, parquet.ValueOf(950)),
+	)))
+	defer reader.Close()
+
+	if stats := reader.FilterStats(); stats.SkippedRows != 900 {
+		t.Errorf("wrong number of skipped rows: want=900 got=%d", stats.SkippedRows)
+	}
+	if err := reader.SeekToRow(49); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []int64{49, 950, 951} {
+		row := predicateRow{}
+		if err := reader.Read(&row); err != nil {
+			t.Fatal(err)
+		}
+		if row.ID != want {
+			t.Errorf("wrong row: want=%d got=%d", want, row.ID)
+		}
+	}
+}
+
+func TestPredicateColumnNotInSchema(t *testing.T) {
+	type nameRow struct {
+		Name string `parquet:"name"`
+	}
+	rows := predicateRows()
+	file, _ := writeTestFile(t, rows, predicateFileOptions...)
+
+	// The predicate is evaluated on the columns of the file, it may depend on
+	// columns which are not part of the schema of the reader.
+	reader := parquet.NewGenericReader[nameRow](file, parquet.Filter(parquet.Eq([]string{"id"}, parquet.ValueOf(int64(50)))))
+	defer reader.Close()
+
+	if stats := reader.FilterStats(); stats.SkippedRows != 950 {
+		t.Errorf("wrong number of skipped rows: want=950 got=%d", stats.SkippedRows)
+	}
+	got := make([]nameRow, 2)
+	n, err := reader.Read(got)
+	if err != nil && err != io.EOF {
+		t.Fatal(err)
+	}
+	if n != 1 || got[0].Name != rows[50].Name {
+		t.Errorf("wrong rows: %+v", got[:n])
+	}
+}
+
+func TestPredicateSkipPageBounds(t *testing.T) {
+	type row struct {
+		ID   int64  `parquet:"id"`
+		Name string `parquet:"name"`
+	}
+	rows := make([]row, 100)
+	for i := range rows {
+		rows[i] = row{ID: int64(10 + i), Name: fmt.Sprintf("name-%d", 10+i)}
+	}
+	file, _ := writeTestFile(t, rows,
+		parquet.DataPageRowCountLimit(10),
+		parquet.SkipPageBounds("id"),
+		parquet.SkipPageBounds("name"),
+	)
+
+	// Columns written without page bounds have no column index, their pages
+	// cannot be pruned.
+	for _, chunk := range file.RowGroups()[0].ColumnChunks() {
+		if _, err := chunk.ColumnIndex(); err != parquet.ErrMissingColumnIndex {
+			t.Errorf("expected ErrMissingColumnIndex but got %v", err)
+		}
+	}
+	for _, predicate := range []parquet.Predicate{
+		parquet.Eq([]string{"id"}, parquet.ValueOf(int64(50))),
+		parquet.Eq([]string{"name"}, parquet.ValueOf("name-50")),
+	} {
+		reader := parquet.NewGenericReader[row](file, parquet.Filter(predicate))
+		got := make([]row, 2)
+		n, err := reader.Read(got)
+		if err != nil && err != io.EOF {
+			t.Fatal(err)
+		}
+		if n != 1 || got[0] != rows[40] {
+			t.Errorf("%v: wrong rows: %+v", predicate, got[:n])
+		}
+		reader.Close()
+	}
+}
+
+func TestPredicateDatasetPartitions(t *testing.T) {
+	type row struct {
+		ID int64 `parquet:"id"`
+	}
+	type datasetRow struct {
+		ID   int64  `parquet:"id"`
+		Date string `parquet:"date"`
+	}
+
+	fsys := fstest.MapFS{}
+	writeDatasetFile(t, fsys, "date=2024-01-01/part-00000.parquet", []row{{1}, {2}})
+	writeDatasetFile(t, fsys, "date=2024-01-02/part-00000.parquet", []row{{3}, {4}})
+
+	dataset, err := parquet.OpenDataset(fsys, ".")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dataset.Close()
+
+	reader, err := parquet.NewGenericDatasetReader[datasetRow](dataset,
+		parquet.Filter(parquet.Eq([]string{"date"}, parquet.ValueOf("2024-01-02"))),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer reader.Close()
+
+	if stats := reader.FilterStats(); stats.SkippedRowGroups != 1 || stats.SkippedRows != 2 {
+		t.Errorf("wrong filter stats: %+v", stats)
+	}
+	rows := make([]datasetRow, 10)
+	n, err := reader.Read(rows)
+	if err != io.EOF {
+		t.Fatalf("expected io.EOF but got %v", err)
+	}
+	if n != 2 || rows[0].ID != 3 || rows[1].ID != 4 || rows[0].Date != "2024-01-02" {
+		t.Errorf("wrong rows: %+v", rows[:n])
+	}
+}
+
+func TestPredicateLateMaterialization(t *testing.T) {
+	type row struct {
+		Key     int64  `parquet:"key"`
+		Payload []byte `parquet:"payload"`
+	}
+	type keyRow struct {
+		Key int64 `parquet:"key"`
+	}
+
+	rows := make([]row, 2000)
+	for i := range rows {
+		key := int64(i*7919) % int64(len(rows))
+		rows[i] = row{Key: key, Payload: bytes.Repeat([]byte{byte(key)}, 1000)}
+	}
+	file, input := writeTestFile(t, rows,
+		parquet.Compression(&parquet.Uncompressed),
+		parquet.DataPageRowCountLimit(100),
+	)
+
+	readAll := func(r interface{ Read([]row) (int, error) }) []row {
+		t.Helper()
+		values := make([]row, len(rows))
+		n, err := r.Read(values)
+		if err != nil && err != io.EOF {
+			t.Fatal(err)
+		}
+		return values[:n]
+	}
+
+	input.bytesRead = 0
+	readAll(parquet.NewGenericReader[row](file))
+	fullReadBytes := input.bytesRead
+
+	input.bytesRead = 0
+	read := readAll(parquet.NewGenericReader[row](file,
+		parquet.Filter(parquet.Eq([]string{"key"}, parquet.ValueOf(1234))),
+	))
+	if len(read) != 1 || read[0].Key != 1234 || !bytes.Equal(read[0].Payload, bytes.Repeat([]byte{1234 % 256}, 1000)) {
+		t.Fatalf("wrong rows: want=1 row with key 1234 got=%d rows", len(read))
+	}
+	// The values of the payload column must only be read for the page which
+	// contains the matching row.
+	if input.bytesRead > fullReadBytes/10 {
+		t.Errorf("too many bytes read: %d/%d", input.bytesRead, fullReadBytes)
+	}
+
+	var want []row
+	for _, r := range rows {
+		if r.Key < 100 {
+			want = append(want, r)
+		}
+	}
+
+	reader := parquet.NewGenericReader[row](file, parquet.Filter(parquet.Lt([]string{"key"}, parquet.ValueOf(100))))
+	if err := reader.SeekToRow(50); err != nil {
+		t.Fatal(err)
+	}
+	if read := readAll(reader); !reflect.DeepEqual(read, want[50:]) {
+		t.Errorf("wrong rows after seeking: want=%d rows got=%d rows", len(want[50:]), len(read))
+	}
+
+	// The values of the columns which are not read by the predicate are only
+	// decoded for the rows that matched when the rows are converted to a
+	// subset of the columns, or when the columns are projected.
+	for _, test := range []struct {
+		scenario string
+		read     func() int
+	}{
+		{
+			scenario: "subset of the columns",
+			read: func() int {
+				type payloadRow struct {
+					Payload []byte `parquet:"payload"`
+				}
+				values := make([]payloadRow, len(rows))
+				n, err := parquet.NewGenericReader[payloadRow](file,
+					parquet.Filter(parquet.Eq([]string{"key"}, parquet.ValueOf(1234))),
+				).Read(values)
+				if err != nil && err != io.EOF {
+					t.Fatal(err)
+				}
+				return n
+			},
+		},
+		{
+			scenario: "projection",
+			read: func() int {
+				return len(readAll(parquet.NewGenericReader[row](file,
+					parquet.Projection([]string{"payload"}),
+					parquet.Filter(parquet.Eq([]string{"key"}, parquet.ValueOf(1234))),
+				)))
+			},
+		},
+	} {
+		input.bytesRead = 0
+		if n := test.read(); n != 1 {
+			t.Errorf("%s: wrong number of rows: want=1 got=%d", test.scenario, n)
+		}
+		if input.bytesRead > fullReadBytes/10 {
+			t.Errorf("%s: too many bytes read: %d/%d", test.scenario, input.bytesRead, fullReadBytes)
+		}
+	}
+
+	// Reading a subset of the columns converts the rows after evaluating the
+	// predicate.
+	keys := make([]keyRow, len(rows))
+	n, err := parquet.NewGenericReader[keyRow](file,
+		parquet.Filter(parquet.Lt([]string{"key"}, parquet.ValueOf(100))),
+	).Read(keys)
+	if err != nil && err != io.EOF {
+		t.Fatal(err)
+	}
+	if n != len(want) {
+		t.Fatalf("wrong number of rows: want=%d got=%d", len(want), n)
+	}
+	for i, k := range keys[:n] {
+		if k.Key != want[i].Key {
+			t.Errorf("wrong row at index %d: want=%d got=%d", i, want[i].Key, k.Key)
+		}
+	}
+}
@@ -8,6 +8,8 @@ import (
 	"strings"
 
 	"github.com/olekukonko/tablewriter"
+
+	"github.com/parquet-go/parquet-go/deprecated"
 )
 
 func PrintSchema(w io.Writer, name string, node Node) error {
@@ -138,6 +140,10 @@ func annotationOf(node Node) string {
 	if logicalType := node.Type().LogicalType(); logicalType != nil {
 		return logicalType.String()
 	}
+	if convertedType := node.Type().ConvertedType(); convertedType != nil && *convertedType == deprecated.Interval {
+		// INTERVAL is the only converted type without logical type equivalent.
+		return "INTERVAL"
+	}
 	return ""
 }
 
@@ -33,6 +33,20 @@ func TestPrintSchema(t *testing.T) {
 }`,
 		},
 
+		{
+			node: parquet.Group{"float16": parquet.Float16()},
+			print: `message Test {
+	required fixed_len_byte_array(2) float16 (FLOAT16);
+}`,
+		},
+
+		{
+			node: parquet.Group{"interval": parquet.Interval()},
+			print: `message Test {
+	required fixed_len_byte_array(12) interval (INTERVAL);
+}`,
+		},
+
 		{
 			node: parquet.Group{"enum": parquet.Enum()},
 			print: `message Test {
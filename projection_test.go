@@ -0,0 +1,184 @@
+package parquet_test
+
+import (
+	"bytes"
+	"io"
+	"reflect"
+	"testing"
+
+	"github.com/parquet-go/parquet-go"
+)
+
+type projectionMeta struct {
+	Source string `parquet:"source"`
+	Region string `parquet:"region"`
+}
+
+type projectionRow struct {
+	ID      int64          `parquet:"id"`
+	Meta    projectionMeta `parquet:"meta"`
+	Payload []byte         `parquet:"payload"`
+}
+
+func projectionRows() []projectionRow {
+	rows := make([]projectionRow, 1000)
+	for i := range rows {
+		rows[i] = projectionRow{
+			ID:      int64(i),
+			Meta:    projectionMeta{Source: "src", Region: []string{"eu", "us"}[i%2]},
+			Payload: bytes.Repeat([]byte{byte(i)}, 1000),
+		}
+	}
+	return rows
+}
+
+func TestProjection(t *testing.T) {
+	rows := projectionRows()
+	file, input := writeTestFile(t, rows,
+		parquet.Compression(&parquet.Uncompressed),
+		parquet.DataPageRowCountLimit(100),
+	)
+
+	input.bytesRead = 0
+	values := make([]projectionRow, len(rows))
+	if n, err := parquet.NewGenericReader[projectionRow](file).Read(values); n != len(rows) || (err != nil && err != io.EOF) {
+		t.Fatalf("reading all columns: n=%d err=%v", n, err)
+	}
+	fullReadBytes := input.bytesRead
+
+	tests := []struct {
+		scenario string
+		options  []parquet.ReaderOption
+		filter   func(projectionRow) bool
+		project  func(projectionRow) projectionRow
+	}{
+		{
+			scenario: "leaf column",
+			options:  []parquet.ReaderOption{parquet.Projection([]string{"id"})},
+			project:  func(r projectionRow) projectionRow { return projectionRow{ID: r.ID} },
+		},
+		{
+			scenario: "nested leaf column",
+			options:  []parquet.ReaderOption{parquet.Projection([]string{"id"}, []string{"meta", "region"})},
+			project: func(r projectionRow) projectionRow {
+				return projectionRow{ID: r.ID, Meta: projectionMeta{Region: r.Meta.Region}}
+			},
+		},
+		{
+			scenario: "group",
+			options:  []parquet.ReaderOption{parquet.Projection([]string{"meta"})},
+			project:  func(r projectionRow) projectionRow { return projectionRow{Meta: r.Meta} },
+		},
+		{
+			scenario: "filter on a column which is not projected",
+			options: []parquet.ReaderOption{
+				parquet.Projection([]string{"meta", "source"}),
+				parquet.Filter(parquet.Lt([]string{"id"}, parquet.ValueOf(10))),
+			},
+			filter: func(r projectionRow) bool { return r.ID < 10 },
+			project: func(r projectionRow) projectionRow {
+				return projectionRow{Meta: projectionMeta{Source: r.Meta.Source}}
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.scenario, func(t *testing.T) {
+			var want []projectionRow
+			for _, row := range rows {
+				if test.filter == nil || test.filter(row) {
+					want = append(want, test.project(row))
+				}
+			}
+
+			input.bytesRead = 0
+			reader := parquet.NewGenericReader[projectionRow](file, test.options...)
+			defer reader.Close()
+
+			values := make([]projectionRow, len(rows))
+			n, err := reader.Read(values)
+			if err != nil && err != io.EOF {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(values[:n], want) {
+				t.Errorf("wrong rows: want=%d rows got=%d rows", len(want), n)
+			}
+			if input.bytesRead >= fullReadBytes/10 {
+				t.Errorf("too many bytes read: %d/%d", input.bytesRead, fullReadBytes)
+			}
+		})
+	}
+}
+
+func TestProjectionReader(t *testing.T) {
+	rows := projectionRows()
+	file, input := writeTestFile(t, rows,
+		parquet.Compression(&parquet.Uncompressed),
+		parquet.DataPageRowCountLimit(100),
+	)
+	projection := parquet.Projection([]string{"meta", "region"})
+
+	t.Run("struct", func(t *testing.T) {
+		input.bytesRead = 0
+		reader := parquet.NewReader(file, projection)
+		defer reader.Close()
+
+		for i := range 3 {
+			row := projectionRow{ID: 42}
+			if err := reader.Read(&row); err != nil {
+				t.Fatal(err)
+			}
+			want := projectionRow{ID: 42, Meta: projectionMeta{Region: rows[i].Meta.Region}}
+			if !reflect.DeepEqual(row, want) {
+				t.Errorf("wrong row at index %d: want=%+v got=%+v", i, want, row)
+			}
+		}
+		if input.bytesRead >= 50e3 {
+			t.Errorf("too many bytes read: %d", input.bytesRead)
+		}
+	})
+
+	t.Run("map", func(t *testing.T) {
+		reader := parquet.NewReader(file, projection)
+		defer reader.Close()
+
+		row := map[string]any{}
+		if err := reader.Read(&row); err != nil {
+			t.Fatal(err)
+		}
+		want := map[string]any{"meta": map[string]any{"region": "eu"}}
+		if !reflect.DeepEqual(row, want) {
+			t.Errorf("wrong row: want=%v got=%v", want, row)
+		}
+	})
+
+	t.Run("row group", func(t *testing.T) {
+		reader := parquet.NewRowGroupReader(file.RowGroups()[0], projection)
+		defer reader.Close()
+
+		if columns := reader.Schema().Columns(); !reflect.DeepEqual(columns, [][]string{{"meta", "region"}}) {
+			t.Errorf("wrong columns: %q", columns)
+		}
+		buf := make([]parquet.Row, 2)
+		n, err := reader.ReadRows(buf)
+		if n != 2 || err != nil {
+			t.Fatalf("n=%d err=%v", n, err)
+		}
+		for i, row := range buf[:n] {
+			if len(row) != 1 || row[0].String() != rows[i].Meta.Region {
+				t.Errorf("wrong row at index %d: %v", i, row)
+			}
+		}
+	})
+}
+
+func TestProjectionUnknownColumn(t *testing.T) {
+	file, _ := writeTestFile(t, projectionRows()[:10])
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected a panic for a projected column missing from the schema")
+		}
+	}()
+	parquet.NewGenericReader[projectionRow](file, parquet.Projection([]string{"meta", "missing"}))
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io"
 	"reflect"
+	"slices"
 )
 
 // GenericReader is similar to a Reader but uses a type parameter to define the
@@ -44,9 +45,12 @@ func NewGenericReader[T any](input io.ReaderAt, options ...ReaderOption) *Generi
 		if t == nil {
 			c.Schema = rowGroup.Schema()
 		} else {
-			c.Schema = schemaOf(dereference(t))
+			c.Schema = schemaWithShreddedVariantsOf(schemaOf(dereference(t)), f.schema)
 		}
 	}
+	if len(c.Projection) > 0 {
+		c.Schema = mustProjectSchema(c.Schema, c.Projection)
+	}
 
 	r := &GenericReader[T]{
 		base: Reader{
@@ -57,10 +61,14 @@ func NewGenericReader[T any](input io.ReaderAt, options ...ReaderOption) *Generi
 		},
 	}
 
-	if !nodesAreEqual(c.Schema, f.schema) {
+	if c.Filter != nil || c.Selection != nil {
+		r.base.filterRowGroups(c, f.RowGroups())
+	} else if !nodesAreEqual(c.Schema, f.schema) {
 		r.base.file.rowGroup = convertRowGroupTo(r.base.file.rowGroup, c.Schema)
 	}
 
+	r.base.readConcurrency, r.base.readAheadBytes = c.ReadConcurrency, c.ReadAheadBytes
+	r.base.file.rowGroup = r.base.parallelize(r.base.file.rowGroup)
 	r.base.read.init(r.base.file.schema, r.base.file.rowGroup)
 	r.read = readFuncOf[T](t, r.base.file.schema)
 	return r
@@ -77,9 +85,12 @@ func NewGenericRowGroupReader[T any](rowGroup RowGroup, options ...ReaderOption)
 		if t == nil {
 			c.Schema = rowGroup.Schema()
 		} else {
-			c.Schema = schemaOf(dereference(t))
+			c.Schema = schemaWithShreddedVariantsOf(schemaOf(dereference(t)), rowGroup.Schema())
 		}
 	}
+	if len(c.Projection) > 0 {
+		c.Schema = mustProjectSchema(c.Schema, c.Projection)
+	}
 
 	r := &GenericReader[T]{
 		base: Reader{
@@ -90,10 +101,14 @@ func NewGenericRowGroupReader[T any](rowGroup RowGroup, options ...ReaderOption)
 		},
 	}
 
-	if !nodesAreEqual(c.Schema, rowGroup.Schema()) {
+	if c.Filter != nil || c.Selection != nil {
+		r.base.filterRowGroups(c, rowGroupsOf(rowGroup))
+	} else if !nodesAreEqual(c.Schema, rowGroup.Schema()) {
 		r.base.file.rowGroup = convertRowGroupTo(r.base.file.rowGroup, c.Schema)
 	}
 
+	r.base.readConcurrency, r.base.readAheadBytes = c.ReadConcurrency, c.ReadAheadBytes
+	r.base.file.rowGroup = r.base.parallelize(r.base.file.rowGroup)
 	r.base.read.init(r.base.file.schema, r.base.file.rowGroup)
 	r.read = readFuncOf[T](t, r.base.file.schema)
 	return r
@@ -134,6 +149,12 @@ func (r *GenericReader[T]) Close() error {
 	return r.base.Close()
 }
 
+// FilterStats returns statistics about the rows pruned by the predicate that
+// the reader was configured with. See Filter for details.
+func (r *GenericReader[T]) FilterStats() FilterStats {
+	return r.base.FilterStats()
+}
+
 // readRows reads the next rows from the reader into the given rows slice up to len(rows).
 //
 // The returned values are safe to reuse across readRows calls and do not share
@@ -237,6 +258,16 @@ type Reader struct {
 	read     reader
 	rowIndex int64
 	rowbuf   []Row
+
+	source      RowGroup
+	projection  [][]string
+	filter      Predicate
+	selection   RowSelection
+	rowGroups   []RowGroup
+	filterStats FilterStats
+
+	readConcurrency int
+	readAheadBytes  int64
 }
 
 // NewReader constructs a parquet reader reading rows from the given
@@ -277,17 +308,104 @@ func NewReader(input io.ReaderAt, options ...ReaderOption) *Reader {
 			schema:   f.schema,
 			rowGroup: fileRowGroupOf(f),
 		},
+		source:     fileRowGroupOf(f),
+		projection: c.Projection,
 	}
 
 	if c.Schema != nil {
 		r.file.schema = c.Schema
-		r.file.rowGroup = convertRowGroupTo(r.file.rowGroup, c.Schema)
+	}
+	if len(c.Projection) > 0 {
+		r.file.schema = mustProjectSchema(r.file.schema, c.Projection)
 	}
 
+	if c.Filter != nil || c.Selection != nil {
+		r.filterRowGroups(c, f.RowGroups())
+	} else {
+		r.file.rowGroup = convertRowGroupTo(r.file.rowGroup, r.file.schema)
+	}
+
+	r.readConcurrency, r.readAheadBytes = c.ReadConcurrency, c.ReadAheadBytes
+	r.file.rowGroup = r.parallelize(r.file.rowGroup)
 	r.read.init(r.file.schema, r.file.rowGroup)
 	return r
 }
 
+// projectSchema returns a schema with only the columns of schema which have
+// one of the given paths as prefix.
+func projectSchema(schema *Schema, paths [][]string) (*Schema, error) {
+	columns := schema.Columns()
+	projection := make([]columnPath, 0, len(paths))
+	for _, path := range paths {
+		if !slices.ContainsFunc(columns, func(column []string) bool {
+			return columnPath(path).isPrefixOf(column)
+		}) {
+			return nil, fmt.Errorf("projected column %q does not exist in the schema", columnPath(path))
+		}
+		projection = append(projection, path)
+	}
+
+	node := projectColumns(schema, nil, projection)
+	if node == nil {
+		node = Group{}
+	}
+	return NewSchema(schema.Name(), node), nil
+}
+
+func mustProjectSchema(schema *Schema, paths [][]string) *Schema {
+	projected, err := projectSchema(schema, paths)
+	if err != nil {
+		panic(err)
+	}
+	return projected
+}
+
+// filterColumnPaths returns the paths of the columns of schema that the filter
+// depends on.
+func filterColumnPaths(schema *Schema, filter Predicate) [][]string {
+	if filter == nil {
+		return nil
+	}
+	columns := schema.Columns()
+	filterColumns := make([]bool, len(columns))
+	filter.matchRowFunc(schema, filterColumns)
+	var paths [][]string
+	for i, ok := range filterColumns {
+		if ok {
+			paths = append(paths, columns[i])
+		}
+	}
+	return paths
+}
+
+// filterRowGroups configures r to only read the rows of the row groups which
+// match the filter and are within the row selection of c. The row groups are
+// converted to the schema of r.
+func (r *Reader) filterRowGroups(c *ReaderConfig, rowGroups []RowGroup) {
+	r.filter = c.Filter
+	r.selection = c.Selection
+	r.rowGroups = rowGroups
+	r.file.rowGroup, r.filterStats = filterRowGroups(rowGroups, r.file.schema, r.filter, r.selection)
+}
+
+// parallelize returns a view of rowGroup which decodes its row groups
+// concurrently when r is configured with a read concurrency greater than one.
+func (r *Reader) parallelize(rowGroup RowGroup) RowGroup {
+	return parallelRowGroupOf(rowGroup, r.readConcurrency, r.readAheadBytes)
+}
+
+// rowGroupsOf returns the list of row groups that rowGroup is made of.
+func rowGroupsOf(rowGroup RowGroup) []RowGroup {
+	switch c := rowGroup.(type) {
+	case *concatRowGroup:
+		return c.rowGroups
+	case *Dataset:
+		return c.rowGroups
+	default:
+		return []RowGroup{rowGroup}
+	}
+}
+
 func openFile(input io.ReaderAt) (*File, error) {
 	f, _ := input.(*File)
 	if f != nil {
@@ -321,17 +439,32 @@ func NewRowGroupReader(rowGroup RowGroup, options ...ReaderOption) *Reader {
 		panic(err)
 	}
 
+	schema := rowGroup.Schema()
 	if c.Schema != nil {
-		rowGroup = convertRowGroupTo(rowGroup, c.Schema)
+		schema = c.Schema
+	}
+
+	if len(c.Projection) > 0 {
+		schema = mustProjectSchema(schema, c.Projection)
 	}
 
 	r := &Reader{
 		file: reader{
-			schema:   rowGroup.Schema(),
+			schema:   schema,
 			rowGroup: rowGroup,
 		},
+		source:     rowGroup,
+		projection: c.Projection,
+	}
+
+	if c.Filter != nil || c.Selection != nil {
+		r.filterRowGroups(c, rowGroupsOf(rowGroup))
+	} else {
+		r.file.rowGroup = convertRowGroupTo(rowGroup, schema)
 	}
 
+	r.readConcurrency, r.readAheadBytes = c.ReadConcurrency, c.ReadAheadBytes
+	r.file.rowGroup = r.parallelize(r.file.rowGroup)
 	r.read.init(r.file.schema, r.file.rowGroup)
 	return r
 }
@@ -414,16 +547,27 @@ func (r *Reader) Read(row interface{}) error {
 }
 
 func (r *Reader) updateReadSchema(rowType reflect.Type) error {
-	schema := schemaOf(rowType)
+	schema := schemaWithShreddedVariantsOf(schemaOf(rowType), r.file.schema)
+	if len(r.projection) > 0 {
+		var err error
+		if schema, err = projectSchema(schema, r.projection); err != nil {
+			return err
+		}
+	}
 
-	if nodesAreEqual(schema, r.file.schema) {
+	if r.filter != nil || r.selection != nil {
+		rowGroup, _ := filterRowGroups(r.rowGroups, schema, r.filter, r.selection)
+		r.read.init(schema, r.parallelize(rowGroup))
+	} else if nodesAreEqual(schema, r.file.schema) {
 		r.read.init(schema, r.file.rowGroup)
 	} else {
-		conv, err := Convert(schema, r.file.schema)
+		// The conversion is applied to the source row group since converted
+		// row groups cannot be converted again.
+		conv, err := Convert(schema, r.source.Schema())
 		if err != nil {
 			return err
 		}
-		r.read.init(schema, ConvertRowGroup(r.file.rowGroup, conv))
+		r.read.init(schema, r.parallelize(ConvertRowGroup(r.source, conv)))
 	}
 
 	r.seen = rowType
@@ -460,6 +604,10 @@ func (r *Reader) SeekToRow(rowIndex int64) error {
 	return nil
 }
 
+// FilterStats returns statistics about the rows pruned by the predicate that
+// the reader was configured with. See Filter for details.
+func (r *Reader) FilterStats() FilterStats { return r.filterStats }
+
 // Close closes the reader, preventing more rows from being read.
 func (r *Reader) Close() error {
 	if err := r.read.Close(); err != nil {
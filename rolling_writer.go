@@ -0,0 +1,208 @@
+package parquet
+
+import (
+	"io"
+	"slices"
+
+	"github.com/parquet-go/parquet-go/format"
+)
+
+// rollingWriterBatchSize is the maximum number of rows written to the file
+// between checks of the file size.
+const rollingWriterBatchSize = 64
+
+// RollingWriterConfig carries the configuration of a RollingWriter.
+type RollingWriterConfig struct {
+	// CreateFile is called to create the output of each file produced by the
+	// writer. The index argument starts at zero and is incremented for each
+	// new file. The writer closes the output after writing the file footer.
+	CreateFile func(index int) (io.WriteCloser, error)
+
+	// MaxFileBytes is the size at which the writer closes the current file
+	// and starts a new one. The size of the row group being written is an
+	// estimate, files may be slightly larger than the limit. Zero means that
+	// there are no limits.
+	MaxFileBytes int64
+
+	// MaxFileRows is the maximum number of rows written to each file. Zero
+	// means that there are no limits.
+	MaxFileRows int64
+
+	// OnFileClosed is called with the metadata written in the footer of each
+	// file after its output was closed. Errors returned by the function are
+	// reported to the caller of the method which closed the file.
+	OnFileClosed func(index int, metadata *format.FileMetaData) error
+}
+
+// Validate returns a non-nil error if the configuration of c is invalid.
+func (c *RollingWriterConfig) Validate() error {
+	const baseName = "parquet.(*RollingWriterConfig)."
+	var reasons []error
+	if c.CreateFile == nil {
+		reasons = append(reasons, errorInvalidOptionValue(baseName+"CreateFile", nil))
+	}
+	if c.MaxFileBytes < 0 {
+		reasons = append(reasons, errorInvalidOptionValue(baseName+"MaxFileBytes", c.MaxFileBytes))
+	}
+	if c.MaxFileRows < 0 {
+		reasons = append(reasons, errorInvalidOptionValue(baseName+"MaxFileRows", c.MaxFileRows))
+	}
+	return errorInvalidConfiguration(reasons...)
+}
+
+// RollingWriter is a type similar to GenericWriter but it spreads the rows it
+// writes over a sequence of parquet files, starting a new file when the current
+// one reaches the size or row count limits of its configuration.
+//
+// All files are written with the same schema and writer options, including key
+// value metadata. Files are created lazily when rows are written to them, so no
+// empty files are produced.
+type RollingWriter[T any] struct {
+	writer  *GenericWriter[T]
+	config  RollingWriterConfig
+	output  io.WriteCloser
+	index   int
+	numRows int64
+}
+
+// NewRollingWriter constructs a new rolling writer which creates files using
+// the configuration passed as first argument. The writer options are applied
+// to each file.
+//
+// The function panics if the configuration is invalid.
+func NewRollingWriter[T any](config *RollingWriterConfig, options ...WriterOption) *RollingWriter[T] {
+	if err := config.Validate(); err != nil {
+		panic(err)
+	}
+	return &RollingWriter[T]{
+		writer: NewGenericWriter[T](nil, options...),
+		config: *config,
+	}
+}
+
+// Close closes the file being written, if any.
+func (w *RollingWriter[T]) Close() error {
+	if w.output == nil {
+		return nil
+	}
+	return w.closeFile()
+}
+
+// Flush flushes the buffered rows to a row group of the file being written.
+func (w *RollingWriter[T]) Flush() error {
+	if w.output == nil {
+		return nil
+	}
+	return w.writer.Flush()
+}
+
+// Write writes rows to the current file, rolling over to new files when the
+// limits are reached.
+func (w *RollingWriter[T]) Write(rows []T) (int, error) {
+	return w.write(len(rows), func(i, j int) (int, error) {
+		return w.writer.Write(rows[i:j:j])
+	})
+}
+
+// WriteRows writes rows to the current file, rolling over to new files when
+// the limits are reached.
+func (w *RollingWriter[T]) WriteRows(rows []Row) (int, error) {
+	return w.write(len(rows), func(i, j int) (int, error) {
+		return w.writer.WriteRows(rows[i:j:j])
+	})
+}
+
+// SetKeyValueMetadata sets a key/value pair in the metadata of the file being
+// written and all the files created after it.
+func (w *RollingWriter[T]) SetKeyValueMetadata(key, value string) {
+	w.writer.SetKeyValueMetadata(key, value)
+}
+
+// Schema returns the schema of rows written by w.
+func (w *RollingWriter[T]) Schema() *Schema {
+	return w.writer.Schema()
+}
+
+func (w *RollingWriter[T]) write(numRows int, write func(i, j int) (int, error)) (int, error) {
+	written := 0
+
+	for written < numRows {
+		if w.output == nil {
+			if err := w.createFile(); err != nil {
+				return written, err
+			}
+		}
+
+		batchSize := min(numRows-written, rollingWriterBatchSize)
+		if w.config.MaxFileRows > 0 {
+			batchSize = int(min(int64(batchSize), w.config.MaxFileRows-w.numRows))
+		}
+		if w.config.MaxFileBytes > 0 && w.numRows > 0 {
+			// Use the average size of rows written to the file so far to
+			// avoid writing batches which would overflow the file.
+			fileSize := w.estimatedFileSize()
+			rowSize := max(fileSize/w.numRows, 1)
+			batchSize = int(min(int64(batchSize), max((w.config.MaxFileBytes-fileSize)/rowSize, 1)))
+		}
+
+		n, err := write(written, written+batchSize)
+		written += n
+		w.numRows += int64(n)
+		if err != nil {
+			return written, err
+		}
+
+		if w.isFileFull() {
+			if err := w.closeFile(); err != nil {
+				return written, err
+			}
+		}
+	}
+
+	return written, nil
+}
+
+func (w *RollingWriter[T]) isFileFull() bool {
+	if w.config.MaxFileRows > 0 && w.numRows >= w.config.MaxFileRows {
+		return true
+	}
+	return w.config.MaxFileBytes > 0 && w.estimatedFileSize() >= w.config.MaxFileBytes
+}
+
+func (w *RollingWriter[T]) estimatedFileSize() int64 {
+	base := w.writer.base.writer
+	return base.writer.offset + base.estimatedRowGroupSize()
+}
+
+func (w *RollingWriter[T]) createFile() error {
+	output, err := w.config.CreateFile(w.index)
+	if err != nil {
+		return err
+	}
+	w.output = output
+	w.numRows = 0
+	w.writer.Reset(output)
+	return nil
+}
+
+func (w *RollingWriter[T]) closeFile() error {
+	index, output := w.index, w.output
+	w.index++
+	w.output = nil
+
+	if err := w.writer.Close(); err != nil {
+		output.Close()
+		return err
+	}
+	if err := output.Close(); err != nil {
+		return err
+	}
+	if w.config.OnFileClosed == nil {
+		return nil
+	}
+
+	metadata := w.writer.base.writer.fileMetaData
+	metadata.RowGroups = slices.Clone(metadata.RowGroups)
+	metadata.KeyValueMetadata = slices.Clone(metadata.KeyValueMetadata)
+	return w.config.OnFileClosed(index, &metadata)
+}
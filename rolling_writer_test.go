@@ -0,0 +1,175 @@
+package parquet_test
+
+import (
+	"bytes"
+	"io"
+	"reflect"
+	"testing"
+
+	"github.com/parquet-go/parquet-go"
+	"github.com/parquet-go/parquet-go/format"
+)
+
+type rollingWriterFile struct {
+	bytes.Buffer
+	closed bool
+}
+
+func (f *rollingWriterFile) Close() error {
+	f.closed = true
+	return nil
+}
+
+func TestRollingWriterMaxFileRows(t *testing.T) {
+	type row struct {
+		ID   int64  `parquet:"id"`
+		Name string `parquet:"name"`
+	}
+
+	rows := make([]row, 1000)
+	for i := range rows {
+		rows[i] = row{ID: int64(i), Name: "name"}
+	}
+
+	var files []*rollingWriterFile
+	var metadata []*format.FileMetaData
+
+	w := parquet.NewRollingWriter[row](&parquet.RollingWriterConfig{
+		CreateFile: func(index int) (io.WriteCloser, error) {
+			if index != len(files) {
+				t.Errorf("wrong file index: want=%d got=%d", len(files), index)
+			}
+			f := new(rollingWriterFile)
+			files = append(files, f)
+			return f, nil
+		},
+		MaxFileRows: 300,
+		OnFileClosed: func(index int, m *format.FileMetaData) error {
+			if !files[index].closed {
+				t.Errorf("file %d was not closed before calling OnFileClosed", index)
+			}
+			metadata = append(metadata, m)
+			return nil
+		},
+	},
+		parquet.KeyValueMetadata("hello", "world"),
+	)
+
+	if n, err := w.Write(rows[:500]); err != nil {
+		t.Fatal(err)
+	} else if n != 500 {
+		t.Fatalf("wrong number of rows written: want=500 got=%d", n)
+	}
+	if _, err := w.Write(rows[500:]); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	wantNumRows := []int64{300, 300, 300, 100}
+	if len(files) != len(wantNumRows) {
+		t.Fatalf("wrong number of files: want=%d got=%d", len(wantNumRows), len(files))
+	}
+	if len(metadata) != len(wantNumRows) {
+		t.Fatalf("wrong number of OnFileClosed calls: want=%d got=%d", len(wantNumRows), len(metadata))
+	}
+
+	var read []row
+	for i, file := range files {
+		if metadata[i].NumRows != wantNumRows[i] {
+			t.Errorf("file %d: wrong number of rows in metadata: want=%d got=%d", i, wantNumRows[i], metadata[i].NumRows)
+		}
+
+		f, err := parquet.OpenFile(bytes.NewReader(file.Bytes()), int64(file.Len()))
+		if err != nil {
+			t.Fatalf("file %d: %v", i, err)
+		}
+		if f.NumRows() != wantNumRows[i] {
+			t.Errorf("file %d: wrong number of rows: want=%d got=%d", i, wantNumRows[i], f.NumRows())
+		}
+		if value, ok := f.Lookup("hello"); !ok || value != "world" {
+			t.Errorf("file %d: missing key/value metadata: %q", i, value)
+		}
+
+		values, err := parquet.Read[row](bytes.NewReader(file.Bytes()), int64(file.Len()))
+		if err != nil {
+			t.Fatalf("file %d: %v", i, err)
+		}
+		read = append(read, values...)
+	}
+
+	if !reflect.DeepEqual(read, rows) {
+		t.Error("rows read from the files do not match the rows written")
+	}
+}
+
+func TestRollingWriterMaxFileBytes(t *testing.T) {
+	type row struct {
+		ID   int64  `parquet:"id"`
+		Data []byte `parquet:"data"`
+	}
+
+	const maxFileBytes = 64 * 1024
+
+	var files []*rollingWriterFile
+	w := parquet.NewRollingWriter[row](&parquet.RollingWriterConfig{
+		CreateFile: func(int) (io.WriteCloser, error) {
+			f := new(rollingWriterFile)
+			files = append(files, f)
+			return f, nil
+		},
+		MaxFileBytes: maxFileBytes,
+	},
+		parquet.Compression(&parquet.Uncompressed),
+		parquet.PageBufferSize(4096),
+	)
+
+	rows := make([]row, 1000)
+	for i := range rows {
+		rows[i] = row{ID: int64(i), Data: bytes.Repeat([]byte{byte(i)}, 1000)}
+	}
+	if _, err := w.Write(rows); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(files) < 10 {
+		t.Fatalf("expected at least 10 files but got %d", len(files))
+	}
+
+	numRows := int64(0)
+	for i, file := range files {
+		// The size of rows being buffered is an estimate, files may slightly
+		// exceed the limit.
+		if file.Len() > maxFileBytes+maxFileBytes/4 {
+			t.Errorf("file %d is too large: %d bytes", i, file.Len())
+		}
+		f, err := parquet.OpenFile(bytes.NewReader(file.Bytes()), int64(file.Len()))
+		if err != nil {
+			t.Fatalf("file %d: %v", i, err)
+		}
+		numRows += f.NumRows()
+	}
+	if numRows != int64(len(rows)) {
+		t.Errorf("wrong number of rows: want=%d got=%d", len(rows), numRows)
+	}
+}
+
+func TestRollingWriterConfigValidation(t *testing.T) {
+	if err := (&parquet.RollingWriterConfig{}).Validate(); err == nil {
+		t.Error("expected an error for a missing CreateFile function")
+	}
+	config := &parquet.RollingWriterConfig{
+		CreateFile: func(int) (io.WriteCloser, error) { return nil, nil },
+	}
+	if err := config.Validate(); err != nil {
+		t.Error(err)
+	}
+	config.MaxFileRows = -1
+	if err := config.Validate(); err == nil {
+		t.Error("expected an error for a negative MaxFileRows")
+	}
+}
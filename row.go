@@ -417,6 +417,8 @@ func deconstructFuncOf(columnIndex int16, node Node) (int16, deconstructFunc) {
 		return deconstructFuncOfList(columnIndex, node)
 	case isMap(node):
 		return deconstructFuncOfMap(columnIndex, node)
+	case isVariant(node):
+		return deconstructFuncOfVariant(columnIndex, node)
 	default:
 		return deconstructFuncOfRequired(columnIndex, node)
 	}
@@ -565,6 +567,8 @@ func reconstructFuncOf(columnIndex int16, node Node) (int16, reconstructFunc) {
 		return reconstructFuncOfList(columnIndex, node)
 	case isMap(node):
 		return reconstructFuncOfMap(columnIndex, node)
+	case isVariant(node):
+		return reconstructFuncOfVariant(columnIndex, node)
 	default:
 		return reconstructFuncOfRequired(columnIndex, node)
 	}
@@ -0,0 +1,357 @@
+package parquet
+
+import "io"
+
+// rowFilterBatchSize is the maximum number of rows for which the values of
+// predicate columns are decoded and evaluated at once.
+const rowFilterBatchSize = 1024
+
+// rowFilter configures a rowGroupRows to only return the rows within a set of
+// row ranges which match a predicate.
+//
+// The rows are read in two steps: values of the columns that the predicate
+// depends on are decoded first, for batches of rows, and the predicate is
+// evaluated on each row of the batch. The values of other columns are then
+// only decoded for the rows that matched; the column readers skip the rows in
+// between, seeking past whole pages when the column chunks have an offset
+// index.
+type rowFilter struct {
+	ranges   rowRanges
+	match    func(Row) bool
+	columns  []bool // columns that the predicate depends on
+	seekable []bool // columns with an offset index to seek to pages
+
+	index      int   // index of the current row range
+	rowIndex   int64 // index of the next row to evaluate
+	batch      []Row // values of predicate columns of the current batch
+	batchStart int64 // index of the first row of the batch
+	selected   []int64
+	offset     int // index of the next selected row to return
+	skip       Row
+}
+
+func newRowFilter(rowGroup RowGroup, ranges rowRanges, match func(Row) bool, columns []bool) *rowFilter {
+	chunks := rowGroup.ColumnChunks()
+	f := &rowFilter{
+		ranges:   ranges,
+		match:    match,
+		columns:  columns,
+		seekable: make([]bool, len(chunks)),
+	}
+	if f.match == nil {
+		f.columns = make([]bool, len(chunks))
+	}
+	for i, chunk := range chunks {
+		// Seeking to a row of column chunks without an offset index requires
+		// reading all the pages from the beginning of the chunk, it is more
+		// efficient to skip pages while reading sequentially.
+		f.seekable[i] = true
+		if c, ok := chunk.(*fileColumnChunk); ok {
+			_, err := c.OffsetIndex()
+			f.seekable[i] = err == nil
+		}
+	}
+	return f
+}
+
+func (f *rowFilter) reset() {
+	f.index = 0
+	f.rowIndex = 0
+	f.batchStart = 0
+	f.batch = f.batch[:0]
+	f.selected = f.selected[:0]
+	f.offset = 0
+}
+
+// next positions f on the next row to evaluate, returning the maximum number
+// of rows that can be evaluated before reaching the end of the current range.
+// It returns zero when all the ranges have been consumed.
+func (f *rowFilter) next() int64 {
+	for f.index < len(f.ranges) {
+		r := f.ranges[f.index]
+		if f.rowIndex < r.start {
+			f.rowIndex = r.start
+		}
+		if f.rowIndex < r.end {
+			return r.end - f.rowIndex
+		}
+		f.index++
+	}
+	return 0
+}
+
+// readFilteredRows is the implementation of ReadRows for rowGroupRows which
+// have a filter.
+func (r *rowGroupRows) readFilteredRows(rows []Row) (int, error) {
+	f := r.filter
+
+	for i := range r.columns {
+		r.columns[i].used = false
+	}
+	for i := range rows {
+		rows[i] = rows[i][:0]
+	}
+
+	n := 0
+	for n < len(rows) {
+		if f.offset == len(f.selected) {
+			ok, err := r.readFilterBatch()
+			if err != nil {
+				if err == io.EOF && n > 0 {
+					err = nil
+				}
+				return n, err
+			}
+			if !ok {
+				break
+			}
+			continue
+		}
+
+		rowIndex := f.selected[f.offset]
+		for i := range r.columns {
+			if f.columns[i] {
+				continue
+			}
+			ok, err := r.seekColumn(i, rowIndex)
+			if err != nil {
+				return n, unexpectedEOF(err)
+			}
+			if !ok {
+				// Moving to the next page of the column would invalidate
+				// values of rows that were already returned.
+				return n, nil
+			}
+		}
+
+		row := rows[n]
+		var batchRow Row
+		if f.match != nil {
+			batchRow = f.batch[rowIndex-f.batchStart]
+		}
+		for i := range r.columns {
+			c := &r.columns[i]
+			c.used = true
+			if f.columns[i] {
+				row = appendColumnValues(row, batchRow, i)
+				continue
+			}
+			var err error
+			if row, err = r.readColumnRow(i, row); err != nil {
+				return n, err
+			}
+			c.rows--
+			c.rowIndex++
+		}
+		rows[n] = row
+		f.offset++
+		n++
+	}
+	return n, nil
+}
+
+// readFilterBatch decodes the values of predicate columns for the next batch
+// of rows and evaluates the predicate. It returns false if it could not read
+// the batch without invalidating values of rows already returned by the
+// current call to ReadRows.
+func (r *rowGroupRows) readFilterBatch() (bool, error) {
+	f := r.filter
+	f.batch = f.batch[:0]
+	f.selected = f.selected[:0]
+	f.offset = 0
+
+	for {
+		limit := min(f.next(), rowFilterBatchSize)
+		if limit == 0 {
+			return false, io.EOF
+		}
+
+		if f.match == nil {
+			for i := int64(0); i < limit; i++ {
+				f.selected = append(f.selected, f.rowIndex+i)
+			}
+			f.rowIndex += limit
+			return true, nil
+		}
+
+		for i, ok := range f.columns {
+			if !ok {
+				continue
+			}
+			if ok, err := r.seekColumn(i, f.rowIndex); !ok || err != nil {
+				return ok, unexpectedEOF(err)
+			}
+			limit = min(limit, r.columns[i].rows)
+		}
+
+		for len(f.batch) < int(limit) {
+			f.batch = append(f.batch, nil)
+		}
+		f.batch = f.batch[:limit]
+		f.batchStart = f.rowIndex
+
+		for j := range f.batch {
+			row := f.batch[j][:0]
+			for i, ok := range f.columns {
+				if !ok {
+					continue
+				}
+				var err error
+				if row, err = r.readColumnRow(i, row); err != nil {
+					return false, err
+				}
+				r.columns[i].rows--
+				r.columns[i].rowIndex++
+			}
+			f.batch[j] = row
+			if f.match(row) {
+				f.selected = append(f.selected, f.rowIndex+int64(j))
+			}
+		}
+
+		f.rowIndex += limit
+		if len(f.selected) > 0 {
+			return true, nil
+		}
+	}
+}
+
+// seekColumn positions the reader of column i on the given row index. The
+// method returns false if the row is in a different page and the values of
+// the current page were returned by the current call to ReadRows.
+func (r *rowGroupRows) seekColumn(i int, rowIndex int64) (bool, error) {
+	c := &r.columns[i]
+	if rowIndex >= c.rowIndex && rowIndex < c.rowIndex+c.rows {
+		return true, r.skipColumnRows(i, rowIndex-c.rowIndex)
+	}
+	if c.used {
+		return false, nil
+	}
+
+	if rowIndex < c.rowIndex || (rowIndex > c.rowIndex+c.rows && r.filter.seekable[i]) {
+		if err := r.readers[i].SeekToRow(rowIndex); err != nil {
+			return false, err
+		}
+		c.rowIndex = rowIndex
+	} else {
+		c.rowIndex += c.rows
+	}
+
+	for {
+		clearValues(r.buffer(i))
+		Release(c.page)
+		c.page, c.values = nil, nil
+		c.offset, c.length, c.rows = 0, 0, 0
+
+		page, err := r.readers[i].ReadPage()
+		if err != nil {
+			return false, err
+		}
+		c.page = page
+		c.values = page.Values()
+		c.rows = page.NumRows()
+
+		if rowIndex < c.rowIndex+c.rows {
+			return true, r.skipColumnRows(i, rowIndex-c.rowIndex)
+		}
+		c.rowIndex += c.rows
+		c.rows = 0
+	}
+}
+
+// skipColumnRows discards the values of the next n rows of column i, which
+// must all be in the current page.
+func (r *rowGroupRows) skipColumnRows(i int, n int64) (err error) {
+	c := &r.columns[i]
+	for ; n > 0; n-- {
+		if r.filter.skip, err = r.readColumnRow(i, r.filter.skip[:0]); err != nil {
+			return err
+		}
+		c.rows--
+		c.rowIndex++
+	}
+	clearValues(r.filter.skip)
+	return nil
+}
+
+// readColumnRow appends the values of the next row of column i to row.
+func (r *rowGroupRows) readColumnRow(i int, row Row) (Row, error) {
+	col := &r.columns[i]
+	buf := r.buffer(i)
+	skip := int32(1)
+
+	for {
+		if col.offset == col.length {
+			n, err := col.values.ReadValues(buf)
+			if n == 0 {
+				switch err {
+				case nil:
+					err = io.ErrNoProgress
+				case io.EOF:
+					return row, nil
+				}
+				return row, err
+			}
+			col.offset = 0
+			col.length = int32(n)
+		}
+
+		endOffset := col.offset + skip
+		for endOffset < col.length && buf[endOffset].repetitionLevel != 0 {
+			endOffset++
+		}
+
+		row = append(row, buf[col.offset:endOffset]...)
+
+		if col.offset = endOffset; col.offset < col.length {
+			return row, nil
+		}
+		skip = 0
+	}
+}
+
+// seekFilteredRows is the implementation of SeekToRow for rowGroupRows which
+// have a filter; the row index is relative to the rows matching the filter.
+func (r *rowGroupRows) seekFilteredRows(rowIndex int64) error {
+	r.Reset()
+	_, err := skipRows(r, rowIndex)
+	return err
+}
+
+// skipRows reads and discards up to n rows from rows. It returns the number of
+// rows that were skipped, which is less than n if the end of rows was reached.
+func skipRows(rows RowReader, n int64) (int64, error) {
+	if n == 0 {
+		return 0, nil
+	}
+	buf := make([]Row, min(n, defaultRowBufferSize))
+	skipped := int64(0)
+	for skipped < n {
+		c, err := rows.ReadRows(buf[:min(n-skipped, int64(len(buf)))])
+		skipped += int64(c)
+		if err != nil {
+			if err == io.EOF {
+				err = nil
+			}
+			return skipped, err
+		}
+	}
+	return skipped, nil
+}
+
+// appendColumnValues appends the values of column i in row to dst.
+func appendColumnValues(dst, row Row, i int) Row {
+	for _, v := range row {
+		if v.Column() == i {
+			dst = append(dst, v)
+		}
+	}
+	return dst
+}
+
+func unexpectedEOF(err error) error {
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	return err
+}
@@ -178,6 +178,7 @@ type rowGroupRows struct {
 	closed       bool
 	done         chan<- struct{}
 	pageReadMode ReadMode
+	filter       *rowFilter
 }
 
 type columnChunkRows struct {
@@ -186,6 +187,9 @@ type columnChunkRows struct {
 	length int32
 	page   Page
 	values ValueReader
+	// The fields below are only used when reading rows with a filter.
+	rowIndex int64
+	used     bool
 }
 
 const columnBufferSize = defaultValueBufferSize
@@ -256,6 +260,9 @@ func (r *rowGroupRows) Reset() {
 		r.readers[i].SeekToRow(0)
 	}
 	r.clear()
+	if r.filter != nil {
+		r.filter.reset()
+	}
 }
 
 func (r *rowGroupRows) Close() error {
@@ -285,6 +292,10 @@ func (r *rowGroupRows) SeekToRow(rowIndex int64) error {
 		return io.ErrClosedPipe
 	}
 
+	if r.filter != nil {
+		return r.seekFilteredRows(rowIndex)
+	}
+
 	if !r.inited {
 		r.init()
 	}
@@ -308,6 +319,10 @@ func (r *rowGroupRows) ReadRows(rows []Row) (int, error) {
 		r.init()
 	}
 
+	if r.filter != nil {
+		return r.readFilteredRows(rows)
+	}
+
 	// Limit the number of rows that we read to the smallest number of rows
 	// remaining in the current page of each column. This is necessary because
 	// the pointers exposed to the returned rows need to remain valid until the
@@ -0,0 +1,99 @@
+package parquet
+
+import (
+	"fmt"
+	"slices"
+)
+
+// RowRange represents the range of row indexes [Start, End).
+type RowRange struct {
+	Start int64
+	End   int64
+}
+
+// NumRows returns the number of rows in the range.
+func (r RowRange) NumRows() int64 { return max(r.End-r.Start, 0) }
+
+// String returns a human-readable representation of the row range.
+func (r RowRange) String() string { return fmt.Sprintf("[%d:%d)", r.Start, r.End) }
+
+// RowSelection is a list of sorted and non-overlapping row ranges, used to
+// read a subset of the rows of a file or row group.
+//
+// Row selections are passed to readers with the SelectRows option, or used to
+// read rows from a row group with the SelectedRows function. The row indexes
+// are relative to the beginning of the file or row group that rows are read
+// from, and only the pages containing rows of the selection are read when the
+// column chunks have an offset index.
+type RowSelection []RowRange
+
+// RowSelectionOf returns a RowSelection of the given row indexes, which do not
+// need to be sorted and may contain duplicates.
+func RowSelectionOf(rowIndexes ...int64) RowSelection {
+	rowIndexes = slices.Clone(rowIndexes)
+	slices.Sort(rowIndexes)
+
+	var selection RowSelection
+	for _, rowIndex := range rowIndexes {
+		if n := len(selection); n > 0 && selection[n-1].End >= rowIndex {
+			selection[n-1].End = max(selection[n-1].End, rowIndex+1)
+		} else {
+			selection = append(selection, RowRange{Start: rowIndex, End: rowIndex + 1})
+		}
+	}
+	return selection
+}
+
+// NumRows returns the number of rows in the selection.
+func (s RowSelection) NumRows() (numRows int64) {
+	for _, r := range s {
+		numRows += r.NumRows()
+	}
+	return numRows
+}
+
+// Validate returns a non-nil error if the ranges of s are not sorted, overlap,
+// or have negative row indexes.
+func (s RowSelection) Validate() error {
+	end := int64(0)
+	for i, r := range s {
+		switch {
+		case r.Start < 0:
+			return fmt.Errorf("invalid row selection: negative start of range %d %s", i, r)
+		case r.End < r.Start:
+			return fmt.Errorf("invalid row selection: end of range %d %s is before its start", i, r)
+		case i > 0 && r.Start < end:
+			return fmt.Errorf("invalid row selection: range %d %s is not sorted or overlaps with the previous range", i, r)
+		}
+		end = r.End
+	}
+	return nil
+}
+
+// rowRanges returns the ranges of s within the range of rows [offset,
+// offset+numRows), relative to offset.
+func (s RowSelection) rowRanges(offset, numRows int64) (ranges rowRanges) {
+	i, _ := slices.BinarySearchFunc(s, offset, func(r RowRange, rowIndex int64) int {
+		return compareInt64(r.End, rowIndex+1)
+	})
+	for _, r := range s[i:] {
+		if r.Start >= offset+numRows {
+			break
+		}
+		ranges = ranges.append(max(r.Start-offset, 0), min(r.End-offset, numRows))
+	}
+	return ranges
+}
+
+// SelectedRows returns a Rows reading the rows of rowGroup which are within the
+// row selection. The method panics if the selection is invalid.
+//
+// Pages of the column chunks which contain no selected rows are skipped, and
+// the values of the selected rows are read from the pages that contain them.
+func SelectedRows(rowGroup RowGroup, selection RowSelection) Rows {
+	if err := selection.Validate(); err != nil {
+		panic(err)
+	}
+	selected, _ := filterRowGroups(rowGroupsOf(rowGroup), rowGroup.Schema(), nil, selection)
+	return selected.Rows()
+}
@@ -0,0 +1,161 @@
+package parquet_test
+
+import (
+	"bytes"
+	"io"
+	"reflect"
+	"testing"
+
+	"github.com/parquet-go/parquet-go"
+)
+
+func TestRowSelectionOf(t *testing.T) {
+	selection := parquet.RowSelectionOf(7, 3, 4, 5, 10, 4, 11, 0)
+	want := parquet.RowSelection{{0, 1}, {3, 6}, {7, 8}, {10, 12}}
+	if !reflect.DeepEqual(selection, want) {
+		t.Errorf("wrong selection: want=%v got=%v", want, selection)
+	}
+	if numRows := selection.NumRows(); numRows != 7 {
+		t.Errorf("wrong number of rows: want=7 got=%d", numRows)
+	}
+	if err := selection.Validate(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestRowSelectionValidate(t *testing.T) {
+	for _, selection := range []parquet.RowSelection{
+		{{-1, 2}},
+		{{2, 1}},
+		{{0, 5}, {4, 6}},
+		{{5, 6}, {0, 1}},
+	} {
+		if err := selection.Validate(); err == nil {
+			t.Errorf("expected an error validating %v", selection)
+		}
+	}
+}
+
+type rowSelectionRow struct {
+	ID      int64  `parquet:"id"`
+	Payload []byte `parquet:"payload"`
+}
+
+// rowSelectionIDs returns n rows numbered from 0, which is all the filter and
+// row group tests need.
+func rowSelectionIDs(n int) []rowSelectionRow {
+	rows := make([]rowSelectionRow, n)
+	for i := range rows {
+		rows[i].ID = int64(i)
+	}
+	return rows
+}
+
+func TestRowSelection(t *testing.T) {
+	rows := rowSelectionIDs(2000)
+	for i := range rows {
+		rows[i].Payload = bytes.Repeat([]byte{byte(i)}, 1000)
+	}
+	file, input := writeTestFile(t, rows,
+		parquet.Compression(&parquet.Uncompressed),
+		parquet.MaxRowsPerRowGroup(1000),
+		parquet.DataPageRowCountLimit(100),
+	)
+	selection := parquet.RowSelection{{5, 8}, {95, 105}, {990, 1010}, {1999, 2000}}
+
+	var want []rowSelectionRow
+	for _, r := range selection {
+		want = append(want, rows[r.Start:r.End]...)
+	}
+
+	input.bytesRead = 0
+	reader := parquet.NewGenericReader[rowSelectionRow](file, parquet.SelectRows(selection))
+	defer reader.Close()
+
+	if numRows := reader.NumRows(); numRows != selection.NumRows() {
+		t.Errorf("wrong number of rows: want=%d got=%d", selection.NumRows(), numRows)
+	}
+	values := make([]rowSelectionRow, len(rows))
+	n, err := reader.Read(values)
+	if err != nil && err != io.EOF {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(values[:n], want) {
+		t.Errorf("wrong rows: want=%d rows got=%d rows", len(want), n)
+	}
+	// The selection overlaps with 6 pages of each column, out of 20.
+	if maxBytes := int64(len(rows)*1000) / 3; input.bytesRead > maxBytes {
+		t.Errorf("too many bytes read: %d > %d", input.bytesRead, maxBytes)
+	}
+
+	if err := reader.SeekToRow(13); err != nil {
+		t.Fatal(err)
+	}
+	n, err = reader.Read(values[:2])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(values[:n], want[13:15]) {
+		t.Errorf("wrong rows after seek: want=%v got=%v", want[13:15], values[:n])
+	}
+}
+
+func TestRowSelectionFilter(t *testing.T) {
+	file, _ := writeTestFile(t, rowSelectionIDs(2000), parquet.DataPageRowCountLimit(100))
+
+	reader := parquet.NewGenericReader[rowSelectionRow](file,
+		parquet.SelectRows(parquet.RowSelectionOf(1, 2, 3, 500, 1500, 1501)),
+		parquet.Filter(parquet.Ge([]string{"id"}, parquet.ValueOf(int64(3)))),
+	)
+	defer reader.Close()
+
+	values := make([]rowSelectionRow, 10)
+	n, err := reader.Read(values)
+	if err != nil && err != io.EOF {
+		t.Fatal(err)
+	}
+	var ids []int64
+	for _, v := range values[:n] {
+		ids = append(ids, v.ID)
+	}
+	if want := []int64{3, 500, 1500, 1501}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("wrong rows: want=%v got=%v", want, ids)
+	}
+}
+
+func TestSelectedRows(t *testing.T) {
+	file, _ := writeTestFile(t, rowSelectionIDs(2000), parquet.MaxRowsPerRowGroup(1000))
+	rowGroup := file.RowGroups()[1]
+
+	rows := parquet.SelectedRows(rowGroup, parquet.RowSelection{{0, 2}, {998, 1200}})
+	defer rows.Close()
+
+	buf := make([]parquet.Row, 10)
+	var ids []int64
+	for {
+		n, err := rows.ReadRows(buf)
+		for _, row := range buf[:n] {
+			ids = append(ids, row[0].Int64())
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if want := []int64{1000, 1001, 1998, 1999}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("wrong rows: want=%v got=%v", want, ids)
+	}
+}
+
+func TestRowSelectionInvalid(t *testing.T) {
+	file, _ := writeTestFile(t, rowSelectionIDs(100))
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected a panic for an invalid row selection")
+		}
+	}()
+	parquet.NewGenericReader[rowSelectionRow](file, parquet.SelectRows(parquet.RowSelection{{10, 20}, {15, 30}}))
+}
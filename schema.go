@@ -13,6 +13,7 @@ import (
 	"github.com/parquet-go/parquet-go/compress"
 	"github.com/parquet-go/parquet-go/deprecated"
 	"github.com/parquet-go/parquet-go/encoding"
+	"github.com/parquet-go/parquet-go/format"
 )
 
 // Schema represents a parquet schema created from a Go value.
@@ -54,10 +55,14 @@ type Schema struct {
 //	list      | for slice types, use the parquet LIST logical type
 //	enum      | for string types, use the parquet ENUM logical type
 //	uuid      | for string and [16]byte types, use the parquet UUID logical type
+//	float16   | for float32, float64 and [2]byte types, use the parquet FLOAT16 logical type
+//	variant   | for any type, use the parquet VARIANT logical type to store semi-structured values
+//	geometry  | for string and []byte types, use the parquet GEOMETRY logical type to store WKB values
+//	geography | for string and []byte types, use the parquet GEOGRAPHY logical type to store WKB values
 //	decimal   | for int32, int64 and [n]byte types, use the parquet DECIMAL logical type
 //	date      | for int32 types use the DATE logical type
 //	timestamp | for int64 types use the TIMESTAMP logical type with, by default, millisecond precision
-//	split     | for float32/float64, use the BYTE_STREAM_SPLIT encoding
+//	split     | for float32/float64, integer, time.Time and [n]byte types, use the BYTE_STREAM_SPLIT encoding
 //	id(n)     | where n is int denoting a column field id. Example id(2) for a column with field id of 2
 //
 // # The date logical type is an int32 value of the number of days since the unix epoch
@@ -534,6 +539,8 @@ func nodeOf(t reflect.Type, tag []string) Node {
 		return Leaf(Int96Type)
 	case reflect.TypeOf(uuid.UUID{}):
 		return UUID()
+	case reflect.TypeOf(deprecated.Duration{}):
+		return Interval()
 	case reflect.TypeOf(time.Time{}):
 		return Timestamp(Nanosecond)
 	}
@@ -593,6 +600,8 @@ func nodeOf(t reflect.Type, tag []string) Node {
 
 		if strings.Contains(mapTag, "json") {
 			n = JSON()
+		} else if strings.Contains(mapTag, "variant") {
+			n = Variant()
 		} else {
 			n = Map(
 				makeNodeOf(t.Key(), t.Name(), []string{keyTag}),
@@ -602,7 +611,7 @@ func nodeOf(t reflect.Type, tag []string) Node {
 
 		forEachTagOption([]string{mapTag}, func(option, args string) {
 			switch option {
-			case "", "json":
+			case "", "json", "variant":
 				return
 			case "optional":
 				n = Optional(n)
@@ -799,6 +808,9 @@ func makeNodeOf(t reflect.Type, name string, tag []string) Node {
 		case "json":
 			setNode(JSON())
 
+		case "variant":
+			setNode(Variant())
+
 		case "delta":
 			switch t.Kind() {
 			case reflect.Int, reflect.Int32, reflect.Int64, reflect.Uint, reflect.Uint32, reflect.Uint64:
@@ -828,10 +840,22 @@ func makeNodeOf(t reflect.Type, name string, tag []string) Node {
 
 		case "split":
 			switch t.Kind() {
-			case reflect.Float32, reflect.Float64:
+			case reflect.Float32, reflect.Float64,
+				reflect.Int, reflect.Int32, reflect.Int64, reflect.Uint, reflect.Uint32, reflect.Uint64:
 				setEncoding(&ByteStreamSplit)
+			case reflect.Array:
+				if t.Elem().Kind() == reflect.Uint8 { // [N]byte?
+					setEncoding(&ByteStreamSplit)
+				} else {
+					throwInvalidTag(t, name, option)
+				}
 			default:
-				throwInvalidTag(t, name, option)
+				switch t {
+				case reflect.TypeOf(time.Time{}):
+					setEncoding(&ByteStreamSplit)
+				default:
+					throwInvalidTag(t, name, option)
+				}
 			}
 
 		case "list":
@@ -862,6 +886,32 @@ func makeNodeOf(t reflect.Type, name string, tag []string) Node {
 				throwInvalidTag(t, name, option)
 			}
 
+		case "float16":
+			switch t.Kind() {
+			case reflect.Float32, reflect.Float64:
+				setNode(Float16())
+			case reflect.Array:
+				if t.Elem().Kind() != reflect.Uint8 || t.Len() != float16Size {
+					throwInvalidTag(t, name, option)
+				}
+				setNode(Float16())
+			default:
+				throwInvalidTag(t, name, option)
+			}
+
+		case "geometry", "geography":
+			switch {
+			case t.Kind() == reflect.String,
+				t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8:
+				if option == "geometry" {
+					setNode(Geometry(""))
+				} else {
+					setNode(Geography("", format.Spherical))
+				}
+			default:
+				throwInvalidTag(t, name, option)
+			}
+
 		case "decimal":
 			scale, precision, err := parseDecimalArgs(args)
 			if err != nil {
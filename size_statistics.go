@@ -0,0 +1,194 @@
+package parquet
+
+import (
+	"slices"
+
+	"github.com/parquet-go/parquet-go/encoding"
+	"github.com/parquet-go/parquet-go/format"
+)
+
+// SizeStatisticsOf returns the size statistics of a column chunk, which
+// contain the number of bytes of BYTE_ARRAY values before encoding and
+// compression, and the histograms of repetition and definition levels.
+//
+// The function returns false if the column chunk was not read from a parquet
+// file or if the writer of the file did not record size statistics.
+func SizeStatisticsOf(chunk ColumnChunk) (format.SizeStatistics, bool) {
+	c, ok := chunk.(*fileColumnChunk)
+	if !ok {
+		return format.SizeStatistics{}, false
+	}
+	stats := c.chunk.MetaData.SizeStatistics
+	if stats.UnencodedByteArrayDataBytes == 0 && stats.RepetitionLevelHistogram == nil && stats.DefinitionLevelHistogram == nil {
+		return format.SizeStatistics{}, false
+	}
+	return stats, true
+}
+
+// ColumnIndexWithLevelHistograms is an optional interface implemented by column
+// indexes which give access to the histograms of repetition and definition
+// levels of their pages. The column indexes of column chunks read from parquet
+// files implement it.
+//
+// Programs use a type assertion to determine whether a column index exposes
+// histograms:
+//
+//	if index, ok := columnIndex.(parquet.ColumnIndexWithLevelHistograms); ok {
+//		histogram := index.DefinitionLevelHistogram(0)
+//		...
+//	}
+type ColumnIndexWithLevelHistograms interface {
+	ColumnIndex
+
+	// Returns the histograms of repetition and definition levels of the page
+	// at the given index, where the element at index n is the number of values
+	// at level n.
+	//
+	// The methods return nil if the column index does not have histograms,
+	// which is the case for columns with a max level of zero or when the
+	// writer of a parquet file did not record them.
+	RepetitionLevelHistogram(int) []int64
+	DefinitionLevelHistogram(int) []int64
+}
+
+var (
+	_ ColumnIndexWithLevelHistograms = (*formatColumnIndex)(nil)
+	_ ColumnIndexWithLevelHistograms = fileColumnIndex{}
+)
+
+func (f *formatColumnIndex) RepetitionLevelHistogram(i int) []int64 {
+	return pageLevelHistogram(f.index.RepetitionLevelHistograms, f.NumPages(), i)
+}
+
+func (f *formatColumnIndex) DefinitionLevelHistogram(i int) []int64 {
+	return pageLevelHistogram(f.index.DefinitionLevelHistograms, f.NumPages(), i)
+}
+
+func (i fileColumnIndex) RepetitionLevelHistogram(j int) []int64 {
+	return pageLevelHistogram(i.columnIndex().RepetitionLevelHistograms, i.NumPages(), j)
+}
+
+func (i fileColumnIndex) DefinitionLevelHistogram(j int) []int64 {
+	return pageLevelHistogram(i.columnIndex().DefinitionLevelHistograms, i.NumPages(), j)
+}
+
+// pageLevelHistogram returns the histogram of page i from the concatenation of
+// the histograms of all pages of a column index.
+func pageLevelHistogram(histograms []int64, numPages, i int) []int64 {
+	if numPages == 0 || len(histograms)%numPages != 0 {
+		return nil
+	}
+	n := len(histograms) / numPages
+	return histograms[i*n : (i+1)*n : (i+1)*n]
+}
+
+// sizeStatistics accumulates the size statistics of a column chunk, and the
+// per-page values written to the page index.
+//
+// Level histograms are omitted for columns with a max level of zero since they
+// would only repeat the number of values.
+type sizeStatistics struct {
+	maxRepetitionLevel byte
+	maxDefinitionLevel byte
+	byteArray          bool
+
+	unencodedByteArrayDataBytes int64
+	repetitionLevelHistogram    []int64
+	definitionLevelHistogram    []int64
+
+	pageUnencodedByteArrayDataBytes []int64
+	pageRepetitionLevelHistograms   []int64
+	pageDefinitionLevelHistograms   []int64
+}
+
+func (s *sizeStatistics) init(typ Type, maxRepetitionLevel, maxDefinitionLevel byte) {
+	s.maxRepetitionLevel = maxRepetitionLevel
+	s.maxDefinitionLevel = maxDefinitionLevel
+	s.byteArray = typ.Kind() == ByteArray
+	s.reset()
+}
+
+func (s *sizeStatistics) reset() {
+	s.unencodedByteArrayDataBytes = 0
+	s.repetitionLevelHistogram = resetHistogram(s.repetitionLevelHistogram, s.maxRepetitionLevel)
+	s.definitionLevelHistogram = resetHistogram(s.definitionLevelHistogram, s.maxDefinitionLevel)
+	s.pageUnencodedByteArrayDataBytes = s.pageUnencodedByteArrayDataBytes[:0]
+	s.pageRepetitionLevelHistograms = s.pageRepetitionLevelHistograms[:0]
+	s.pageDefinitionLevelHistograms = s.pageDefinitionLevelHistograms[:0]
+}
+
+func resetHistogram(histogram []int64, maxLevel byte) []int64 {
+	if maxLevel == 0 {
+		return nil
+	}
+	histogram = slices.Grow(histogram[:0], int(maxLevel)+1)[:int(maxLevel)+1]
+	clear(histogram)
+	return histogram
+}
+
+// observePage records the statistics of a data page. The dictionary is used to
+// compute the size of values of dictionary-encoded pages, it may be nil.
+func (s *sizeStatistics) observePage(page Page, dict Dictionary) {
+	if s.byteArray {
+		size := unencodedByteArrayDataBytes(page, dict)
+		s.unencodedByteArrayDataBytes += size
+		s.pageUnencodedByteArrayDataBytes = append(s.pageUnencodedByteArrayDataBytes, size)
+	}
+	s.pageRepetitionLevelHistograms = observeLevels(s.pageRepetitionLevelHistograms, s.repetitionLevelHistogram, page.RepetitionLevels(), page.NumValues())
+	s.pageDefinitionLevelHistograms = observeLevels(s.pageDefinitionLevelHistograms, s.definitionLevelHistogram, page.DefinitionLevels(), page.NumValues())
+}
+
+// observeLevels appends the histogram of levels to pageHistograms, and adds it
+// to the chunk histogram. A page without levels has all its values at level
+// zero.
+func observeLevels(pageHistograms, histogram []int64, levels []byte, numValues int64) []int64 {
+	if histogram == nil {
+		return pageHistograms
+	}
+	offset := len(pageHistograms)
+	pageHistograms = append(pageHistograms, make([]int64, len(histogram))...)
+	pageHistogram := pageHistograms[offset:]
+	if len(levels) == 0 {
+		pageHistogram[0] = numValues
+	} else {
+		for _, level := range levels {
+			pageHistogram[level]++
+		}
+	}
+	for i, count := range pageHistogram {
+		histogram[i] += count
+	}
+	return pageHistograms
+}
+
+func unencodedByteArrayDataBytes(page Page, dict Dictionary) int64 {
+	data := page.Data()
+	switch data.Kind() {
+	case encoding.ByteArray:
+		values, _ := data.ByteArray()
+		return int64(len(values))
+	case encoding.Int32:
+		// Pages of dictionary-encoded columns hold the indexes of their values
+		// in the dictionary.
+		if dict == nil {
+			return 0
+		}
+		size := int64(0)
+		for _, index := range data.Int32() {
+			size += int64(len(dict.Index(index).ByteArray()))
+		}
+		return size
+	default:
+		return 0
+	}
+}
+
+// statistics returns the size statistics of the column chunk. The returned
+// value does not share memory with s, so it remains valid after s is reset.
+func (s *sizeStatistics) statistics() format.SizeStatistics {
+	return format.SizeStatistics{
+		UnencodedByteArrayDataBytes: s.unencodedByteArrayDataBytes,
+		RepetitionLevelHistogram:    slices.Clone(s.repetitionLevelHistogram),
+		DefinitionLevelHistogram:    slices.Clone(s.definitionLevelHistogram),
+	}
+}
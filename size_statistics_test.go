@@ -0,0 +1,168 @@
+package parquet_test
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/parquet-go/parquet-go"
+)
+
+func TestSizeStatistics(t *testing.T) {
+	type row struct {
+		ID    int64    `parquet:"id"`
+		Name  string   `parquet:"name,optional"`
+		Tags  []string `parquet:"tags,list"`
+		Color string   `parquet:"color,dict"`
+	}
+
+	colors := []string{"red", "green", "blue"}
+	rows := make([]row, 1000)
+	var (
+		nameBytes, tagsBytes, colorBytes int64
+		nameDefinitionLevels             = make([]int64, 2)
+		tagsRepetitionLevels             = make([]int64, 2)
+		tagsDefinitionLevels             = make([]int64, 2)
+	)
+	for i := range rows {
+		rows[i] = row{ID: int64(i), Color: colors[i%len(colors)]}
+		colorBytes += int64(len(rows[i].Color))
+
+		if i%4 == 0 {
+			nameDefinitionLevels[0]++
+		} else {
+			rows[i].Name = strings.Repeat("a", i%7+1)
+			nameBytes += int64(len(rows[i].Name))
+			nameDefinitionLevels[1]++
+		}
+
+		switch i % 3 {
+		case 0:
+			// Empty lists are represented by a single value at level zero.
+			tagsRepetitionLevels[0]++
+			tagsDefinitionLevels[0]++
+		default:
+			for j := 0; j < i%3; j++ {
+				rows[i].Tags = append(rows[i].Tags, strings.Repeat("t", j+1))
+				tagsBytes += int64(j + 1)
+				tagsRepetitionLevels[min(j, 1)]++
+				tagsDefinitionLevels[1]++
+			}
+		}
+	}
+
+	b := new(bytes.Buffer)
+	w := parquet.NewGenericWriter[row](b, parquet.PageBufferSize(256))
+	if _, err := w.Write(rows); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := parquet.OpenFile(bytes.NewReader(b.Bytes()), int64(b.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	schema := f.Schema()
+	chunks := f.RowGroups()[0].ColumnChunks()
+	lookup := func(path ...string) parquet.ColumnChunk {
+		leaf, ok := schema.Lookup(path...)
+		if !ok {
+			t.Fatalf("column %v not found", path)
+		}
+		return chunks[leaf.ColumnIndex]
+	}
+
+	if _, ok := parquet.SizeStatisticsOf(lookup("id")); ok {
+		t.Error("unexpected size statistics for a required INT64 column")
+	}
+
+	for _, test := range []struct {
+		path                        []string
+		unencodedByteArrayDataBytes int64
+		repetitionLevelHistogram    []int64
+		definitionLevelHistogram    []int64
+	}{
+		{
+			path:                        []string{"name"},
+			unencodedByteArrayDataBytes: nameBytes,
+			definitionLevelHistogram:    nameDefinitionLevels,
+		},
+		{
+			path:                        []string{"tags", "list", "element"},
+			unencodedByteArrayDataBytes: tagsBytes,
+			repetitionLevelHistogram:    tagsRepetitionLevels,
+			definitionLevelHistogram:    tagsDefinitionLevels,
+		},
+		{
+			path:                        []string{"color"},
+			unencodedByteArrayDataBytes: colorBytes,
+		},
+	} {
+		chunk := lookup(test.path...)
+		stats, ok := parquet.SizeStatisticsOf(chunk)
+		if !ok {
+			t.Fatalf("%v: missing size statistics", test.path)
+		}
+		if stats.UnencodedByteArrayDataBytes != test.unencodedByteArrayDataBytes {
+			t.Errorf("%v: wrong unencoded byte array data bytes: want=%d got=%d", test.path, test.unencodedByteArrayDataBytes, stats.UnencodedByteArrayDataBytes)
+		}
+		if !reflect.DeepEqual(stats.RepetitionLevelHistogram, test.repetitionLevelHistogram) {
+			t.Errorf("%v: wrong repetition level histogram: want=%v got=%v", test.path, test.repetitionLevelHistogram, stats.RepetitionLevelHistogram)
+		}
+		if !reflect.DeepEqual(stats.DefinitionLevelHistogram, test.definitionLevelHistogram) {
+			t.Errorf("%v: wrong definition level histogram: want=%v got=%v", test.path, test.definitionLevelHistogram, stats.DefinitionLevelHistogram)
+		}
+
+		// The sum of the page histograms of the column index must match the
+		// histograms of the column chunk.
+		index, err := chunk.ColumnIndex()
+		if err != nil {
+			t.Fatal(err)
+		}
+		columnIndex, ok := index.(parquet.ColumnIndexWithLevelHistograms)
+		if !ok {
+			t.Fatalf("%v: the column index does not expose level histograms", test.path)
+		}
+		if columnIndex.NumPages() < 2 {
+			t.Fatalf("%v: expected multiple pages but got %d", test.path, columnIndex.NumPages())
+		}
+		for _, histogram := range []struct {
+			want []int64
+			page func(int) []int64
+		}{
+			{test.repetitionLevelHistogram, columnIndex.RepetitionLevelHistogram},
+			{test.definitionLevelHistogram, columnIndex.DefinitionLevelHistogram},
+		} {
+			var sum []int64
+			for i := 0; i < columnIndex.NumPages(); i++ {
+				page := histogram.page(i)
+				if sum == nil && page != nil {
+					sum = make([]int64, len(page))
+				}
+				for j, n := range page {
+					sum[j] += n
+				}
+			}
+			if !reflect.DeepEqual(sum, histogram.want) {
+				t.Errorf("%v: page histograms do not sum to the column chunk histogram: want=%v got=%v", test.path, histogram.want, sum)
+			}
+		}
+	}
+
+	_, offsetIndexes, err := f.ReadPageIndex()
+	if err != nil {
+		t.Fatal(err)
+	}
+	leaf, _ := schema.Lookup("name")
+	sum := int64(0)
+	for _, size := range offsetIndexes[leaf.ColumnIndex].UnencodedByteArrayDataBytes {
+		sum += size
+	}
+	if sum != nameBytes {
+		t.Errorf("wrong sum of unencoded byte array data bytes in the offset index: want=%d got=%d", nameBytes, sum)
+	}
+}
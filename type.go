@@ -649,6 +649,8 @@ func (t floatType) ConvertValue(val Value, typ Type) (Value, error) {
 	switch typ.(type) {
 	case *stringType:
 		return convertStringToFloat(val)
+	case *float16Type:
+		return convertFloat16ToFloat(val)
 	}
 	switch typ.Kind() {
 	case Boolean:
@@ -730,6 +732,8 @@ func (t doubleType) ConvertValue(val Value, typ Type) (Value, error) {
 	switch typ.(type) {
 	case *stringType:
 		return convertStringToDouble(val)
+	case *float16Type:
+		return convertFloat16ToDouble(val)
 	}
 	switch typ.Kind() {
 	case Boolean:
@@ -1443,6 +1447,192 @@ func (t *uuidType) ConvertValue(val Value, typ Type) (Value, error) {
 	return be128Type{}.ConvertValue(val, typ)
 }
 
+// Float16 constructs a leaf node of FLOAT16 logical type.
+//
+// Values are IEEE 754 half-precision floating point numbers stored in
+// FIXED_LEN_BYTE_ARRAY(2) columns. Go values of type float32 or float64 are
+// converted to half-precision when written and back when read.
+//
+// https://github.com/apache/parquet-format/blob/master/LogicalTypes.md#float16
+func Float16() Node { return Leaf(&float16Type{}) }
+
+var float16BaseType = fixedLenByteArrayType{length: float16Size}
+
+type float16Type format.Float16Type
+
+func (t *float16Type) String() string { return (*format.Float16Type)(t).String() }
+
+func (t *float16Type) Kind() Kind { return float16BaseType.Kind() }
+
+func (t *float16Type) Length() int { return float16BaseType.Length() }
+
+func (t *float16Type) EstimateSize(n int) int { return float16BaseType.EstimateSize(n) }
+
+func (t *float16Type) EstimateNumValues(n int) int { return float16BaseType.EstimateNumValues(n) }
+
+func (t *float16Type) Compare(a, b Value) int { return compareFloat16(a.byteArray(), b.byteArray()) }
+
+func (t *float16Type) ColumnOrder() *format.ColumnOrder { return &typeDefinedColumnOrder }
+
+func (t *float16Type) PhysicalType() *format.Type { return &physicalTypes[FixedLenByteArray] }
+
+func (t *float16Type) LogicalType() *format.LogicalType {
+	return &format.LogicalType{Float16: (*format.Float16Type)(t)}
+}
+
+func (t *float16Type) ConvertedType() *deprecated.ConvertedType { return nil }
+
+func (t *float16Type) NewColumnIndexer(sizeLimit int) ColumnIndexer {
+	return newFloat16ColumnIndexer()
+}
+
+func (t *float16Type) NewDictionary(columnIndex, numValues int, data encoding.Values) Dictionary {
+	return newFloat16Dictionary(t, makeColumnIndex(columnIndex), makeNumValues(numValues), data)
+}
+
+func (t *float16Type) NewColumnBuffer(columnIndex, numValues int) ColumnBuffer {
+	return newFloat16ColumnBuffer(t, makeColumnIndex(columnIndex), makeNumValues(numValues))
+}
+
+func (t *float16Type) NewPage(columnIndex, numValues int, data encoding.Values) Page {
+	return newFloat16Page(t, makeColumnIndex(columnIndex), makeNumValues(numValues), data)
+}
+
+func (t *float16Type) NewValues(values []byte, offsets []uint32) encoding.Values {
+	return float16BaseType.NewValues(values, offsets)
+}
+
+func (t *float16Type) Encode(dst []byte, src encoding.Values, enc encoding.Encoding) ([]byte, error) {
+	return float16BaseType.Encode(dst, src, enc)
+}
+
+func (t *float16Type) Decode(dst encoding.Values, src []byte, enc encoding.Encoding) (encoding.Values, error) {
+	return float16BaseType.Decode(dst, src, enc)
+}
+
+func (t *float16Type) EstimateDecodeSize(numValues int, src []byte, enc encoding.Encoding) int {
+	return float16BaseType.EstimateDecodeSize(numValues, src, enc)
+}
+
+func (t *float16Type) AssignValue(dst reflect.Value, src Value) error {
+	switch dst.Kind() {
+	case reflect.Float32, reflect.Float64:
+		if src.IsNull() {
+			dst.SetFloat(0)
+		} else {
+			dst.SetFloat(float64(float16FromBytes(src.byteArray())))
+		}
+		return nil
+	}
+	return float16BaseType.AssignValue(dst, src)
+}
+
+func (t *float16Type) ConvertValue(val Value, typ Type) (Value, error) {
+	switch typ.(type) {
+	case *float16Type:
+		return val, nil
+	}
+	switch typ.Kind() {
+	case Float:
+		return convertFloatToFloat16(val)
+	case Double:
+		return convertDoubleToFloat16(val)
+	}
+	return float16BaseType.ConvertValue(val, typ)
+}
+
+// Interval constructs a leaf node of the deprecated INTERVAL converted type.
+//
+// Values are stored in FIXED_LEN_BYTE_ARRAY(12) columns as three little-endian
+// unsigned 32 bits integers representing a number of months, days, and
+// milliseconds. Go values of type deprecated.Duration map to this node.
+//
+// The sort order of intervals is undefined, parquet writers do not produce
+// statistics or column indexes for columns of this type.
+//
+// https://github.com/apache/parquet-format/blob/master/LogicalTypes.md#interval
+func Interval() Node { return Leaf(&intervalType{}) }
+
+var intervalBaseType = fixedLenByteArrayType{length: deprecated.IntervalSize}
+
+type intervalType struct{}
+
+func (t *intervalType) String() string { return "INTERVAL" }
+
+func (t *intervalType) Kind() Kind { return intervalBaseType.Kind() }
+
+func (t *intervalType) Length() int { return intervalBaseType.Length() }
+
+func (t *intervalType) EstimateSize(n int) int { return intervalBaseType.EstimateSize(n) }
+
+func (t *intervalType) EstimateNumValues(n int) int { return intervalBaseType.EstimateNumValues(n) }
+
+func (t *intervalType) Compare(a, b Value) int { return intervalBaseType.Compare(a, b) }
+
+func (t *intervalType) ColumnOrder() *format.ColumnOrder { return &typeDefinedColumnOrder }
+
+func (t *intervalType) PhysicalType() *format.Type { return &physicalTypes[FixedLenByteArray] }
+
+func (t *intervalType) LogicalType() *format.LogicalType { return nil }
+
+func (t *intervalType) ConvertedType() *deprecated.ConvertedType {
+	return &convertedTypes[deprecated.Interval]
+}
+
+func (t *intervalType) NewColumnIndexer(sizeLimit int) ColumnIndexer {
+	return intervalBaseType.NewColumnIndexer(sizeLimit)
+}
+
+func (t *intervalType) NewDictionary(columnIndex, numValues int, data encoding.Values) Dictionary {
+	return newFixedLenByteArrayDictionary(t, makeColumnIndex(columnIndex), makeNumValues(numValues), data)
+}
+
+func (t *intervalType) NewColumnBuffer(columnIndex, numValues int) ColumnBuffer {
+	return newFixedLenByteArrayColumnBuffer(t, makeColumnIndex(columnIndex), makeNumValues(numValues))
+}
+
+func (t *intervalType) NewPage(columnIndex, numValues int, data encoding.Values) Page {
+	return newFixedLenByteArrayPage(t, makeColumnIndex(columnIndex), makeNumValues(numValues), data)
+}
+
+func (t *intervalType) NewValues(values []byte, offsets []uint32) encoding.Values {
+	return intervalBaseType.NewValues(values, offsets)
+}
+
+func (t *intervalType) Encode(dst []byte, src encoding.Values, enc encoding.Encoding) ([]byte, error) {
+	return intervalBaseType.Encode(dst, src, enc)
+}
+
+func (t *intervalType) Decode(dst encoding.Values, src []byte, enc encoding.Encoding) (encoding.Values, error) {
+	return intervalBaseType.Decode(dst, src, enc)
+}
+
+func (t *intervalType) EstimateDecodeSize(numValues int, src []byte, enc encoding.Encoding) int {
+	return intervalBaseType.EstimateDecodeSize(numValues, src, enc)
+}
+
+func (t *intervalType) AssignValue(dst reflect.Value, src Value) error {
+	if dst.Type() == reflect.TypeOf(deprecated.Duration{}) {
+		dst.Set(reflect.ValueOf(src.Interval()))
+		return nil
+	}
+	return intervalBaseType.AssignValue(dst, src)
+}
+
+func (t *intervalType) ConvertValue(val Value, typ Type) (Value, error) {
+	return intervalBaseType.ConvertValue(val, typ)
+}
+
+// hasUndefinedOrder returns true if the sort order of values of type t is
+// undefined, in which case no statistics must be written for them.
+func hasUndefinedOrder(t Type) bool {
+	if lt := t.LogicalType(); lt != nil && (lt.Geometry != nil || lt.Geography != nil) {
+		return true
+	}
+	ct := t.ConvertedType()
+	return ct != nil && *ct == deprecated.Interval
+}
+
 // Enum constructs a leaf node with a logical type representing enumerations.
 //
 // https://github.com/apache/parquet-format/blob/master/LogicalTypes.md#enum
@@ -1689,6 +1879,175 @@ func (t *bsonType) ConvertValue(val Value, typ Type) (Value, error) {
 	}
 }
 
+// Geometry constructs a leaf node of GEOMETRY logical type.
+//
+// Values are geospatial features encoded in the Well-Known Binary (WKB) format,
+// with edges interpolated as straight lines in the coordinate reference system
+// crs. An empty crs selects the default, OGC:CRS84.
+//
+// The sort order of geometries is undefined, so the writer does not record
+// min/max statistics for these columns; it records the bounding box and set
+// of geometry types of each column chunk in the geospatial statistics instead.
+//
+// https://github.com/apache/parquet-format/blob/master/LogicalTypes.md#geometry
+func Geometry(crs string) Node { return Leaf(&geometryType{CRS: crs}) }
+
+type geometryType format.GeometryType
+
+func (t *geometryType) String() string { return (*format.GeometryType)(t).String() }
+
+func (t *geometryType) Kind() Kind { return byteArrayType{}.Kind() }
+
+func (t *geometryType) Length() int { return byteArrayType{}.Length() }
+
+func (t *geometryType) EstimateSize(n int) int { return byteArrayType{}.EstimateSize(n) }
+
+func (t *geometryType) EstimateNumValues(n int) int { return byteArrayType{}.EstimateNumValues(n) }
+
+func (t *geometryType) Compare(a, b Value) int { return byteArrayType{}.Compare(a, b) }
+
+func (t *geometryType) ColumnOrder() *format.ColumnOrder { return byteArrayType{}.ColumnOrder() }
+
+func (t *geometryType) PhysicalType() *format.Type { return byteArrayType{}.PhysicalType() }
+
+func (t *geometryType) LogicalType() *format.LogicalType {
+	return &format.LogicalType{Geometry: (*format.GeometryType)(t)}
+}
+
+func (t *geometryType) ConvertedType() *deprecated.ConvertedType { return nil }
+
+func (t *geometryType) NewColumnIndexer(sizeLimit int) ColumnIndexer {
+	return byteArrayType{}.NewColumnIndexer(sizeLimit)
+}
+
+func (t *geometryType) NewDictionary(columnIndex, numValues int, data encoding.Values) Dictionary {
+	return byteArrayType{}.NewDictionary(columnIndex, numValues, data)
+}
+
+func (t *geometryType) NewColumnBuffer(columnIndex, numValues int) ColumnBuffer {
+	return byteArrayType{}.NewColumnBuffer(columnIndex, numValues)
+}
+
+func (t *geometryType) NewPage(columnIndex, numValues int, data encoding.Values) Page {
+	return byteArrayType{}.NewPage(columnIndex, numValues, data)
+}
+
+func (t *geometryType) NewValues(values []byte, offsets []uint32) encoding.Values {
+	return byteArrayType{}.NewValues(values, offsets)
+}
+
+func (t *geometryType) Encode(dst []byte, src encoding.Values, enc encoding.Encoding) ([]byte, error) {
+	return byteArrayType{}.Encode(dst, src, enc)
+}
+
+func (t *geometryType) Decode(dst encoding.Values, src []byte, enc encoding.Encoding) (encoding.Values, error) {
+	return byteArrayType{}.Decode(dst, src, enc)
+}
+
+func (t *geometryType) EstimateDecodeSize(numValues int, src []byte, enc encoding.Encoding) int {
+	return byteArrayType{}.EstimateDecodeSize(numValues, src, enc)
+}
+
+func (t *geometryType) AssignValue(dst reflect.Value, src Value) error {
+	return byteArrayType{}.AssignValue(dst, src)
+}
+
+func (t *geometryType) ConvertValue(val Value, typ Type) (Value, error) {
+	switch typ.(type) {
+	case *byteArrayType, *geometryType:
+		return val, nil
+	default:
+		return val, invalidConversion(val, "GEOMETRY", typ.String())
+	}
+}
+
+// Geography constructs a leaf node of GEOGRAPHY logical type.
+//
+// Values are geospatial features encoded in the Well-Known Binary (WKB) format,
+// with edges interpolated on the ellipsoid of the geographic coordinate
+// reference system crs using the given algorithm. An empty crs selects the
+// default, OGC:CRS84.
+//
+// Like GEOMETRY columns, GEOGRAPHY columns have no min/max statistics, the
+// writer records the set of geometry types and the bounding box of each column
+// chunk in the geospatial statistics instead. The bounding box contains the
+// edges of the values and wraps around the antimeridian (XMin > XMax) when it
+// is the smallest range of longitudes covering them.
+//
+// https://github.com/apache/parquet-format/blob/master/LogicalTypes.md#geography
+func Geography(crs string, algorithm format.EdgeInterpolationAlgorithm) Node {
+	return Leaf(&geographyType{CRS: crs, Algorithm: algorithm})
+}
+
+type geographyType format.GeographyType
+
+func (t *geographyType) String() string { return (*format.GeographyType)(t).String() }
+
+func (t *geographyType) Kind() Kind { return byteArrayType{}.Kind() }
+
+func (t *geographyType) Length() int { return byteArrayType{}.Length() }
+
+func (t *geographyType) EstimateSize(n int) int { return byteArrayType{}.EstimateSize(n) }
+
+func (t *geographyType) EstimateNumValues(n int) int { return byteArrayType{}.EstimateNumValues(n) }
+
+func (t *geographyType) Compare(a, b Value) int { return byteArrayType{}.Compare(a, b) }
+
+func (t *geographyType) ColumnOrder() *format.ColumnOrder { return byteArrayType{}.ColumnOrder() }
+
+func (t *geographyType) PhysicalType() *format.Type { return byteArrayType{}.PhysicalType() }
+
+func (t *geographyType) LogicalType() *format.LogicalType {
+	return &format.LogicalType{Geography: (*format.GeographyType)(t)}
+}
+
+func (t *geographyType) ConvertedType() *deprecated.ConvertedType { return nil }
+
+func (t *geographyType) NewColumnIndexer(sizeLimit int) ColumnIndexer {
+	return byteArrayType{}.NewColumnIndexer(sizeLimit)
+}
+
+func (t *geographyType) NewDictionary(columnIndex, numValues int, data encoding.Values) Dictionary {
+	return byteArrayType{}.NewDictionary(columnIndex, numValues, data)
+}
+
+func (t *geographyType) NewColumnBuffer(columnIndex, numValues int) ColumnBuffer {
+	return byteArrayType{}.NewColumnBuffer(columnIndex, numValues)
+}
+
+func (t *geographyType) NewPage(columnIndex, numValues int, data encoding.Values) Page {
+	return byteArrayType{}.NewPage(columnIndex, numValues, data)
+}
+
+func (t *geographyType) NewValues(values []byte, offsets []uint32) encoding.Values {
+	return byteArrayType{}.NewValues(values, offsets)
+}
+
+func (t *geographyType) Encode(dst []byte, src encoding.Values, enc encoding.Encoding) ([]byte, error) {
+	return byteArrayType{}.Encode(dst, src, enc)
+}
+
+func (t *geographyType) Decode(dst encoding.Values, src []byte, enc encoding.Encoding) (encoding.Values, error) {
+	return byteArrayType{}.Decode(dst, src, enc)
+}
+
+func (t *geographyType) EstimateDecodeSize(numValues int, src []byte, enc encoding.Encoding) int {
+	return byteArrayType{}.EstimateDecodeSize(numValues, src, enc)
+}
+
+func (t *geographyType) AssignValue(dst reflect.Value, src Value) error {
+	return byteArrayType{}.AssignValue(dst, src)
+}
+
+func (t *geographyType) ConvertValue(val Value, typ Type) (Value, error) {
+	switch typ.(type) {
+	case *byteArrayType, *geographyType:
+		return val, nil
+	default:
+		return val, invalidConversion(val, "GEOGRAPHY", typ.String())
+	}
+}
+
 // Date constructs a leaf node of DATE logical type.
 //
 // https://github.com/apache/parquet-format/blob/master/LogicalTypes.md#date
@@ -2192,6 +2551,165 @@ func (t *mapType) ConvertValue(Value, Type) (Value, error) {
 	panic("cannot convert value to a parquet MAP type")
 }
 
+// Variant constructs a node of VARIANT logical type.
+//
+// Variant nodes store semi-structured values of any shape using the parquet
+// variant binary encoding. The node is a group made of a metadata and a value
+// column holding the two parts of the encoded variant (see the variant
+// package).
+//
+// When constructing schemas from Go types, the "variant" struct tag may be
+// used to declare variant fields, which are usually of type any or
+// map[string]any. Go values are converted to and from variants as described
+// in variant.Marshal and variant.Unmarshal.
+//
+// https://github.com/apache/parquet-format/blob/master/VariantEncoding.md
+func Variant() Node {
+	return variantNode{Group{
+		"metadata": Required(Leaf(ByteArrayType)),
+		"value":    Required(Leaf(ByteArrayType)),
+	}}
+}
+
+// ShreddedVariant constructs a node of VARIANT logical type where values
+// matching the typed node passed as argument are stored in typed columns.
+//
+// The typed node is either a leaf, in which case variant values of the same
+// type are stored in the typed column, or a group, in which case each field
+// of variant objects declared in the group is shredded recursively. Values
+// which do not match the typed node, and object fields which are absent from
+// it, are stored in variant encoded form alongside the typed columns, which
+// allows the original values to be fully reconstructed when reading.
+//
+// Shredding allows reads of specific paths of the variant values to only load
+// the typed columns; see VariantFieldPath to locate those columns.
+//
+// Typed leaf nodes may be booleans, signed integers, floats, doubles, strings,
+// byte arrays, timestamps or UUIDs. The function panics if the typed node
+// contains other types, or lists.
+//
+// https://github.com/apache/parquet-format/blob/master/VariantShredding.md
+func ShreddedVariant(typed Node) Node {
+	return variantNode{Group{
+		"metadata":    Required(Leaf(ByteArrayType)),
+		"value":       Optional(Leaf(ByteArrayType)),
+		"typed_value": shreddedTypedValueOf(typed),
+	}}
+}
+
+func shreddedTypedValueOf(typed Node) Node {
+	if typed.Repeated() || isList(typed) || isMap(typed) {
+		panic("cannot shred variant values into repeated parquet nodes")
+	}
+	if typed.Leaf() {
+		if !variantTypedColumnSupported(typed.Type()) {
+			panic("cannot shred variant values into parquet columns of type " + typed.Type().String())
+		}
+		return Optional(typed)
+	}
+	fields := typed.Fields()
+	group := make(Group, len(fields))
+	for _, field := range fields {
+		group[field.Name()] = Required(Group{
+			"value":       Optional(Leaf(ByteArrayType)),
+			"typed_value": shreddedTypedValueOf(field),
+		})
+	}
+	return Optional(group)
+}
+
+// VariantFieldPath returns the path of the typed column storing the values
+// at the given path of objects shredded in a variant column. The returned path
+// is relative to the variant column.
+//
+// For example, the typed values of the "user.id" field of a shredded variant
+// column named "payload" may be looked up with:
+//
+//	schema.Lookup(append([]string{"payload"}, parquet.VariantFieldPath("user", "id")...)...)
+//
+// The path may also be passed to the Projection option to read the values of
+// the field without reading the other columns of the variant.
+func VariantFieldPath(path ...string) []string {
+	columnPath := make([]string, 0, 2*len(path)+1)
+	columnPath = append(columnPath, "typed_value")
+	for _, name := range path {
+		columnPath = append(columnPath, name, "typed_value")
+	}
+	return columnPath
+}
+
+type variantNode struct{ Group }
+
+func (variantNode) Type() Type { return &variantType{} }
+
+func (variantNode) GoType() reflect.Type { return reflect.TypeOf((*any)(nil)).Elem() }
+
+type variantType format.VariantType
+
+func (t *variantType) String() string { return (*format.VariantType)(t).String() }
+
+func (t *variantType) Kind() Kind { panic("cannot call Kind on parquet VARIANT type") }
+
+func (t *variantType) Length() int { return 0 }
+
+func (t *variantType) EstimateSize(int) int { return 0 }
+
+func (t *variantType) EstimateNumValues(int) int { return 0 }
+
+func (t *variantType) Compare(Value, Value) int {
+	panic("cannot compare values on parquet VARIANT type")
+}
+
+func (t *variantType) ColumnOrder() *format.ColumnOrder { return nil }
+
+func (t *variantType) PhysicalType() *format.Type { return nil }
+
+func (t *variantType) LogicalType() *format.LogicalType {
+	return &format.LogicalType{Variant: (*format.VariantType)(t)}
+}
+
+func (t *variantType) ConvertedType() *deprecated.ConvertedType { return nil }
+
+func (t *variantType) NewColumnIndexer(int) ColumnIndexer {
+	panic("create create column indexer from parquet VARIANT type")
+}
+
+func (t *variantType) NewDictionary(int, int, encoding.Values) Dictionary {
+	panic("cannot create dictionary from parquet VARIANT type")
+}
+
+func (t *variantType) NewColumnBuffer(int, int) ColumnBuffer {
+	panic("cannot create column buffer from parquet VARIANT type")
+}
+
+func (t *variantType) NewPage(int, int, encoding.Values) Page {
+	panic("cannot create page from parquet VARIANT type")
+}
+
+func (t *variantType) NewValues(values []byte, _ []uint32) encoding.Values {
+	panic("cannot create values from parquet VARIANT type")
+}
+
+func (t *variantType) Encode(_ []byte, _ encoding.Values, _ encoding.Encoding) ([]byte, error) {
+	panic("cannot encode parquet VARIANT type")
+}
+
+func (t *variantType) Decode(_ encoding.Values, _ []byte, _ encoding.Encoding) (encoding.Values, error) {
+	panic("cannot decode parquet VARIANT type")
+}
+
+func (t *variantType) EstimateDecodeSize(_ int, _ []byte, _ encoding.Encoding) int {
+	panic("cannot estimate decode size of parquet VARIANT type")
+}
+
+func (t *variantType) AssignValue(reflect.Value, Value) error {
+	panic("cannot assign value to a parquet VARIANT type")
+}
+
+func (t *variantType) ConvertValue(Value, Type) (Value, error) {
+	panic("cannot convert value to a parquet VARIANT type")
+}
+
 type nullType format.NullType
 
 func (t *nullType) String() string { return (*format.NullType)(t).String() }
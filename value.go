@@ -184,6 +184,8 @@ func ValueOf(v interface{}) Value {
 		return makeValueBytes(FixedLenByteArray, value[:])
 	case deprecated.Int96:
 		return makeValueInt96(value)
+	case deprecated.Duration:
+		return makeValueInterval(value)
 	case time.Time:
 		k = Int64
 	}
@@ -256,6 +258,10 @@ func ByteArrayValue(value []byte) Value { return makeValueBytes(ByteArray, value
 // slice passed as argument.
 func FixedLenByteArrayValue(value []byte) Value { return makeValueBytes(FixedLenByteArray, value) }
 
+// IntervalValue constructs a FIXED_LEN_BYTE_ARRAY parquet value holding the
+// 12 bytes representation of the INTERVAL passed as argument.
+func IntervalValue(value deprecated.Duration) Value { return makeValueInterval(value) }
+
 func makeValue(k Kind, lt *format.LogicalType, v reflect.Value) Value {
 	if v.Kind() == reflect.Interface {
 		if v.IsNil() {
@@ -284,6 +290,8 @@ func makeValue(k Kind, lt *format.LogicalType, v reflect.Value) Value {
 			val = t.UnixNano()
 		}
 		return makeValueInt64(val)
+	case reflect.TypeOf(deprecated.Duration{}):
+		return makeValueInterval(v.Interface().(deprecated.Duration))
 	}
 
 	switch k {
@@ -338,6 +346,10 @@ func makeValue(k Kind, lt *format.LogicalType, v reflect.Value) Value {
 		switch v.Kind() {
 		case reflect.String: // uuid
 			return makeValueString(k, v.String())
+		case reflect.Float32, reflect.Float64:
+			if lt != nil && lt.Float16 != nil {
+				return makeValueBytes(k, appendFloat16(nil, float32(v.Float())))
+			}
 		case reflect.Array:
 			if v.Type().Elem().Kind() == reflect.Uint8 {
 				return makeValueFixedLenByteArray(v)
@@ -352,6 +364,10 @@ func makeValue(k Kind, lt *format.LogicalType, v reflect.Value) Value {
 	panic("cannot create parquet value of type " + k.String() + " from go value of type " + v.Type().String())
 }
 
+func makeValueInterval(value deprecated.Duration) Value {
+	return makeValueBytes(FixedLenByteArray, value.Append(make([]byte, 0, deprecated.IntervalSize)))
+}
+
 func makeValueKind(kind Kind) Value {
 	return Value{kind: ^int8(kind)}
 }
@@ -571,6 +587,16 @@ func (v Value) Uint32() uint32 { return v.uint32() }
 // Uint64 returns v as a uint64, assuming the underlying type is INT64.
 func (v Value) Uint64() uint64 { return v.uint64() }
 
+// Interval returns v as an interval, assuming the underlying type is a
+// FIXED_LEN_BYTE_ARRAY of the INTERVAL converted type.
+func (v Value) Interval() deprecated.Duration {
+	var val deprecated.Duration
+	if !v.isNull() {
+		val = deprecated.MakeDuration(v.byteArray())
+	}
+	return val
+}
+
 // ByteArray returns v as a []byte, assuming the underlying type is either
 // BYTE_ARRAY or FIXED_LEN_BYTE_ARRAY.
 //
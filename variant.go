@@ -0,0 +1,725 @@
+package parquet
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/parquet-go/parquet-go/sparse"
+	"github.com/parquet-go/parquet-go/variant"
+)
+
+// variantLayout describes how the leaf columns of a VARIANT group map to the
+// parts of the variant values.
+//
+// Column indexes are relative to the first leaf column of the group, and
+// definition levels are relative to the definition level of the group.
+type variantLayout struct {
+	metadata   int16
+	numColumns int16
+	variantValue
+}
+
+// variantValue represents a pair of value and typed_value columns, which can
+// be found at the top level of a VARIANT group and in each field of shredded
+// objects.
+type variantValue struct {
+	// Index of the column holding variant encoded values, or -1 if the
+	// value column does not exist.
+	value int16
+	// Definition level at which values of the value column are not null.
+	valueLevel byte
+	// Layout of the typed_value column, or nil if the values are not shredded.
+	typed *variantTypedValue
+}
+
+type variantTypedValue struct {
+	// Definition level at which the typed_value node is not null.
+	level byte
+	// Index of the first leaf column of the typed_value node.
+	firstColumn int16
+	// Type of values for leaf typed_value nodes, nil for shredded objects.
+	typ Type
+	// Fields of shredded objects.
+	fields []variantField
+}
+
+type variantField struct {
+	name string
+	variantValue
+}
+
+func makeVariantLayout(node Node) (layout variantLayout, err error) {
+	layout.metadata = -1
+	layout.value = -1
+	columnIndex := int16(0)
+
+	for _, field := range node.Fields() {
+		switch field.Name() {
+		case "metadata":
+			if !isVariantBinaryNode(field) || !field.Required() {
+				return layout, fmt.Errorf("variant metadata must be a required binary column")
+			}
+			layout.metadata = columnIndex
+			columnIndex++
+		default:
+			columnIndex, err = layout.variantValue.init(field, columnIndex, 0)
+			if err != nil {
+				return layout, err
+			}
+		}
+	}
+
+	if layout.value < 0 && layout.typed == nil {
+		return layout, fmt.Errorf("variant group has no value or typed_value columns")
+	}
+	// The metadata is only needed to decode the variant values, it may be
+	// omitted when projecting the typed columns of shredded variants.
+	if layout.metadata < 0 && layout.hasValueColumns() {
+		return layout, fmt.Errorf("variant group has no metadata column")
+	}
+	layout.numColumns = columnIndex
+	return layout, nil
+}
+
+func (v *variantValue) init(field Field, columnIndex int16, level byte) (int16, error) {
+	if field.Repeated() {
+		return columnIndex, fmt.Errorf("variant column %q must not be repeated", field.Name())
+	}
+
+	switch field.Name() {
+	case "value":
+		if !isVariantBinaryNode(field) {
+			return columnIndex, fmt.Errorf("variant value must be a binary column")
+		}
+		v.value = columnIndex
+		v.valueLevel = level
+		if field.Optional() {
+			v.valueLevel++
+		}
+		return columnIndex + 1, nil
+
+	case "typed_value":
+		t := &variantTypedValue{level: level, firstColumn: columnIndex}
+		if field.Optional() {
+			t.level++
+		}
+		v.typed = t
+
+		if field.Leaf() {
+			if !variantTypedColumnSupported(field.Type()) {
+				return columnIndex, fmt.Errorf("shredded variant values of type %s are not supported", field.Type())
+			}
+			t.typ = field.Type()
+			return columnIndex + 1, nil
+		}
+
+		if isList(field) || isMap(field) {
+			return columnIndex, fmt.Errorf("shredded variant arrays are not supported")
+		}
+
+		fields := field.Fields()
+		if len(fields) == 0 {
+			return columnIndex, fmt.Errorf("shredded variant objects must have at least one field")
+		}
+
+		t.fields = make([]variantField, len(fields))
+		for i, f := range fields {
+			if f.Leaf() || !f.Required() {
+				return columnIndex, fmt.Errorf("shredded variant object field %q must be a required group", f.Name())
+			}
+			t.fields[i] = variantField{name: f.Name(), variantValue: variantValue{value: -1}}
+			for _, g := range f.Fields() {
+				var err error
+				columnIndex, err = t.fields[i].init(g, columnIndex, t.level)
+				if err != nil {
+					return columnIndex, fmt.Errorf("%s: %w", f.Name(), err)
+				}
+			}
+		}
+		return columnIndex, nil
+
+	default:
+		return columnIndex, fmt.Errorf("unexpected field %q in variant group", field.Name())
+	}
+}
+
+// hasValueColumns returns true if v or the fields of its shredded objects have
+// value columns.
+func (v *variantValue) hasValueColumns() bool {
+	if v.value >= 0 {
+		return true
+	}
+	if v.typed != nil {
+		for i := range v.typed.fields {
+			if v.typed.fields[i].hasValueColumns() {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func isVariantBinaryNode(node Node) bool {
+	return node.Leaf() && node.Type().Kind() == ByteArray
+}
+
+func variantTypedColumnSupported(typ Type) bool {
+	if lt := typ.LogicalType(); lt != nil {
+		switch {
+		case lt.UTF8 != nil, lt.Timestamp != nil, lt.UUID != nil:
+			return true
+		case lt.Integer != nil:
+			return lt.Integer.IsSigned
+		default:
+			return false
+		}
+	}
+	switch typ.Kind() {
+	case Boolean, Int32, Int64, Float, Double, ByteArray:
+		return true
+	default:
+		return false
+	}
+}
+
+// makeVariantTypedValue converts v to a value of the typed column type,
+// returning false if the Go value does not have a representation in the
+// column, in which case it must be stored in the variant value column.
+func makeVariantTypedValue(typ Type, v reflect.Value) (Value, bool) {
+	if lt := typ.LogicalType(); lt != nil {
+		switch {
+		case lt.UTF8 != nil:
+			if v.Kind() == reflect.String {
+				return makeValueString(ByteArray, v.String()), true
+			}
+			return Value{}, false
+		case lt.Timestamp != nil:
+			if v.Type() == reflect.TypeOf(time.Time{}) {
+				return makeValue(Int64, lt, v), true
+			}
+			return Value{}, false
+		case lt.UUID != nil:
+			if v.Type() == reflect.TypeOf(uuid.UUID{}) {
+				u := v.Interface().(uuid.UUID)
+				return makeValueBytes(FixedLenByteArray, u[:]), true
+			}
+			return Value{}, false
+		}
+	}
+
+	switch kind := typ.Kind(); kind {
+	case Boolean:
+		if v.Kind() == reflect.Bool {
+			return makeValueBoolean(v.Bool()), true
+		}
+
+	case Int32, Int64:
+		var i int64
+		switch v.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			i = v.Int()
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			u := v.Uint()
+			if u > math.MaxInt64 {
+				return Value{}, false
+			}
+			i = int64(u)
+		default:
+			return Value{}, false
+		}
+		bitWidth := 64
+		if lt := typ.LogicalType(); lt != nil && lt.Integer != nil {
+			bitWidth = int(lt.Integer.BitWidth)
+		} else if kind == Int32 {
+			bitWidth = 32
+		}
+		if bitWidth < 64 && (i < -(1<<(bitWidth-1)) || i >= 1<<(bitWidth-1)) {
+			return Value{}, false
+		}
+		if kind == Int32 {
+			return makeValueInt32(int32(i)), true
+		}
+		return makeValueInt64(i), true
+
+	case Float:
+		if v.Kind() == reflect.Float32 {
+			return makeValueFloat(float32(v.Float())), true
+		}
+
+	case Double:
+		if v.Kind() == reflect.Float64 {
+			return makeValueDouble(v.Float()), true
+		}
+
+	case ByteArray:
+		if v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.Uint8 && !v.IsNil() {
+			return makeValueBytes(ByteArray, v.Bytes()), true
+		}
+	}
+
+	return Value{}, false
+}
+
+// variantTypedValueOf converts a value of a typed column to the Go value that
+// variant.Unmarshal would have produced for the equivalent variant value.
+func variantTypedValueOf(typ Type, v Value) any {
+	if lt := typ.LogicalType(); lt != nil {
+		switch {
+		case lt.UTF8 != nil:
+			return string(v.byteArray())
+		case lt.Timestamp != nil:
+			switch unit := lt.Timestamp.Unit; {
+			case unit.Millis != nil:
+				return time.UnixMilli(v.int64()).UTC()
+			case unit.Micros != nil:
+				return time.UnixMicro(v.int64()).UTC()
+			default:
+				return time.Unix(0, v.int64()).UTC()
+			}
+		case lt.UUID != nil:
+			return uuid.UUID(v.byteArray())
+		}
+	}
+
+	switch typ.Kind() {
+	case Boolean:
+		return v.boolean()
+	case Int32:
+		return int64(v.int32())
+	case Int64:
+		return v.int64()
+	case Float:
+		return v.float()
+	case Double:
+		return v.double()
+	default:
+		return copyBytes(v.byteArray())
+	}
+}
+
+// variantWriter holds the state needed to deconstruct a Go value into the
+// leaf columns of a VARIANT group.
+type variantWriter struct {
+	*variantLayout
+	columns         [][]Value
+	firstColumn     int16
+	repetitionLevel byte
+	definitionLevel byte
+	encoder         variant.Encoder
+}
+
+func (w *variantWriter) write(v reflect.Value) error {
+	if err := w.writeValue(&w.variantValue, 0, v, true); err != nil {
+		return err
+	}
+	if w.metadata >= 0 {
+		w.writeColumn(w.metadata, makeValueBytes(ByteArray, w.encoder.Metadata(nil)), 0)
+	}
+	return nil
+}
+
+func (w *variantWriter) writeNulls(level byte) {
+	for columnIndex := int16(0); columnIndex < w.numColumns; columnIndex++ {
+		w.writeColumn(columnIndex, Value{}, level)
+	}
+}
+
+func (w *variantWriter) writeColumn(columnIndex int16, v Value, level byte) {
+	columnIndex += w.firstColumn
+	v.repetitionLevel = w.repetitionLevel
+	v.definitionLevel = w.definitionLevel + level
+	v.columnIndex = ^columnIndex
+	w.columns[columnIndex] = append(w.columns[columnIndex], v)
+}
+
+func (w *variantWriter) writeVariantValue(column int16, level byte, v reflect.Value) error {
+	var x any
+	if v.IsValid() {
+		x = v.Interface()
+	}
+	b, err := w.encoder.Encode(nil, x)
+	if err != nil {
+		return err
+	}
+	w.writeColumn(column, makeValueBytes(ByteArray, b), level)
+	return nil
+}
+
+func (w *variantWriter) writeTypedNulls(t *variantTypedValue, level byte) {
+	if t == nil {
+		return
+	}
+	if t.typ != nil {
+		w.writeColumn(t.firstColumn, Value{}, level)
+		return
+	}
+	for i := range t.fields {
+		w.writeValueNulls(&t.fields[i].variantValue, level)
+	}
+}
+
+func (w *variantWriter) writeValueNulls(v *variantValue, level byte) {
+	if v.value >= 0 {
+		w.writeColumn(v.value, Value{}, level)
+	}
+	w.writeTypedNulls(v.typed, level)
+}
+
+// writeValue writes the Go value v to the columns of the value and
+// typed_value pair. The level is the definition level of the parent node of
+// the pair, and exists is false if v is a field missing from its parent
+// object.
+func (w *variantWriter) writeValue(p *variantValue, level byte, v reflect.Value, exists bool) error {
+	if !exists {
+		w.writeValueNulls(p, level)
+		return nil
+	}
+
+	for v.IsValid() && (v.Kind() == reflect.Interface || v.Kind() == reflect.Pointer) {
+		if v.IsNil() {
+			v = reflect.Value{}
+		} else {
+			v = v.Elem()
+		}
+	}
+
+	// The value column may only be omitted when it is nullable.
+	canShred := p.typed != nil && v.IsValid() && (p.value < 0 || p.valueLevel > level)
+
+	if canShred && p.typed.typ != nil {
+		if typed, ok := makeVariantTypedValue(p.typed.typ, v); ok {
+			if p.value >= 0 {
+				w.writeColumn(p.value, Value{}, level)
+			}
+			w.writeColumn(p.typed.firstColumn, typed, p.typed.level)
+			return nil
+		}
+	}
+
+	if canShred && p.typed.fields != nil && v.Kind() == reflect.Map && v.Type().Key().Kind() == reflect.String && !v.IsNil() {
+		return w.writeObject(p, level, v)
+	}
+
+	if p.value < 0 {
+		return fmt.Errorf("cannot write variant value of type %s to typed column without a value column", v.Type())
+	}
+	if err := w.writeVariantValue(p.value, p.valueLevel, v); err != nil {
+		return err
+	}
+	w.writeTypedNulls(p.typed, level)
+	return nil
+}
+
+func (w *variantWriter) writeObject(p *variantValue, level byte, object reflect.Value) error {
+	t := p.typed
+	shredded := make(map[string]struct{}, len(t.fields))
+	for i := range t.fields {
+		shredded[t.fields[i].name] = struct{}{}
+	}
+
+	residual := map[string]any(nil)
+	iter := object.MapRange()
+	for iter.Next() {
+		key := iter.Key().String()
+		if _, ok := shredded[key]; !ok {
+			if residual == nil {
+				residual = make(map[string]any)
+			}
+			residual[key] = iter.Value().Interface()
+		}
+	}
+
+	if residual == nil {
+		if p.value >= 0 {
+			w.writeColumn(p.value, Value{}, level)
+		}
+	} else {
+		if p.value < 0 {
+			return fmt.Errorf("cannot write variant object with fields missing from the shredded typed columns without a value column")
+		}
+		if err := w.writeVariantValue(p.value, p.valueLevel, reflect.ValueOf(residual)); err != nil {
+			return err
+		}
+	}
+
+	for i := range t.fields {
+		f := &t.fields[i]
+		v := object.MapIndex(reflect.ValueOf(f.name).Convert(object.Type().Key()))
+		if err := w.writeValue(&f.variantValue, t.level, v, v.IsValid()); err != nil {
+			return fmt.Errorf("%s: %w", f.name, err)
+		}
+	}
+	return nil
+}
+
+// variantReader holds the state needed to reconstruct Go values from the leaf
+// columns of a VARIANT group.
+type variantReader struct {
+	*variantLayout
+	columns         [][]Value
+	definitionLevel byte
+	dictionary      variant.Metadata
+}
+
+func (r *variantReader) read() (any, error) {
+	if r.metadata >= 0 {
+		m, err := variant.ParseMetadata(r.columns[r.metadata][0].byteArray())
+		if err != nil {
+			return nil, err
+		}
+		r.dictionary = m
+	}
+	v, _, err := r.readValue(&r.variantValue)
+	return v, err
+}
+
+func (r *variantReader) isNull(columnIndex int16, level byte) bool {
+	return r.columns[columnIndex][0].definitionLevel < r.definitionLevel+level
+}
+
+// readValue reconstructs the Go value of a value and typed_value pair,
+// returning false if neither of the columns hold a value, which indicates
+// that the field is missing from its parent object.
+func (r *variantReader) readValue(p *variantValue) (any, bool, error) {
+	if t := p.typed; t != nil && !r.isNull(t.firstColumn, t.level) {
+		if t.typ != nil {
+			return variantTypedValueOf(t.typ, r.columns[t.firstColumn][0]), true, nil
+		}
+
+		object := make(map[string]any, len(t.fields))
+		if p.value >= 0 && !r.isNull(p.value, p.valueLevel) {
+			v, err := r.dictionary.Decode(r.columns[p.value][0].byteArray())
+			if err != nil {
+				return nil, false, err
+			}
+			residual, ok := v.(map[string]any)
+			if !ok {
+				return nil, false, fmt.Errorf("shredded variant object holds a value of type %T", v)
+			}
+			for k, v := range residual {
+				object[k] = v
+			}
+		}
+
+		for i := range t.fields {
+			f := &t.fields[i]
+			v, ok, err := r.readValue(&f.variantValue)
+			if err != nil {
+				return nil, false, fmt.Errorf("%s: %w", f.name, err)
+			}
+			if ok {
+				object[f.name] = v
+			}
+		}
+		return object, true, nil
+	}
+
+	if p.value >= 0 && !r.isNull(p.value, p.valueLevel) {
+		v, err := r.dictionary.Decode(r.columns[p.value][0].byteArray())
+		return v, err == nil, err
+	}
+	return nil, false, nil
+}
+
+func assignVariantValue(dst reflect.Value, v any) error {
+	if v == nil {
+		dst.Set(reflect.Zero(dst.Type()))
+		return nil
+	}
+	src := reflect.ValueOf(v)
+	switch {
+	case src.Type().AssignableTo(dst.Type()):
+		dst.Set(src)
+	case src.Type().ConvertibleTo(dst.Type()) && src.Kind() != reflect.Map && src.Kind() != reflect.Slice:
+		dst.Set(src.Convert(dst.Type()))
+	default:
+		return fmt.Errorf("cannot assign variant value of type %T to %s", v, dst.Type())
+	}
+	return nil
+}
+
+func writeVariant(layout *variantLayout, columns [][]Value, firstColumn int16, levels levels, value reflect.Value) error {
+	w := &variantWriter{
+		variantLayout:   layout,
+		columns:         columns,
+		firstColumn:     firstColumn,
+		repetitionLevel: levels.repetitionLevel,
+		definitionLevel: levels.definitionLevel,
+	}
+	if !value.IsValid() {
+		w.writeNulls(0)
+		return nil
+	}
+	return w.write(value)
+}
+
+//go:noinline
+func deconstructFuncOfVariant(columnIndex int16, node Node) (int16, deconstructFunc) {
+	layout, err := makeVariantLayout(node)
+	if err != nil {
+		panic(err)
+	}
+	return columnIndex + layout.numColumns, func(columns [][]Value, levels levels, value reflect.Value) {
+		if err := writeVariant(&layout, columns, columnIndex, levels, value); err != nil {
+			panic(err)
+		}
+	}
+}
+
+//go:noinline
+func reconstructFuncOfVariant(columnIndex int16, node Node) (int16, reconstructFunc) {
+	layout, err := makeVariantLayout(node)
+	if err != nil {
+		// The error is deferred to the reconstruction of values so schemas
+		// of files using unsupported shredding layouts can still be opened.
+		return numLeafColumnsOf(node) + columnIndex, func(reflect.Value, levels, [][]Value) error {
+			return err
+		}
+	}
+	return columnIndex + layout.numColumns, func(value reflect.Value, levels levels, columns [][]Value) error {
+		r := &variantReader{
+			variantLayout:   &layout,
+			columns:         columns,
+			definitionLevel: levels.definitionLevel,
+		}
+		v, err := r.read()
+		if err != nil {
+			return err
+		}
+		return assignVariantValue(value, v)
+	}
+}
+
+func writeRowsFuncOfVariant(t reflect.Type, schema *Schema, path columnPath) writeRowsFunc {
+	node := lookupColumnPath(schema, path)
+	layout, err := makeVariantLayout(node)
+	if err != nil {
+		panic(err)
+	}
+
+	firstLeafPath := path
+	for leaf := node; !leaf.Leaf(); leaf = leaf.Fields()[0] {
+		firstLeafPath = firstLeafPath.append(leaf.Fields()[0].Name())
+	}
+	firstColumn := schema.mapping.lookup(firstLeafPath).columnIndex
+
+	return func(columns []ColumnBuffer, rows sparse.Array, levels columnLevels) error {
+		values := make([][]Value, firstColumn+layout.numColumns)
+		lvl := makeLevels(levels)
+
+		if rows.Len() == 0 {
+			if err := writeVariant(&layout, values, firstColumn, lvl, reflect.Value{}); err != nil {
+				return err
+			}
+		}
+
+		for i := 0; i < rows.Len(); i++ {
+			v := reflect.NewAt(t, rows.Index(i)).Elem()
+			if err := writeVariant(&layout, values, firstColumn, lvl, v); err != nil {
+				return err
+			}
+		}
+
+		for columnIndex := firstColumn; columnIndex < firstColumn+layout.numColumns; columnIndex++ {
+			if _, err := columns[columnIndex].WriteValues(values[columnIndex]); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+func makeLevels(l columnLevels) levels {
+	return levels{
+		repetitionDepth: l.repetitionDepth,
+		repetitionLevel: l.repetitionLevel,
+		definitionLevel: l.definitionLevel,
+	}
+}
+
+// schemaWithShreddedVariantsOf returns a schema where the VARIANT groups of
+// struct fields are replaced by the matching groups of the source schema when
+// their layouts differ, so that values written with shredding can be fully
+// reconstructed into Go values declaring "variant" fields.
+func schemaWithShreddedVariantsOf(schema *Schema, source Node) *Schema {
+	root, replaced := nodeWithShreddedVariantsOf(schema.root, source)
+	if !replaced {
+		return schema
+	}
+	return NewSchema(schema.name, root)
+}
+
+func nodeWithShreddedVariantsOf(node, source Node) (Node, bool) {
+	switch n := node.(type) {
+	case *structNode:
+		var fields []structField
+		for i := range n.fields {
+			f := &n.fields[i]
+			s := fieldByName(source, f.name)
+			if s == nil || s.Leaf() {
+				continue
+			}
+			var replacement Node
+			var replaced bool
+			if isVariant(f.Node) && isVariant(s) {
+				replacement, replaced = s, !nodesAreEqual(f.Node, s)
+			} else {
+				replacement, replaced = nodeWithShreddedVariantsOf(f.Node, s)
+			}
+			if replaced {
+				if fields == nil {
+					fields = make([]structField, len(n.fields))
+					copy(fields, n.fields)
+				}
+				fields[i].Node = replacement
+			}
+		}
+		if fields != nil {
+			return &structNode{gotype: n.gotype, fields: fields}, true
+		}
+	case *optionalNode:
+		if inner, replaced := nodeWithShreddedVariantsOf(n.Node, source); replaced {
+			return &optionalNode{inner}, true
+		}
+	case *repeatedNode:
+		if inner, replaced := nodeWithShreddedVariantsOf(n.Node, source); replaced {
+			return &repeatedNode{inner}, true
+		}
+	case *requiredNode:
+		if inner, replaced := nodeWithShreddedVariantsOf(n.Node, source); replaced {
+			return &requiredNode{inner}, true
+		}
+	}
+	return node, false
+}
+
+// projectVariantFields returns the fields retained by the projection of a
+// VARIANT group, adding the metadata column when some of the retained columns
+// hold variant values, since it is needed to decode them. Projections which
+// only retain typed columns of shredded values are read without the metadata.
+func projectVariantFields(node Node, retained []Field) []Field {
+	if !slices.ContainsFunc(retained, hasVariantValueColumn) || fieldByName(node, "metadata") == nil {
+		return retained
+	}
+	fields := make([]Field, 0, len(retained)+1)
+	for _, field := range node.Fields() {
+		switch {
+		case field.Name() == "metadata":
+			fields = append(fields, field)
+		case len(retained) > 0 && retained[0].Name() == field.Name():
+			fields, retained = append(fields, retained[0]), retained[1:]
+		}
+	}
+	return fields
+}
+
+func hasVariantValueColumn(field Field) bool {
+	if field.Leaf() {
+		return field.Name() == "value"
+	}
+	return slices.ContainsFunc(field.Fields(), hasVariantValueColumn)
+}
@@ -0,0 +1,339 @@
+package variant
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Encoder encodes Go values to variant values.
+//
+// The encoder accumulates the field names of objects in a dictionary shared by
+// all values it encodes, which is serialized by calling Metadata. This allows
+// multiple values, for example the pieces of a shredded variant, to reference
+// the same metadata.
+//
+// The zero value is a valid encoder with an empty dictionary.
+type Encoder struct {
+	keys  []string
+	index map[string]int
+}
+
+// Reset clears the dictionary of field names accumulated by the encoder.
+func (e *Encoder) Reset() {
+	e.keys = e.keys[:0]
+	clear(e.index)
+}
+
+// Metadata appends the variant metadata representing the dictionary of field
+// names of all values encoded since the last call to Reset to dst, and returns
+// the extended buffer.
+func (e *Encoder) Metadata(dst []byte) []byte {
+	length := 0
+	for _, key := range e.keys {
+		length += len(key)
+	}
+	offsetSize := uintSize(max(len(e.keys), length))
+
+	header := byte(Version) | byte(offsetSize-1)<<6
+	if sort.SliceIsSorted(e.keys, func(i, j int) bool { return e.keys[i] < e.keys[j] }) {
+		// Keys are unique, so being sorted means being strictly increasing.
+		header |= 0x10
+	}
+
+	dst = append(dst, header)
+	dst = appendUint(dst, len(e.keys), offsetSize)
+	offset := 0
+	for _, key := range e.keys {
+		dst = appendUint(dst, offset, offsetSize)
+		offset += len(key)
+	}
+	dst = appendUint(dst, offset, offsetSize)
+	for _, key := range e.keys {
+		dst = append(dst, key...)
+	}
+	return dst
+}
+
+// Encode appends the variant encoding of v to dst, and returns the extended
+// buffer. See Marshal for details on how Go values are encoded.
+func (e *Encoder) Encode(dst []byte, v any) ([]byte, error) {
+	return e.encode(dst, reflect.ValueOf(v))
+}
+
+func (e *Encoder) fieldID(key string) int {
+	id, ok := e.index[key]
+	if !ok {
+		if e.index == nil {
+			e.index = make(map[string]int)
+		}
+		id = len(e.keys)
+		e.keys = append(e.keys, key)
+		e.index[key] = id
+	}
+	return id
+}
+
+var (
+	timeType    = reflect.TypeOf(time.Time{})
+	uuidType    = reflect.TypeOf(uuid.UUID{})
+	decimalType = reflect.TypeOf(Decimal{})
+)
+
+func (e *Encoder) encode(dst []byte, v reflect.Value) ([]byte, error) {
+	if !v.IsValid() {
+		return appendPrimitiveHeader(dst, primitiveNull), nil
+	}
+
+	switch v.Type() {
+	case timeType:
+		return appendTime(dst, v.Interface().(time.Time)), nil
+	case uuidType:
+		u := v.Interface().(uuid.UUID)
+		return append(appendPrimitiveHeader(dst, primitiveUUID), u[:]...), nil
+	case decimalType:
+		return appendDecimal(dst, v.Interface().(Decimal))
+	}
+
+	switch v.Kind() {
+	case reflect.Interface, reflect.Pointer:
+		if v.IsNil() {
+			return appendPrimitiveHeader(dst, primitiveNull), nil
+		}
+		return e.encode(dst, v.Elem())
+
+	case reflect.Bool:
+		if v.Bool() {
+			return appendPrimitiveHeader(dst, primitiveTrue), nil
+		}
+		return appendPrimitiveHeader(dst, primitiveFalse), nil
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return appendInt(dst, v.Int()), nil
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		u := v.Uint()
+		if u > math.MaxInt64 {
+			return dst, fmt.Errorf("%w: integer %d overflows the variant int64 type", ErrUnsupportedType, u)
+		}
+		return appendInt(dst, int64(u)), nil
+
+	case reflect.Float32:
+		dst = appendPrimitiveHeader(dst, primitiveFloat)
+		return binary.LittleEndian.AppendUint32(dst, math.Float32bits(float32(v.Float()))), nil
+
+	case reflect.Float64:
+		dst = appendPrimitiveHeader(dst, primitiveDouble)
+		return binary.LittleEndian.AppendUint64(dst, math.Float64bits(v.Float())), nil
+
+	case reflect.String:
+		return appendString(dst, v.String()), nil
+
+	case reflect.Slice:
+		if v.IsNil() {
+			return appendPrimitiveHeader(dst, primitiveNull), nil
+		}
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			b := v.Bytes()
+			dst = appendPrimitiveHeader(dst, primitiveBinary)
+			dst = binary.LittleEndian.AppendUint32(dst, uint32(len(b)))
+			return append(dst, b...), nil
+		}
+		return e.encodeArray(dst, v)
+
+	case reflect.Array:
+		return e.encodeArray(dst, v)
+
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return dst, fmt.Errorf("%w: map keys must be strings but got %s", ErrUnsupportedType, v.Type().Key())
+		}
+		if v.IsNil() {
+			return appendPrimitiveHeader(dst, primitiveNull), nil
+		}
+		return e.encodeObject(dst, v)
+
+	default:
+		return dst, fmt.Errorf("%w: %s", ErrUnsupportedType, v.Type())
+	}
+}
+
+func (e *Encoder) encodeObject(dst []byte, v reflect.Value) ([]byte, error) {
+	keys := v.MapKeys()
+	sort.Slice(keys, func(i, j int) bool { return keys[i].String() < keys[j].String() })
+
+	ids := make([]int, len(keys))
+	offsets := make([]int, len(keys)+1)
+	values := []byte(nil)
+	maxID := 0
+	for i, key := range keys {
+		var err error
+		ids[i] = e.fieldID(key.String())
+		maxID = max(maxID, ids[i])
+		offsets[i] = len(values)
+		values, err = e.encode(values, v.MapIndex(key))
+		if err != nil {
+			return dst, fmt.Errorf("%s: %w", key.String(), err)
+		}
+	}
+	offsets[len(keys)] = len(values)
+
+	idSize := uintSize(maxID)
+	offsetSize := uintSize(len(values))
+	isLarge := len(keys) > math.MaxUint8
+
+	header := byte(basicObject) | byte(offsetSize-1)<<2 | byte(idSize-1)<<4
+	if isLarge {
+		header |= 0x10 << 2
+	}
+	dst = append(dst, header)
+	dst = appendNumElements(dst, len(keys), isLarge)
+	for _, id := range ids {
+		dst = appendUint(dst, id, idSize)
+	}
+	for _, offset := range offsets {
+		dst = appendUint(dst, offset, offsetSize)
+	}
+	return append(dst, values...), nil
+}
+
+func (e *Encoder) encodeArray(dst []byte, v reflect.Value) ([]byte, error) {
+	n := v.Len()
+	offsets := make([]int, n+1)
+	values := []byte(nil)
+	for i := 0; i < n; i++ {
+		var err error
+		offsets[i] = len(values)
+		values, err = e.encode(values, v.Index(i))
+		if err != nil {
+			return dst, fmt.Errorf("%d: %w", i, err)
+		}
+	}
+	offsets[n] = len(values)
+
+	offsetSize := uintSize(len(values))
+	isLarge := n > math.MaxUint8
+
+	header := byte(basicArray) | byte(offsetSize-1)<<2
+	if isLarge {
+		header |= 0x4 << 2
+	}
+	dst = append(dst, header)
+	dst = appendNumElements(dst, n, isLarge)
+	for _, offset := range offsets {
+		dst = appendUint(dst, offset, offsetSize)
+	}
+	return append(dst, values...), nil
+}
+
+func appendPrimitiveHeader(dst []byte, typ int) []byte {
+	return append(dst, byte(typ)<<2|basicPrimitive)
+}
+
+func appendInt(dst []byte, v int64) []byte {
+	switch {
+	case v >= math.MinInt8 && v <= math.MaxInt8:
+		return append(appendPrimitiveHeader(dst, primitiveInt8), byte(v))
+	case v >= math.MinInt16 && v <= math.MaxInt16:
+		return binary.LittleEndian.AppendUint16(appendPrimitiveHeader(dst, primitiveInt16), uint16(v))
+	case v >= math.MinInt32 && v <= math.MaxInt32:
+		return binary.LittleEndian.AppendUint32(appendPrimitiveHeader(dst, primitiveInt32), uint32(v))
+	default:
+		return binary.LittleEndian.AppendUint64(appendPrimitiveHeader(dst, primitiveInt64), uint64(v))
+	}
+}
+
+func appendString(dst []byte, s string) []byte {
+	if len(s) <= maxShortStringLength {
+		dst = append(dst, byte(len(s))<<2|basicShortString)
+	} else {
+		dst = appendPrimitiveHeader(dst, primitiveString)
+		dst = binary.LittleEndian.AppendUint32(dst, uint32(len(s)))
+	}
+	return append(dst, s...)
+}
+
+func appendTime(dst []byte, t time.Time) []byte {
+	if t.Nanosecond()%1000 != 0 {
+		dst = appendPrimitiveHeader(dst, primitiveTimestampNanos)
+		return binary.LittleEndian.AppendUint64(dst, uint64(t.UnixNano()))
+	}
+	dst = appendPrimitiveHeader(dst, primitiveTimestampMicros)
+	return binary.LittleEndian.AppendUint64(dst, uint64(t.UnixMicro()))
+}
+
+const maxDecimalScale = 38
+
+func appendDecimal(dst []byte, d Decimal) ([]byte, error) {
+	if d.Scale < 0 || d.Scale > maxDecimalScale {
+		return dst, fmt.Errorf("%w: decimal scale %d out of range [0:%d]", ErrUnsupportedType, d.Scale, maxDecimalScale)
+	}
+	v := d.Value
+	if v == nil {
+		v = new(big.Int)
+	}
+	switch {
+	case v.IsInt64() && v.Int64() >= math.MinInt32 && v.Int64() <= math.MaxInt32:
+		dst = append(appendPrimitiveHeader(dst, primitiveDecimal4), byte(d.Scale))
+		return binary.LittleEndian.AppendUint32(dst, uint32(v.Int64())), nil
+	case v.IsInt64():
+		dst = append(appendPrimitiveHeader(dst, primitiveDecimal8), byte(d.Scale))
+		return binary.LittleEndian.AppendUint64(dst, uint64(v.Int64())), nil
+	case v.Cmp(maxInt128) <= 0 && v.Cmp(minInt128) >= 0:
+		dst = append(appendPrimitiveHeader(dst, primitiveDecimal16), byte(d.Scale))
+		return appendInt128(dst, v), nil
+	default:
+		return dst, fmt.Errorf("%w: decimal value %s overflows 128 bits", ErrUnsupportedType, v)
+	}
+}
+
+var (
+	maxInt128 = new(big.Int).Sub(new(big.Int).Rsh(int128Range, 1), big.NewInt(1))
+	minInt128 = new(big.Int).Neg(new(big.Int).Rsh(int128Range, 1))
+)
+
+// appendInt128 appends v as a 16 bytes little-endian two's complement integer.
+func appendInt128(dst []byte, v *big.Int) []byte {
+	if v.Sign() < 0 {
+		v = new(big.Int).Add(v, int128Range)
+	}
+	be := v.FillBytes(make([]byte, 16))
+	for i := len(be) - 1; i >= 0; i-- {
+		dst = append(dst, be[i])
+	}
+	return dst
+}
+
+func appendNumElements(dst []byte, n int, isLarge bool) []byte {
+	if isLarge {
+		return binary.LittleEndian.AppendUint32(dst, uint32(n))
+	}
+	return append(dst, byte(n))
+}
+
+func appendUint(dst []byte, v, size int) []byte {
+	for i := 0; i < size; i++ {
+		dst = append(dst, byte(v>>(8*i)))
+	}
+	return dst
+}
+
+// uintSize returns the number of bytes needed to represent v.
+func uintSize(v int) int {
+	switch {
+	case v <= math.MaxUint8:
+		return 1
+	case v <= math.MaxUint16:
+		return 2
+	case v <= 1<<24-1:
+		return 3
+	default:
+		return 4
+	}
+}
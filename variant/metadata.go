@@ -0,0 +1,273 @@
+package variant
+
+import (
+	"encoding/binary"
+	"math"
+	"math/big"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Metadata is the decoded form of variant metadata, made of the dictionary of
+// field names referenced by variant objects.
+//
+// The zero value is an empty dictionary, which is valid to decode values that
+// contain no objects.
+type Metadata struct {
+	keys   []string
+	sorted bool
+}
+
+// ParseMetadata decodes the variant metadata in b.
+func ParseMetadata(b []byte) (Metadata, error) {
+	if len(b) == 0 {
+		return Metadata{}, errInvalid("empty metadata")
+	}
+
+	header := b[0]
+	if version := header & 0x0F; version != Version {
+		return Metadata{}, errInvalid("unsupported metadata version %d", version)
+	}
+	sorted := (header & 0x10) != 0
+	offsetSize := int(header>>6) + 1
+	b = b[1:]
+
+	if len(b) < offsetSize {
+		return Metadata{}, errInvalid("metadata too short to contain the dictionary size")
+	}
+	dictSize := readUint(b, offsetSize)
+	b = b[offsetSize:]
+
+	if uint64(dictSize+1)*uint64(offsetSize) > uint64(len(b)) {
+		return Metadata{}, errInvalid("metadata too short to contain %d dictionary offsets", dictSize+1)
+	}
+	offsets, data := b[:(dictSize+1)*offsetSize], b[(dictSize+1)*offsetSize:]
+
+	keys := make([]string, dictSize)
+	start := readUint(offsets, offsetSize)
+	for i := range keys {
+		end := readUint(offsets[(i+1)*offsetSize:], offsetSize)
+		if start > end || end > len(data) {
+			return Metadata{}, errInvalid("dictionary offset out of bounds: [%d:%d]", start, end)
+		}
+		keys[i] = string(data[start:end])
+		start = end
+	}
+
+	return Metadata{keys: keys, sorted: sorted}, nil
+}
+
+// Len returns the number of field names in the metadata dictionary.
+func (m Metadata) Len() int { return len(m.keys) }
+
+// Key returns the field name at index i of the metadata dictionary.
+func (m Metadata) Key(i int) string { return m.keys[i] }
+
+// Sorted returns true if the dictionary field names are sorted and unique.
+func (m Metadata) Sorted() bool { return m.sorted }
+
+// Decode decodes the variant value in b, resolving field names of objects in
+// the metadata dictionary. See Unmarshal for the list of Go types that values
+// are decoded to.
+func (m Metadata) Decode(b []byte) (any, error) {
+	v, _, err := m.decode(b)
+	return v, err
+}
+
+// decode returns the decoded value and the number of bytes it occupied in b.
+func (m Metadata) decode(b []byte) (any, int, error) {
+	if len(b) == 0 {
+		return nil, 0, errInvalid("empty value")
+	}
+
+	header := b[0]
+	switch valueHeader := int(header >> 2); header & 0x3 {
+	case basicPrimitive:
+		v, n, err := decodePrimitive(valueHeader, b[1:])
+		return v, 1 + n, err
+
+	case basicShortString:
+		if len(b) < 1+valueHeader {
+			return nil, 0, errInvalid("short string of length %d exceeds value size", valueHeader)
+		}
+		return string(b[1 : 1+valueHeader]), 1 + valueHeader, nil
+
+	case basicObject:
+		offsetSize := (valueHeader & 0x3) + 1
+		idSize := ((valueHeader >> 2) & 0x3) + 1
+		isLarge := (valueHeader & 0x10) != 0
+		numElements, ids, offsets, values, err := splitContainer(b[1:], isLarge, idSize, offsetSize)
+		if err != nil {
+			return nil, 0, err
+		}
+		object := make(map[string]any, numElements)
+		for i := 0; i < numElements; i++ {
+			id := readUint(ids[i*idSize:], idSize)
+			if id >= len(m.keys) {
+				return nil, 0, errInvalid("field id %d out of bounds of the metadata dictionary of size %d", id, len(m.keys))
+			}
+			v, err := m.decodeElement(values, readUint(offsets[i*offsetSize:], offsetSize))
+			if err != nil {
+				return nil, 0, err
+			}
+			object[m.keys[id]] = v
+		}
+		return object, len(b) - len(values) + readUint(offsets[numElements*offsetSize:], offsetSize), nil
+
+	default: // basicArray
+		offsetSize := (valueHeader & 0x3) + 1
+		isLarge := (valueHeader & 0x4) != 0
+		numElements, _, offsets, values, err := splitContainer(b[1:], isLarge, 0, offsetSize)
+		if err != nil {
+			return nil, 0, err
+		}
+		array := make([]any, numElements)
+		for i := range array {
+			v, err := m.decodeElement(values, readUint(offsets[i*offsetSize:], offsetSize))
+			if err != nil {
+				return nil, 0, err
+			}
+			array[i] = v
+		}
+		return array, len(b) - len(values) + readUint(offsets[numElements*offsetSize:], offsetSize), nil
+	}
+}
+
+func (m Metadata) decodeElement(values []byte, offset int) (any, error) {
+	if offset >= len(values) {
+		return nil, errInvalid("element offset %d out of bounds of values of size %d", offset, len(values))
+	}
+	v, _, err := m.decode(values[offset:])
+	return v, err
+}
+
+// splitContainer splits the content of an object or array value, following
+// its header byte, into the field ids, offsets, and values sections.
+func splitContainer(b []byte, isLarge bool, idSize, offsetSize int) (numElements int, ids, offsets, values []byte, err error) {
+	numSize := 1
+	if isLarge {
+		numSize = 4
+	}
+	if len(b) < numSize {
+		return 0, nil, nil, nil, errInvalid("value too short to contain the number of elements")
+	}
+	numElements = readUint(b, numSize)
+	b = b[numSize:]
+
+	idsLength := uint64(numElements) * uint64(idSize)
+	offsetsLength := uint64(numElements+1) * uint64(offsetSize)
+	if idsLength+offsetsLength > uint64(len(b)) {
+		return 0, nil, nil, nil, errInvalid("value too short to contain %d elements", numElements)
+	}
+	ids, b = b[:idsLength], b[idsLength:]
+	offsets, values = b[:offsetsLength], b[offsetsLength:]
+
+	if end := readUint(offsets[numElements*offsetSize:], offsetSize); end > len(values) {
+		return 0, nil, nil, nil, errInvalid("values size %d exceeds the remaining %d bytes", end, len(values))
+	}
+	return numElements, ids, offsets, values, nil
+}
+
+func decodePrimitive(typ int, b []byte) (any, int, error) {
+	size := 0
+	switch typ {
+	case primitiveNull, primitiveTrue, primitiveFalse:
+	case primitiveInt8:
+		size = 1
+	case primitiveInt16:
+		size = 2
+	case primitiveInt32, primitiveDate, primitiveFloat:
+		size = 4
+	case primitiveInt64, primitiveDouble, primitiveTimestampMicros, primitiveTimestampNTZMicros,
+		primitiveTimeNTZMicros, primitiveTimestampNanos, primitiveTimestampNTZNanos:
+		size = 8
+	case primitiveDecimal4:
+		size = 1 + 4
+	case primitiveDecimal8:
+		size = 1 + 8
+	case primitiveDecimal16:
+		size = 1 + 16
+	case primitiveUUID:
+		size = 16
+	case primitiveBinary, primitiveString:
+		if len(b) < 4 {
+			return nil, 0, errInvalid("value too short to contain the length of primitive type %d", typ)
+		}
+		size = 4 + int(binary.LittleEndian.Uint32(b))
+	default:
+		return nil, 0, errInvalid("unknown primitive type %d", typ)
+	}
+
+	if len(b) < size {
+		return nil, 0, errInvalid("value of primitive type %d too short: %d < %d", typ, len(b), size)
+	}
+	b = b[:size]
+
+	switch typ {
+	case primitiveNull:
+		return nil, size, nil
+	case primitiveTrue:
+		return true, size, nil
+	case primitiveFalse:
+		return false, size, nil
+	case primitiveInt8:
+		return int64(int8(b[0])), size, nil
+	case primitiveInt16:
+		return int64(int16(binary.LittleEndian.Uint16(b))), size, nil
+	case primitiveInt32:
+		return int64(int32(binary.LittleEndian.Uint32(b))), size, nil
+	case primitiveInt64:
+		return int64(binary.LittleEndian.Uint64(b)), size, nil
+	case primitiveFloat:
+		return math.Float32frombits(binary.LittleEndian.Uint32(b)), size, nil
+	case primitiveDouble:
+		return math.Float64frombits(binary.LittleEndian.Uint64(b)), size, nil
+	case primitiveDecimal4:
+		unscaled := int64(int32(binary.LittleEndian.Uint32(b[1:])))
+		return Decimal{Value: big.NewInt(unscaled), Scale: int(b[0])}, size, nil
+	case primitiveDecimal8:
+		unscaled := int64(binary.LittleEndian.Uint64(b[1:]))
+		return Decimal{Value: big.NewInt(unscaled), Scale: int(b[0])}, size, nil
+	case primitiveDecimal16:
+		return Decimal{Value: decodeInt128(b[1:]), Scale: int(b[0])}, size, nil
+	case primitiveDate:
+		days := int64(int32(binary.LittleEndian.Uint32(b)))
+		return time.Unix(days*86400, 0).UTC(), size, nil
+	case primitiveTimestampMicros, primitiveTimestampNTZMicros:
+		return time.UnixMicro(int64(binary.LittleEndian.Uint64(b))).UTC(), size, nil
+	case primitiveTimestampNanos, primitiveTimestampNTZNanos:
+		return time.Unix(0, int64(binary.LittleEndian.Uint64(b))).UTC(), size, nil
+	case primitiveTimeNTZMicros:
+		return time.Duration(binary.LittleEndian.Uint64(b)) * time.Microsecond, size, nil
+	case primitiveUUID:
+		return uuid.UUID(b), size, nil
+	case primitiveBinary:
+		return append([]byte{}, b[4:]...), size, nil
+	default: // primitiveString
+		return string(b[4:]), size, nil
+	}
+}
+
+// decodeInt128 decodes a 16 bytes little-endian two's complement integer.
+func decodeInt128(b []byte) *big.Int {
+	be := make([]byte, 16)
+	for i := range be {
+		be[i] = b[15-i]
+	}
+	v := new(big.Int).SetBytes(be)
+	if be[0]&0x80 != 0 {
+		v.Sub(v, int128Range)
+	}
+	return v
+}
+
+var int128Range = new(big.Int).Lsh(big.NewInt(1), 128)
+
+func readUint(b []byte, size int) int {
+	v := 0
+	for i := 0; i < size; i++ {
+		v |= int(b[i]) << (8 * i)
+	}
+	return v
+}
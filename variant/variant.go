@@ -0,0 +1,140 @@
+// Package variant implements the parquet variant binary encoding.
+//
+// Variant values are represented by two byte sequences: the metadata, which
+// holds a dictionary of the object field names, and the value, which encodes
+// the data and references field names by their index in the dictionary.
+//
+// https://github.com/apache/parquet-format/blob/master/VariantEncoding.md
+package variant
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// Version is the version of the variant encoding implemented by this package.
+const Version = 1
+
+const (
+	basicPrimitive   = 0
+	basicShortString = 1
+	basicObject      = 2
+	basicArray       = 3
+)
+
+const (
+	primitiveNull               = 0
+	primitiveTrue               = 1
+	primitiveFalse              = 2
+	primitiveInt8               = 3
+	primitiveInt16              = 4
+	primitiveInt32              = 5
+	primitiveInt64              = 6
+	primitiveDouble             = 7
+	primitiveDecimal4           = 8
+	primitiveDecimal8           = 9
+	primitiveDecimal16          = 10
+	primitiveDate               = 11
+	primitiveTimestampMicros    = 12
+	primitiveTimestampNTZMicros = 13
+	primitiveFloat              = 14
+	primitiveBinary             = 15
+	primitiveString             = 16
+	primitiveTimeNTZMicros      = 17
+	primitiveTimestampNanos     = 18
+	primitiveTimestampNTZNanos  = 19
+	primitiveUUID               = 20
+)
+
+const maxShortStringLength = 63
+
+var (
+	// ErrInvalid is returned when decoding malformed variant metadata or
+	// values.
+	ErrInvalid = errors.New("invalid variant")
+
+	// ErrUnsupportedType is returned when encoding a Go value which has no
+	// variant representation.
+	ErrUnsupportedType = errors.New("unsupported variant type")
+)
+
+func errInvalid(format string, args ...any) error {
+	return fmt.Errorf("%w: "+format, append([]any{ErrInvalid}, args...)...)
+}
+
+// Decimal represents variant decimal values. The numeric value is equal to
+// Value × 10^-Scale.
+type Decimal struct {
+	Value *big.Int
+	Scale int
+}
+
+// String returns a human-readable representation of the decimal value.
+func (d Decimal) String() string {
+	if d.Value == nil {
+		return "0"
+	}
+	s := d.Value.String()
+	if d.Scale <= 0 {
+		return s
+	}
+	sign := ""
+	if s[0] == '-' {
+		sign, s = "-", s[1:]
+	}
+	for len(s) <= d.Scale {
+		s = "0" + s
+	}
+	return sign + s[:len(s)-d.Scale] + "." + s[len(s)-d.Scale:]
+}
+
+// Marshal encodes v into a pair of variant metadata and value.
+//
+// Go values are encoded as follows:
+//
+//   - nil values, nil pointers, nil maps and nil slices are encoded as null
+//   - bool values are encoded as booleans
+//   - signed and unsigned integers are encoded as the smallest integer type
+//     able to represent them
+//   - float32 and float64 are encoded as float and double
+//   - strings are encoded as strings, []byte as binary
+//   - time.Time is encoded as a UTC timestamp with microsecond precision, or
+//     nanosecond precision if the value has sub-microsecond components
+//   - uuid.UUID is encoded as a UUID
+//   - Decimal is encoded as the smallest decimal type able to represent it
+//   - maps with string keys are encoded as objects
+//   - slices and arrays are encoded as arrays
+//
+// Pointers and interfaces are followed to encode the value they point to.
+func Marshal(v any) (metadata, value []byte, err error) {
+	e := new(Encoder)
+	value, err = e.Encode(nil, v)
+	if err != nil {
+		return nil, nil, err
+	}
+	return e.Metadata(nil), value, nil
+}
+
+// Unmarshal decodes a variant value and returns its Go representation.
+//
+// Values are decoded to the following Go types:
+//
+//   - null as nil
+//   - booleans as bool
+//   - integers of all sizes as int64
+//   - float as float32, double as float64
+//   - decimals as Decimal
+//   - strings as string, binary as []byte
+//   - dates and timestamps as time.Time in UTC
+//   - times as time.Duration since midnight
+//   - UUIDs as uuid.UUID
+//   - objects as map[string]any
+//   - arrays as []any
+func Unmarshal(metadata, value []byte) (any, error) {
+	m, err := ParseMetadata(metadata)
+	if err != nil {
+		return nil, err
+	}
+	return m.Decode(value)
+}
@@ -0,0 +1,235 @@
+package variant_test
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"math/big"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/parquet-go/parquet-go/variant"
+)
+
+func TestMarshalUnmarshal(t *testing.T) {
+	now := time.Date(2024, 3, 15, 12, 30, 45, 123456000, time.UTC)
+	id := uuid.MustParse("c0ffee00-1234-5678-9abc-def012345678")
+
+	tests := []struct {
+		scenario string
+		value    any
+		want     any
+	}{
+		{scenario: "null", value: nil, want: nil},
+		{scenario: "true", value: true, want: true},
+		{scenario: "false", value: false, want: false},
+		{scenario: "int8", value: int8(-12), want: int64(-12)},
+		{scenario: "int16", value: 1000, want: int64(1000)},
+		{scenario: "int32", value: uint32(1 << 30), want: int64(1 << 30)},
+		{scenario: "int64", value: int64(math.MinInt64), want: int64(math.MinInt64)},
+		{scenario: "float", value: float32(1.5), want: float32(1.5)},
+		{scenario: "double", value: math.Pi, want: math.Pi},
+		{scenario: "short string", value: "hello", want: "hello"},
+		{scenario: "long string", value: strings.Repeat("x", 100), want: strings.Repeat("x", 100)},
+		{scenario: "binary", value: []byte{1, 2, 3}, want: []byte{1, 2, 3}},
+		{scenario: "timestamp micros", value: now, want: now},
+		{scenario: "timestamp nanos", value: now.Add(789), want: now.Add(789)},
+		{scenario: "uuid", value: id, want: id},
+		{
+			scenario: "decimal4",
+			value:    variant.Decimal{Value: big.NewInt(-12345), Scale: 2},
+			want:     variant.Decimal{Value: big.NewInt(-12345), Scale: 2},
+		},
+		{
+			scenario: "decimal8",
+			value:    variant.Decimal{Value: big.NewInt(1 << 40), Scale: 3},
+			want:     variant.Decimal{Value: big.NewInt(1 << 40), Scale: 3},
+		},
+		{
+			scenario: "decimal16",
+			value:    variant.Decimal{Value: new(big.Int).Neg(new(big.Int).Lsh(big.NewInt(1), 100)), Scale: 10},
+			want:     variant.Decimal{Value: new(big.Int).Neg(new(big.Int).Lsh(big.NewInt(1), 100)), Scale: 10},
+		},
+		{scenario: "nil pointer", value: (*int)(nil), want: nil},
+		{scenario: "nil map", value: map[string]int(nil), want: nil},
+		{scenario: "array", value: []int{1, 2, 3}, want: []any{int64(1), int64(2), int64(3)}},
+		{scenario: "empty array", value: []string{}, want: []any{}},
+		{
+			scenario: "object",
+			value: map[string]any{
+				"name":  "Luke",
+				"age":   42,
+				"tags":  []string{"jedi", "pilot"},
+				"ship":  map[string]any{"model": "X-wing", "active": true},
+				"empty": map[string]int{},
+				"none":  nil,
+			},
+			want: map[string]any{
+				"name":  "Luke",
+				"age":   int64(42),
+				"tags":  []any{"jedi", "pilot"},
+				"ship":  map[string]any{"model": "X-wing", "active": true},
+				"empty": map[string]any{},
+				"none":  nil,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.scenario, func(t *testing.T) {
+			metadata, value, err := variant.Marshal(test.value)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := variant.Unmarshal(metadata, value)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("value mismatch:\nwant = %#v\ngot  = %#v", test.want, got)
+			}
+		})
+	}
+}
+
+func TestMarshalLargeContainers(t *testing.T) {
+	array := make([]int, 1000)
+	object := make(map[string]int, 300)
+	for i := range array {
+		array[i] = i * 1000
+	}
+	for i := 0; i < 300; i++ {
+		object[strings.Repeat("k", i+1)] = i
+	}
+
+	for _, value := range []any{array, object} {
+		metadata, encoded, err := variant.Marshal(value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := variant.Unmarshal(metadata, encoded)
+		if err != nil {
+			t.Fatal(err)
+		}
+		switch v := got.(type) {
+		case []any:
+			for i, x := range v {
+				if x != int64(array[i]) {
+					t.Fatalf("array element %d mismatch: want=%d got=%v", i, array[i], x)
+				}
+			}
+		case map[string]any:
+			for k, x := range v {
+				if x != int64(object[k]) {
+					t.Fatalf("object field %q mismatch: want=%d got=%v", k, object[k], x)
+				}
+			}
+		}
+	}
+}
+
+func TestEncoderSharedMetadata(t *testing.T) {
+	e := new(variant.Encoder)
+	a, err := e.Encode(nil, map[string]int{"b": 1, "a": 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := e.Encode(nil, map[string]int{"c": 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m, err := variant.ParseMetadata(e.Metadata(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Len() != 3 {
+		t.Fatalf("wrong number of keys: want=3 got=%d", m.Len())
+	}
+
+	for _, test := range []struct {
+		value []byte
+		want  any
+	}{
+		{a, map[string]any{"a": int64(2), "b": int64(1)}},
+		{b, map[string]any{"c": int64(3)}},
+	} {
+		got, err := m.Decode(test.value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("value mismatch: want=%v got=%v", test.want, got)
+		}
+	}
+}
+
+func TestEncodingLayout(t *testing.T) {
+	metadata, value, err := variant.Marshal(map[string]any{"a": 1, "b": "c"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantMetadata := []byte{0x11, 2, 0, 1, 2, 'a', 'b'}
+	if !bytes.Equal(metadata, wantMetadata) {
+		t.Errorf("metadata mismatch:\nwant = %#v\ngot  = %#v", wantMetadata, metadata)
+	}
+
+	wantValue := []byte{
+		0x02, // object, 1 byte ids and offsets
+		2,    // number of elements
+		0, 1, // field ids
+		0, 2, 4, // field offsets
+		0x0C, 1, // int8(1)
+		0x05, 'c', // short string "c"
+	}
+	if !bytes.Equal(value, wantValue) {
+		t.Errorf("value mismatch:\nwant = %#v\ngot  = %#v", wantValue, value)
+	}
+}
+
+func TestUnmarshalInvalid(t *testing.T) {
+	metadata, value, err := variant.Marshal(map[string]any{"a": []int{1, 2}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < len(value); i++ {
+		if _, err := variant.Unmarshal(metadata, value[:i]); !errors.Is(err, variant.ErrInvalid) {
+			t.Errorf("truncated value of length %d: expected ErrInvalid but got %v", i, err)
+		}
+	}
+	if _, err := variant.Unmarshal([]byte{0x02, 0}, value); !errors.Is(err, variant.ErrInvalid) {
+		t.Errorf("unsupported metadata version: expected ErrInvalid but got %v", err)
+	}
+}
+
+func TestMarshalUnsupported(t *testing.T) {
+	for _, value := range []any{
+		make(chan int),
+		map[int]string{1: "a"},
+		uint64(math.MaxUint64),
+		struct{}{},
+	} {
+		if _, _, err := variant.Marshal(value); !errors.Is(err, variant.ErrUnsupportedType) {
+			t.Errorf("%T: expected ErrUnsupportedType but got %v", value, err)
+		}
+	}
+}
+
+func TestDecimalString(t *testing.T) {
+	for _, test := range []struct {
+		decimal variant.Decimal
+		want    string
+	}{
+		{variant.Decimal{Value: big.NewInt(12345), Scale: 2}, "123.45"},
+		{variant.Decimal{Value: big.NewInt(-5), Scale: 3}, "-0.005"},
+		{variant.Decimal{Value: big.NewInt(42)}, "42"},
+	} {
+		if got := test.decimal.String(); got != test.want {
+			t.Errorf("want=%s got=%s", test.want, got)
+		}
+	}
+}
@@ -0,0 +1,358 @@
+package parquet_test
+
+import (
+	"bytes"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+	"github.com/parquet-go/parquet-go/variant"
+)
+
+type variantRow struct {
+	ID       int64 `parquet:"id"`
+	Payload  any   `parquet:"payload,variant"`
+	Optional any   `parquet:"optional,variant,optional"`
+}
+
+// normalizeVariant returns the Go value that v is expected to be decoded to
+// after a round trip through the variant encoding.
+func normalizeVariant(t *testing.T, v any) any {
+	t.Helper()
+	metadata, value, err := variant.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	v, err = variant.Unmarshal(metadata, value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
+
+func variantPayloads() []any {
+	return []any{
+		map[string]any{"customer": "acme", "amount": 42, "user": map[string]any{"id": 1, "name": "Luke"}},
+		map[string]any{"customer": "globex", "amount": "unknown"},
+		map[string]any{"customer": "initech", "tags": []string{"a", "b"}, "user": map[string]any{"id": "x"}},
+		map[string]any{"amount": int64(1) << 40, "user": "anonymous"},
+		map[string]any{},
+		"not an object",
+		[]any{1, "two", 3.0},
+		nil,
+		true,
+		map[string]any{"customer": nil, "user": map[string]any{"id": 2, "at": time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)}},
+	}
+}
+
+func TestVariant(t *testing.T) {
+	payloads := variantPayloads()
+	rows := make([]variantRow, 3*len(payloads))
+	for i := range rows {
+		rows[i] = variantRow{ID: int64(i), Payload: payloads[i%len(payloads)]}
+		if i%2 == 0 {
+			rows[i].Optional = payloads[(i+1)%len(payloads)]
+		}
+	}
+
+	for _, test := range []struct {
+		scenario string
+		write    func(io.Writer) error
+	}{
+		{
+			scenario: "generic writer",
+			write: func(output io.Writer) error {
+				w := parquet.NewGenericWriter[variantRow](output)
+				if _, err := w.Write(rows); err != nil {
+					return err
+				}
+				return w.Close()
+			},
+		},
+		{
+			scenario: "writer",
+			write: func(output io.Writer) error {
+				w := parquet.NewWriter(output, parquet.SchemaOf(variantRow{}))
+				for _, row := range rows {
+					if err := w.Write(row); err != nil {
+						return err
+					}
+				}
+				return w.Close()
+			},
+		},
+	} {
+		t.Run(test.scenario, func(t *testing.T) {
+			b := new(bytes.Buffer)
+			if err := test.write(b); err != nil {
+				t.Fatal(err)
+			}
+
+			f, err := parquet.OpenFile(bytes.NewReader(b.Bytes()), int64(b.Len()))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if lt := f.Metadata().Schema[2].LogicalType; lt == nil || lt.Variant == nil {
+				t.Errorf("wrong logical type of the payload column: %v", lt)
+			}
+			if !strings.Contains(f.Schema().String(), "group payload (VARIANT)") {
+				t.Errorf("wrong schema:\n%s", f.Schema())
+			}
+
+			r := parquet.NewGenericReader[variantRow](f)
+			got := make([]variantRow, len(rows))
+			if n, err := r.Read(got); n != len(rows) {
+				t.Fatalf("wrong number of rows read: %d/%d: %v", n, len(rows), err)
+			}
+
+			for i, row := range rows {
+				want := variantRow{
+					ID:       row.ID,
+					Payload:  normalizeVariant(t, row.Payload),
+					Optional: normalizeVariant(t, row.Optional),
+				}
+				if !reflect.DeepEqual(got[i], want) {
+					t.Errorf("row %d mismatch:\nwant = %#v\ngot  = %#v", i, want, got[i])
+				}
+			}
+		})
+	}
+}
+
+type shreddedVariantRow struct {
+	ID      int64 `parquet:"id"`
+	Payload any   `parquet:"payload,variant"`
+}
+
+func shreddedVariantSchema() *parquet.Schema {
+	return parquet.NewSchema("event", parquet.Group{
+		"id": parquet.Int(64),
+		"payload": parquet.ShreddedVariant(parquet.Group{
+			"customer": parquet.String(),
+			"amount":   parquet.Int(64),
+			"user": parquet.Group{
+				"id": parquet.Int(32),
+				"at": parquet.Timestamp(parquet.Microsecond),
+			},
+		}),
+	})
+}
+
+func TestShreddedVariant(t *testing.T) {
+	schema := shreddedVariantSchema()
+	payloads := variantPayloads()
+	rows := make([]shreddedVariantRow, len(payloads))
+	for i := range rows {
+		rows[i] = shreddedVariantRow{ID: int64(i), Payload: payloads[i]}
+	}
+
+	b := new(bytes.Buffer)
+	w := parquet.NewGenericWriter[shreddedVariantRow](b, schema)
+	if _, err := w.Write(rows); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := parquet.OpenFile(bytes.NewReader(b.Bytes()), int64(b.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Reading with the schema of the Go type declaring an unshredded variant
+	// must reconstruct the values from both the typed and variant columns.
+	r := parquet.NewGenericReader[shreddedVariantRow](f)
+	got := make([]shreddedVariantRow, len(rows))
+	if n, err := r.Read(got); n != len(rows) {
+		t.Fatalf("wrong number of rows read: %d/%d: %v", n, len(rows), err)
+	}
+	for i, row := range rows {
+		want := shreddedVariantRow{ID: row.ID, Payload: normalizeVariant(t, row.Payload)}
+		if !reflect.DeepEqual(got[i], want) {
+			t.Errorf("row %d mismatch:\nwant = %#v\ngot  = %#v", i, want, got[i])
+		}
+	}
+
+	reader := parquet.NewReader(f)
+	for i, row := range rows {
+		var got shreddedVariantRow
+		if err := reader.Read(&got); err != nil {
+			t.Fatal(err)
+		}
+		want := shreddedVariantRow{ID: row.ID, Payload: normalizeVariant(t, row.Payload)}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("row %d mismatch:\nwant = %#v\ngot  = %#v", i, want, got)
+		}
+	}
+
+	// Values of shredded fields with the expected types must be stored in the
+	// typed columns so they can be read without decoding the variant values.
+	for _, test := range []struct {
+		path []string
+		want []any
+	}{
+		{
+			path: []string{"customer"},
+			want: []any{"acme", "globex", "initech", nil, nil, nil, nil, nil, nil, nil},
+		},
+		{
+			path: []string{"amount"},
+			want: []any{int64(42), nil, nil, int64(1) << 40, nil, nil, nil, nil, nil, nil},
+		},
+		{
+			path: []string{"user", "id"},
+			want: []any{int64(1), nil, nil, nil, nil, nil, nil, nil, nil, int64(2)},
+		},
+	} {
+		path := append([]string{"payload"}, parquet.VariantFieldPath(test.path...)...)
+		leaf, ok := f.Schema().Lookup(path...)
+		if !ok {
+			t.Fatalf("column %q not found", strings.Join(path, "."))
+		}
+
+		values := readColumnValues(t, f.RowGroups()[0].ColumnChunks()[leaf.ColumnIndex])
+		got := make([]any, len(values))
+		for i, v := range values {
+			if v.DefinitionLevel() == leaf.MaxDefinitionLevel {
+				switch v.Kind() {
+				case parquet.ByteArray:
+					got[i] = string(v.ByteArray())
+				case parquet.Int32:
+					got[i] = int64(v.Int32())
+				default:
+					got[i] = v.Int64()
+				}
+			}
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: typed values mismatch:\nwant = %v\ngot  = %v", strings.Join(path, "."), test.want, got)
+		}
+	}
+}
+
+func readColumnValues(t *testing.T, chunk parquet.ColumnChunk) []parquet.Value {
+	t.Helper()
+	pages := chunk.Pages()
+	defer pages.Close()
+
+	var values []parquet.Value
+	for {
+		p, err := pages.ReadPage()
+		if err == io.EOF {
+			return values
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		buffer := make([]parquet.Value, p.NumValues())
+		n, _ := p.Values().ReadValues(buffer)
+		values = append(values, buffer[:n]...)
+	}
+}
+
+func TestShreddedVariantProjection(t *testing.T) {
+	payloads := variantPayloads()
+	rows := make([]shreddedVariantRow, len(payloads))
+	for i := range rows {
+		rows[i] = shreddedVariantRow{ID: int64(i), Payload: payloads[i]}
+	}
+
+	f, input := writeTestFile(t, rows, shreddedVariantSchema())
+
+	// Projecting the typed column of a shredded field only reads that column,
+	// values which were not shredded are absent from the objects.
+	path := append([]string{"payload"}, parquet.VariantFieldPath("user", "id")...)
+	r := parquet.NewGenericReader[shreddedVariantRow](f, parquet.Projection(path))
+	got := make([]shreddedVariantRow, len(rows)+1)
+	n, err := r.Read(got)
+	if err != nil && err != io.EOF {
+		t.Fatal(err)
+	}
+	r.Close()
+
+	user := func(id any) map[string]any { return map[string]any{"user": map[string]any{"id": id}} }
+	want := []any{
+		user(int64(1)),
+		map[string]any{},
+		map[string]any{"user": map[string]any{}},
+		map[string]any{},
+		map[string]any{},
+		nil,
+		nil,
+		nil,
+		nil,
+		user(int64(2)),
+	}
+	if n != len(want) {
+		t.Fatalf("wrong number of rows read: want=%d got=%d", len(want), n)
+	}
+	for i := range want {
+		if !reflect.DeepEqual(got[i].Payload, want[i]) {
+			t.Errorf("row %d mismatch:\nwant = %#v\ngot  = %#v", i, want[i], got[i].Payload)
+		}
+	}
+
+	for _, column := range [][]string{{"payload", "metadata"}, {"payload", "value"}} {
+		leaf, _ := f.Schema().Lookup(column...)
+		metadata := f.Metadata().RowGroups[0].Columns[leaf.ColumnIndex].MetaData
+		offset := metadata.DataPageOffset
+		if metadata.DictionaryPageOffset != 0 {
+			offset = metadata.DictionaryPageOffset
+		}
+		if input.overlaps(offset, metadata.TotalCompressedSize) {
+			t.Errorf("pages of column %q were read", strings.Join(column, "."))
+		}
+	}
+	leaf, _ := f.Schema().Lookup(path...)
+	if metadata := f.Metadata().RowGroups[0].Columns[leaf.ColumnIndex].MetaData; !input.overlaps(metadata.DataPageOffset, metadata.TotalCompressedSize) {
+		t.Errorf("pages of column %q were not read", strings.Join(path, "."))
+	}
+
+	// Projecting the value columns of shredded fields also reads the metadata
+	// needed to decode them.
+	path = append([]string{"payload"}, parquet.VariantFieldPath("user")...)
+	r = parquet.NewGenericReader[shreddedVariantRow](f, parquet.Projection(path))
+	defer r.Close()
+	n, err = r.Read(got)
+	if err != nil && err != io.EOF {
+		t.Fatal(err)
+	}
+	if n != len(rows) {
+		t.Fatalf("wrong number of rows read: want=%d got=%d", len(rows), n)
+	}
+	if want := map[string]any{"user": map[string]any{"id": "x"}}; !reflect.DeepEqual(got[2].Payload, want) {
+		t.Errorf("row 2 mismatch:\nwant = %#v\ngot  = %#v", want, got[2].Payload)
+	}
+}
+
+func TestShreddedVariantSchema(t *testing.T) {
+	const want = `message event {
+	required group payload (VARIANT) {
+		required binary metadata;
+		optional group typed_value {
+			required group amount {
+				optional int64 typed_value (INT(64,true));
+				optional binary value;
+			}
+			required group customer {
+				optional binary typed_value (STRING);
+				optional binary value;
+			}
+		}
+		optional binary value;
+	}
+}`
+	schema := parquet.NewSchema("event", parquet.Group{
+		"payload": parquet.ShreddedVariant(parquet.Group{
+			"customer": parquet.String(),
+			"amount":   parquet.Int(64),
+		}),
+	})
+	if got := schema.String(); got != want {
+		t.Errorf("schema mismatch:\nwant:\n%s\ngot:\n%s", want, got)
+	}
+}
@@ -13,6 +13,8 @@ import (
 	"reflect"
 	"slices"
 	"sort"
+	"sync"
+	"sync/atomic"
 
 	"github.com/parquet-go/parquet-go/compress"
 	"github.com/parquet-go/parquet-go/encoding"
@@ -146,12 +148,16 @@ func makeWriteFunc[T any](t reflect.Type, schema *Schema) writeFunc[T] {
 	return func(w *GenericWriter[T], rows []T) (n int, err error) {
 		if w.columns == nil {
 			w.columns = make([]ColumnBuffer, len(w.base.writer.columns))
-			for i, c := range w.base.writer.columns {
-				// These fields are usually lazily initialized when writing rows,
-				// we need them to exist now tho.
+		}
+		for i, c := range w.base.writer.columns {
+			// These fields are usually lazily initialized when writing rows,
+			// we need them to exist now tho. Column buffers are also replaced
+			// when columns fall back from dictionary encoding, so they must
+			// be reloaded on each write.
+			if c.columnBuffer == nil {
 				c.columnBuffer = c.newColumnBuffer()
-				w.columns[i] = c.columnBuffer
 			}
+			w.columns[i] = c.columnBuffer
 		}
 		err = writeRows(w.columns, makeArrayOf(rows), columnLevels{})
 		if err == nil {
@@ -179,16 +185,7 @@ func (w *GenericWriter[T]) Write(rows []T) (int, error) {
 		if err != nil {
 			return n, err
 		}
-
-		for _, c := range w.base.writer.columns {
-			if c.columnBuffer.Size() >= int64(c.bufferSize) {
-				if err := c.flush(); err != nil {
-					return n, err
-				}
-			}
-		}
-
-		return n, nil
+		return n, w.base.writer.flushFullPages()
 	})
 }
 
@@ -484,6 +481,16 @@ type writer struct {
 	values  [][]Value
 	numRows int64
 	maxRows int64
+	maxSize int64
+
+	// Set when columns have a limit on the number of rows in data pages, which
+	// requires bounding the number of rows written to column buffers at once.
+	limitPageRows bool
+
+	// Number of goroutines encoding the pages of different columns, and the
+	// list of columns with full pages to flush when it is greater than one.
+	concurrency int
+	fullColumns []*writerColumn
 
 	createdBy string
 	metadata  []format.KeyValue
@@ -499,6 +506,12 @@ type writer struct {
 	columnIndexes  [][]format.ColumnIndex
 	offsetIndexes  [][]format.OffsetIndex
 	sortingColumns []format.SortingColumn
+
+	// Metadata written in the footer of the file, retained after the writer
+	// was closed until it gets reset.
+	fileMetaData format.FileMetaData
+
+	encryptor *fileEncryptor
 }
 
 func newWriter(output io.Writer, config *WriterConfig) *writer {
@@ -510,6 +523,8 @@ func newWriter(output io.Writer, config *WriterConfig) *writer {
 		w.writer.Reset(w.buffer)
 	}
 	w.maxRows = config.MaxRowsPerRowGroup
+	w.maxSize = config.MaxRowGroupBytes
+	w.concurrency = config.WriteConcurrency
 	w.createdBy = config.CreatedBy
 	w.metadata = make([]format.KeyValue, 0, len(config.KeyValueMetadata))
 	for k, v := range config.KeyValueMetadata {
@@ -518,6 +533,14 @@ func newWriter(output io.Writer, config *WriterConfig) *writer {
 	sortKeyValueMetadata(w.metadata)
 	w.sortingColumns = make([]format.SortingColumn, len(config.Sorting.SortingColumns))
 
+	if config.Encryption != nil {
+		encryptor, err := newFileEncryptor(config.Encryption)
+		if err != nil {
+			panic(err)
+		}
+		w.encryptor = encryptor
+	}
+
 	config.Schema.forEachNode(func(name string, node Node) {
 		nodeType := node.Type()
 
@@ -567,12 +590,16 @@ func newWriter(output io.Writer, config *WriterConfig) *writer {
 	// Those buffers are scratch space used to generate the page header and
 	// content, they are shared by all column chunks because they are only
 	// used during calls to writeDictionaryPage or writeDataPage, which are
-	// not done concurrently.
+	// not done concurrently unless the writer has a concurrency greater than
+	// one, in which case each column has its own buffers.
 	buffers := new(writerBuffers)
 
 	forEachLeafColumnOf(config.Schema, func(leaf leafColumn) {
+		buffers := buffers
+		if w.concurrency > 1 {
+			buffers = new(writerBuffers)
+		}
 		encoding := encodingOf(leaf.node)
-		dictionary := Dictionary(nil)
 		columnType := leaf.node.Type()
 		columnIndex := int(leaf.columnIndex)
 		compression := leaf.node.Compression()
@@ -581,13 +608,14 @@ func newWriter(output io.Writer, config *WriterConfig) *writer {
 			compression = defaultCompression
 		}
 
-		if isDictionaryEncoding(encoding) {
-			dictBuffer := columnType.NewValues(
-				make([]byte, 0, defaultDictBufferSize),
-				nil,
-			)
-			dictionary = columnType.NewDictionary(columnIndex, 0, dictBuffer)
-			columnType = dictionary.Type()
+		// Columns with no explicit encoding have their encoding selected
+		// when the first page of each column chunk is written.
+		selectEncoding := config.AdaptiveEncoding != AdaptiveEncodingDisabled && leaf.node.Encoding() == nil
+
+		dictionaryMaxBytes, fallbackEncoding := dictionaryLimitOf(config, leaf.path)
+		pageBufferSize, pageRowCountLimit := pageLimitsOf(config, leaf.path)
+		if fallbackEncoding != nil && (selectEncoding || isDictionaryEncoding(encoding)) && !canEncode(fallbackEncoding, columnType.Kind()) {
+			panic(fmt.Sprintf("cannot use %s as fallback encoding of column %q of type %s", fallbackEncoding, leaf.path, columnType))
 		}
 
 		c := &writerColumn{
@@ -598,40 +626,40 @@ func newWriter(output io.Writer, config *WriterConfig) *writer {
 			columnIndex:        columnType.NewColumnIndexer(config.ColumnIndexSizeLimit),
 			columnFilter:       searchBloomFilterColumn(config.BloomFilters, leaf.path),
 			compression:        compression,
-			dictionary:         dictionary,
+			baseType:           columnType,
+			baseEncoding:       encoding,
+			dictionaryMaxBytes: dictionaryMaxBytes,
+			fallbackEncoding:   fallbackEncoding,
+			selectEncoding:     selectEncoding,
+			compareCompressed:  config.AdaptiveEncoding == AdaptiveEncodingCompressedSize,
 			dataPageType:       dataPageType,
 			maxRepetitionLevel: leaf.maxRepetitionLevel,
 			maxDefinitionLevel: leaf.maxDefinitionLevel,
 			bufferIndex:        int32(leaf.columnIndex),
-			bufferSize:         int32(float64(config.PageBufferSize) * 0.98),
+			bufferSize:         int32(float64(pageBufferSize) * 0.98),
+			pageRowCountLimit:  pageRowCountLimit,
 			writePageStats:     config.DataPageStatistics,
-			writePageBounds: !slices.ContainsFunc(config.SkipPageBounds, func(skip []string) bool {
+			writePageChecksums: !config.SkipPageChecksums,
+			writePageBounds: !hasUndefinedOrder(columnType) && !slices.ContainsFunc(config.SkipPageBounds, func(skip []string) bool {
 				return columnPath(skip).equal(leaf.path)
 			}),
-			encodings: make([]format.Encoding, 0, 3),
-			// Data pages in version 2 can omit compression when dictionary
-			// encoding is employed; only the dictionary page needs to be
-			// compressed, the data pages are encoded with the hybrid
-			// RLE/Bit-Pack encoding which doesn't benefit from an extra
-			// compression layer.
-			isCompressed: isCompressed(compression) && (dataPageType != format.DataPageV2 || dictionary == nil),
+			geospatial: newGeospatialStatistics(columnType),
 		}
 
+		c.setEncoding(encoding)
 		c.header.encoder.Reset(c.header.protocol.NewWriter(&buffers.header))
+		c.sizeStatistics.init(columnType, leaf.maxRepetitionLevel, leaf.maxDefinitionLevel)
 
-		if leaf.maxDefinitionLevel > 0 {
-			c.encodings = addEncoding(c.encodings, format.RLE)
-		}
-
-		if isDictionaryEncoding(encoding) {
-			c.encodings = addEncoding(c.encodings, format.Plain)
+		if w.encryptor != nil {
+			encryptor, err := w.encryptor.columnEncryptor(leaf.path, columnIndex)
+			if err != nil {
+				panic(err)
+			}
+			c.encryptor = encryptor
 		}
 
-		c.encoding = encoding
-		c.encodings = addEncoding(c.encodings, c.encoding.Encoding())
-		sortPageEncodings(c.encodings)
-
 		w.columns = append(w.columns, c)
+		w.limitPageRows = w.limitPageRows || pageRowCountLimit < math.MaxInt
 
 		if sortingIndex := searchSortingColumn(config.Sorting.SortingColumns, leaf.path); sortingIndex < len(w.sortingColumns) {
 			w.sortingColumns[sortingIndex] = format.SortingColumn{
@@ -642,6 +670,17 @@ func newWriter(output io.Writer, config *WriterConfig) *writer {
 		}
 	})
 
+	// A column key which does not match any leaf column is most likely a typo,
+	// silently writing the column it was meant for in plaintext would defeat
+	// the purpose of encrypting it.
+	if config.Encryption != nil {
+		for _, columnKey := range config.Encryption.ColumnKeys {
+			if !slices.ContainsFunc(w.columns, func(c *writerColumn) bool { return c.columnPath.equal(columnKey.Path) }) {
+				panic(fmt.Errorf("encrypted column %q does not exist in the schema", columnPath(columnKey.Path)))
+			}
+		}
+	}
+
 	// Pre-allocate the backing array so that in most cases where the rows
 	// contain a single value we will hit collocated memory areas when writing
 	// rows to the writer. This won't benefit repeated columns much but in that
@@ -661,7 +700,6 @@ func newWriter(output io.Writer, config *WriterConfig) *writer {
 		w.columnChunk[i] = format.ColumnChunk{
 			MetaData: format.ColumnMetaData{
 				Type:             format.Type(c.columnType.Kind()),
-				Encoding:         c.encodings,
 				PathInSchema:     c.columnPath,
 				Codec:            c.compression.CompressionCodec(),
 				KeyValueMetadata: nil, // TODO
@@ -681,6 +719,31 @@ func newWriter(output io.Writer, config *WriterConfig) *writer {
 	return w
 }
 
+// dictionaryLimitOf returns the maximum size of the dictionary of the column at
+// path, and the encoding of data pages written once the limit is exceeded, or
+// nil if none were configured.
+func dictionaryLimitOf(config *WriterConfig, path columnPath) (int64, encoding.Encoding) {
+	for _, limit := range config.DictionaryLimits {
+		if path.equal(limit.Path) {
+			return limit.MaxBytes, limit.Fallback
+		}
+	}
+	return config.DictionaryMaxBytes, nil
+}
+
+// pageLimitsOf returns the page buffer size and the row count limit of data
+// pages of the column at path.
+func pageLimitsOf(config *WriterConfig, path columnPath) (bufferSize, rowCountLimit int) {
+	bufferSize, rowCountLimit = config.PageBufferSize, config.DataPageRowCountLimit
+	for _, limit := range config.PageLimits {
+		if path.equal(limit.Path) {
+			bufferSize = coalesceInt(limit.BufferSize, bufferSize)
+			rowCountLimit = coalesceInt(limit.RowCountLimit, rowCountLimit)
+		}
+	}
+	return bufferSize, rowCountLimit
+}
+
 func (w *writer) reset(writer io.Writer) {
 	if w.buffer == nil {
 		w.writer.Reset(writer)
@@ -690,6 +753,14 @@ func (w *writer) reset(writer io.Writer) {
 	}
 	for _, c := range w.columns {
 		c.reset()
+		if c.encryptor != nil {
+			c.encryptor.rowGroup = 0
+		}
+	}
+	if w.encryptor != nil {
+		if err := w.encryptor.reset(); err != nil {
+			panic(err)
+		}
 	}
 	for i := range w.rowGroups {
 		w.rowGroups[i] = format.RowGroup{}
@@ -703,6 +774,7 @@ func (w *writer) reset(writer io.Writer) {
 	w.rowGroups = w.rowGroups[:0]
 	w.columnIndexes = w.columnIndexes[:0]
 	w.offsetIndexes = w.offsetIndexes[:0]
+	w.fileMetaData = format.FileMetaData{}
 }
 
 func (w *writer) close() error {
@@ -731,7 +803,7 @@ func (w *writer) writeFileHeader() error {
 		return io.ErrClosedPipe
 	}
 	if w.writer.offset == 0 {
-		_, err := w.writer.WriteString("PAR1")
+		_, err := w.writer.WriteString(w.encryptor.magic())
 		return err
 	}
 	return nil
@@ -764,9 +836,16 @@ func (w *writer) writeFileFooter() error {
 	for i, columnIndexes := range w.columnIndexes {
 		rowGroup := &w.rowGroups[i]
 		for j := range columnIndexes {
+			if !w.columns[j].writePageBounds {
+				// Page bounds are meaningless for values that have no sort
+				// order, and were not computed for columns configured with
+				// SkipPageBounds, the column index is omitted for those
+				// columns.
+				continue
+			}
 			column := &rowGroup.Columns[j]
 			column.ColumnIndexOffset = w.writer.offset
-			if err := encoder.Encode(&columnIndexes[j]); err != nil {
+			if err := w.writeIndex(encoder, i, j, moduleColumnIndex, &columnIndexes[j]); err != nil {
 				return err
 			}
 			column.ColumnIndexLength = int32(w.writer.offset - column.ColumnIndexOffset)
@@ -778,13 +857,35 @@ func (w *writer) writeFileFooter() error {
 		for j := range offsetIndexes {
 			column := &rowGroup.Columns[j]
 			column.OffsetIndexOffset = w.writer.offset
-			if err := encoder.Encode(&offsetIndexes[j]); err != nil {
+			if err := w.writeIndex(encoder, i, j, moduleOffsetIndex, &offsetIndexes[j]); err != nil {
 				return err
 			}
 			column.OffsetIndexLength = int32(w.writer.offset - column.OffsetIndexOffset)
 		}
 	}
 
+	encryptionAlgorithm := format.EncryptionAlgorithm{}
+	footerSigningKeyMetadata := []byte(nil)
+	if w.encryptor != nil {
+		for i := range w.rowGroups {
+			rowGroup := &w.rowGroups[i]
+			for j, c := range w.columns {
+				if c.encryptor == nil {
+					continue
+				}
+				if err := c.encryptor.encryptColumnMetaData(protocol, i, &rowGroup.Columns[j]); err != nil {
+					return fmt.Errorf("encrypting column metadata: rowGroup=%d columnChunk=%d/%d: %w", i, j, len(w.columns), err)
+				}
+			}
+		}
+		// In plaintext footer mode, the encryption algorithm is stored in the
+		// footer, otherwise it is written in the file crypto metadata.
+		if w.encryptor.config.PlaintextFooter {
+			encryptionAlgorithm = w.encryptor.algorithm
+			footerSigningKeyMetadata = w.encryptor.config.FooterKeyMetadata
+		}
+	}
+
 	numRows := int64(0)
 	for rowGroupIndex := range w.rowGroups {
 		numRows += w.rowGroups[rowGroupIndex].NumRows
@@ -797,28 +898,56 @@ func (w *writer) writeFileFooter() error {
 	// https://github.com/apache/arrow/blob/70b9ef5/go/parquet/metadata/file.go#L122-L127
 	const parquetFileFormatVersion = 2
 
-	footer, err := thrift.Marshal(new(thrift.CompactProtocol), &format.FileMetaData{
-		Version:          parquetFileFormatVersion,
-		Schema:           w.schemaElements,
-		NumRows:          numRows,
-		RowGroups:        w.rowGroups,
-		KeyValueMetadata: w.metadata,
-		CreatedBy:        w.createdBy,
-		ColumnOrders:     w.columnOrders,
-	})
+	w.fileMetaData = format.FileMetaData{
+		Version:                  parquetFileFormatVersion,
+		Schema:                   w.schemaElements,
+		NumRows:                  numRows,
+		RowGroups:                w.rowGroups,
+		KeyValueMetadata:         w.metadata,
+		CreatedBy:                w.createdBy,
+		ColumnOrders:             w.columnOrders,
+		EncryptionAlgorithm:      encryptionAlgorithm,
+		FooterSigningKeyMetadata: footerSigningKeyMetadata,
+	}
+
+	footer, err := thrift.Marshal(new(thrift.CompactProtocol), &w.fileMetaData)
 	if err != nil {
 		return err
 	}
+	if w.encryptor != nil {
+		if footer, err = w.encryptor.encryptFooter(protocol, footer); err != nil {
+			return fmt.Errorf("encrypting parquet file metadata: %w", err)
+		}
+	}
 
 	length := len(footer)
 	footer = append(footer, 0, 0, 0, 0)
-	footer = append(footer, "PAR1"...)
+	footer = append(footer, w.encryptor.magic()...)
 	binary.LittleEndian.PutUint32(footer[length:], uint32(length))
 
 	_, err = w.writer.Write(footer)
 	return err
 }
 
+// writeIndex writes the column or offset index of a column chunk, encrypting it
+// if the column is encrypted.
+func (w *writer) writeIndex(encoder *thrift.Encoder, rowGroup, column int, module byte, index interface{}) error {
+	c := w.columns[column]
+	if c.encryptor == nil {
+		return encoder.Encode(index)
+	}
+	b, err := thrift.Marshal(new(thrift.CompactProtocol), index)
+	if err != nil {
+		return err
+	}
+	aad := moduleAAD(w.encryptor.aad, module, rowGroup, column, 0)
+	if b, err = c.encryptor.cipher.encryptGCM(nil, b, aad); err != nil {
+		return err
+	}
+	_, err = w.writer.Write(b)
+	return err
+}
+
 func (w *writer) writeRowGroup(rowGroupSchema *Schema, rowGroupSortingColumns []SortingColumn) (int64, error) {
 	numRows := w.columns[0].totalRowCount()
 	if numRows == 0 {
@@ -833,19 +962,31 @@ func (w *writer) writeRowGroup(rowGroupSchema *Schema, rowGroupSortingColumns []
 		w.numRows = 0
 		for _, c := range w.columns {
 			c.reset()
+			if c.encryptor != nil {
+				c.encryptor.rowGroup = len(w.rowGroups)
+			}
 		}
 		for i := range w.columnIndex {
 			w.columnIndex[i] = format.ColumnIndex{}
 		}
 	}()
 
-	for _, c := range w.columns {
+	err := w.forEachColumn(w.columns, func(i int, c *writerColumn) error {
 		if err := c.flush(); err != nil {
-			return 0, err
+			return err
 		}
 		if err := c.flushFilterPages(); err != nil {
-			return 0, err
+			return err
+		}
+		if c.dictionary != nil {
+			if err := c.writeDictionaryPage(&c.dictionaryPage, c.dictionary); err != nil {
+				return fmt.Errorf("writing dictionary page of row group colum %d: %w", i, err)
+			}
 		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
 	}
 
 	if err := w.writeFileHeader(); err != nil {
@@ -865,12 +1006,22 @@ func (w *writer) writeRowGroup(rowGroupSchema *Schema, rowGroupSortingColumns []
 	for i, c := range w.columns {
 		w.columnIndex[i] = format.ColumnIndex(c.columnIndex.ColumnIndex())
 
+		w.columnIndex[i].RepetitionLevelHistograms = slices.Clone(c.sizeStatistics.pageRepetitionLevelHistograms)
+		w.columnIndex[i].DefinitionLevelHistograms = slices.Clone(c.sizeStatistics.pageDefinitionLevelHistograms)
+		c.offsetIndex.UnencodedByteArrayDataBytes = c.sizeStatistics.pageUnencodedByteArrayDataBytes
+		c.columnChunk.MetaData.SizeStatistics = c.sizeStatistics.statistics()
+
+		if c.geospatial != nil {
+			c.columnChunk.MetaData.GeospatialStatistics = c.geospatial.statistics()
+		}
+
 		if c.dictionary != nil {
 			c.columnChunk.MetaData.DictionaryPageOffset = w.writer.offset
-			if err := c.writeDictionaryPage(&w.writer, c.dictionary); err != nil {
+			if _, err := w.writer.Write(c.dictionaryPage.Bytes()); err != nil {
 				return 0, fmt.Errorf("writing dictionary page of row group colum %d: %w", i, err)
 			}
 		}
+		c.columnChunk.MetaData.Encoding = c.encodings()
 
 		dataPageOffset := w.writer.offset
 		c.columnChunk.MetaData.DataPageOffset = dataPageOffset
@@ -931,6 +1082,7 @@ func (w *writer) writeRowGroup(rowGroupSchema *Schema, rowGroupSortingColumns []
 		c := &offsetIndex[i]
 		c.PageLocations = make([]format.PageLocation, len(c.PageLocations))
 		copy(c.PageLocations, w.offsetIndex[i].PageLocations)
+		c.UnencodedByteArrayDataBytes = slices.Clone(c.UnencodedByteArrayDataBytes)
 	}
 
 	w.rowGroups = append(w.rowGroups, format.RowGroup{
@@ -976,6 +1128,9 @@ func (w *writer) WriteRows(rows []Row) (int, error) {
 			}
 		}
 
+		if err := w.flushFullPages(); err != nil {
+			return 0, err
+		}
 		return end - start, nil
 	})
 }
@@ -1012,17 +1167,108 @@ func (w *writer) writeRows(numRows int, write func(i, j int) (int, error)) (int,
 			length = maxRowsPerWrite
 		}
 
+		// Bound the number of rows so that column buffers are flushed exactly
+		// when they reach the row count limit of pages.
+		if w.limitPageRows {
+			length = w.pageRowsRemaining(length)
+		}
+
 		n, err := write(written, written+length)
 		written += n
 		w.numRows += int64(n)
 		if err != nil {
 			return written, err
 		}
+
+		if w.maxSize != DefaultMaxRowGroupBytes && w.estimatedRowGroupSize() >= w.maxSize {
+			if err := w.flush(); err != nil {
+				return written, err
+			}
+		}
 	}
 
 	return written, nil
 }
 
+// flushFullPages writes the data pages of columns which reached the size or
+// row count limit of their pages.
+func (w *writer) flushFullPages() error {
+	w.fullColumns = w.fullColumns[:0]
+	for _, c := range w.columns {
+		if err := c.checkDictionarySize(); err != nil {
+			return err
+		}
+		if c.columnBuffer != nil && c.isPageFull() {
+			w.fullColumns = append(w.fullColumns, c)
+		}
+	}
+	return w.forEachColumn(w.fullColumns, func(_ int, c *writerColumn) error {
+		return c.flush()
+	})
+}
+
+// forEachColumn calls fn for each column, passing the index of the column in
+// the list. The function is called concurrently from up to w.concurrency
+// goroutines, and the first error in the order of the columns is returned.
+func (w *writer) forEachColumn(columns []*writerColumn, fn func(int, *writerColumn) error) error {
+	if w.concurrency <= 1 || len(columns) <= 1 {
+		for i, c := range columns {
+			if err := fn(i, c); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	errs := make([]error, len(columns))
+	next := atomic.Int64{}
+	wg := sync.WaitGroup{}
+	for range min(w.concurrency, len(columns)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				i := int(next.Add(1) - 1)
+				if i >= len(columns) {
+					return
+				}
+				errs[i] = fn(i, columns[i])
+			}
+		}()
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// pageRowsRemaining returns the number of rows, at most numRows, which can be
+// written before a column buffer reaches the row count limit of its pages.
+func (w *writer) pageRowsRemaining(numRows int) int {
+	for _, c := range w.columns {
+		remain := c.pageRowCountLimit
+		if c.columnBuffer != nil {
+			remain -= c.columnBuffer.Len()
+		}
+		numRows = min(numRows, max(remain, 1))
+	}
+	return numRows
+}
+
+// estimatedRowGroupSize returns an estimate of the size that the row group
+// being written will have in the file.
+func (w *writer) estimatedRowGroupSize() int64 {
+	size := int64(0)
+	for _, c := range w.columns {
+		size += c.estimatedSize()
+	}
+	return size
+}
+
 // The WriteValues method is intended to work in pair with WritePage to allow
 // programs to target writing values to specific columns of of the writer.
 func (w *writer) WriteValues(values []Value) (numValues int, err error) {
@@ -1120,6 +1366,18 @@ func (wb *writerBuffers) compress(codec compress.Codec) (err error) {
 	return err
 }
 
+// encrypt replaces the page buffer with the encrypted module of the page data,
+// including the repetition and definition levels.
+func (wb *writerBuffers) encrypt(encryptor *columnEncryptor, module byte, page int) (err error) {
+	wb.scratch = append(wb.scratch[:0], wb.repetitions...)
+	wb.scratch = append(wb.scratch, wb.definitions...)
+	wb.scratch = append(wb.scratch, wb.page...)
+	wb.repetitions = wb.repetitions[:0]
+	wb.definitions = wb.definitions[:0]
+	wb.page, err = encryptor.encrypt(wb.page[:0], module, page, wb.scratch)
+	return err
+}
+
 func (wb *writerBuffers) swapPageAndScratchBuffers() {
 	wb.page, wb.scratch = wb.scratch, wb.page[:0]
 }
@@ -1138,28 +1396,66 @@ type writerColumn struct {
 	compression  compress.Codec
 	dictionary   Dictionary
 
+	// The type and encoding of the column in the schema; columnType and
+	// encoding differ from those when the column is dictionary-encoded, or
+	// uses its fallback encoding or an encoding selected by the writer.
+	baseType     Type
+	baseEncoding encoding.Encoding
+
+	// When the size of the dictionary exceeds dictionaryMaxBytes, the column
+	// falls back to writing the rest of the column chunk with the fallback
+	// encoding, or the alternative encoding selected by the writer, or PLAIN
+	// if neither were set.
+	dictionaryMaxBytes  int64
+	dictionaryFallback  bool
+	fallbackEncoding    encoding.Encoding
+	alternativeEncoding encoding.Encoding
+
+	// Set when the encoding of the column is selected based on the size of the
+	// first page of each column chunk.
+	selectEncoding    bool
+	encodingSelected  bool
+	compareCompressed bool
+
 	dataPageType       format.PageType
 	maxRepetitionLevel byte
 	maxDefinitionLevel byte
 
 	buffers *writerBuffers
 
+	// Encoded dictionary page of the column chunk, generated when row groups
+	// are flushed.
+	dictionaryPage bytes.Buffer
+
 	header struct {
 		protocol thrift.CompactProtocol
 		encoder  thrift.Encoder
 	}
 
-	filter          []byte
-	numRows         int64
-	bufferIndex     int32
-	bufferSize      int32
-	writePageStats  bool
-	writePageBounds bool
-	isCompressed    bool
-	encodings       []format.Encoding
+	filter             []byte
+	numRows            int64
+	bufferIndex        int32
+	bufferSize         int32
+	pageRowCountLimit  int
+	writePageStats     bool
+	writePageBounds    bool
+	writePageChecksums bool
+	isCompressed       bool
 
 	columnChunk *format.ColumnChunk
 	offsetIndex *format.OffsetIndex
+
+	// In-memory size of the data pages written to the column chunk, used to
+	// estimate the size of buffered values once written.
+	writtenPageBytes int64
+
+	sizeStatistics sizeStatistics
+
+	// Non-nil for GEOMETRY and GEOGRAPHY columns, which have geospatial
+	// statistics instead of min/max bounds.
+	geospatial *geospatialStatistics
+
+	encryptor *columnEncryptor
 }
 
 func (c *writerColumn) reset() {
@@ -1176,7 +1472,9 @@ func (c *writerColumn) reset() {
 		c.pool.PutBuffer(c.pageBuffer)
 		c.pageBuffer = nil
 	}
+	c.dictionaryPage.Reset()
 	c.numPages = 0
+	c.writtenPageBytes = 0
 	// Bloom filters may change in size between row groups, but we retain the
 	// buffer to avoid reallocating large memory blocks.
 	c.filter = c.filter[:0]
@@ -1191,7 +1489,117 @@ func (c *writerColumn) reset() {
 	c.columnChunk.MetaData.Statistics = format.Statistics{}
 	c.columnChunk.MetaData.EncodingStats = c.columnChunk.MetaData.EncodingStats[:0]
 	c.columnChunk.MetaData.BloomFilterOffset = 0
+	c.columnChunk.MetaData.SizeStatistics = format.SizeStatistics{}
+	c.columnChunk.MetaData.GeospatialStatistics = format.GeospatialStatistics{}
 	c.offsetIndex.PageLocations = c.offsetIndex.PageLocations[:0]
+	c.offsetIndex.UnencodedByteArrayDataBytes = nil
+	c.sizeStatistics.reset()
+	if c.geospatial != nil {
+		c.geospatial.reset()
+	}
+	// Each row group starts with an empty dictionary, so columns that fell
+	// back to their fallback encoding can use dictionary encoding again, and
+	// columns with selected encodings are evaluated again.
+	if c.selectEncoding {
+		c.dictionary = nil
+		c.alternativeEncoding = nil
+		c.encodingSelected = false
+	}
+	c.dictionaryFallback = false
+	c.setEncoding(c.baseEncoding)
+}
+
+// setEncoding configures the column to encode the next data pages with enc.
+//
+// Switching between dictionary and non-dictionary encodings changes the type
+// of values held in column buffers, the column buffer is then discarded; it
+// must have been flushed before calling this method.
+func (c *writerColumn) setEncoding(enc encoding.Encoding) {
+	if c.encoding != nil && isDictionaryEncoding(c.encoding) != isDictionaryEncoding(enc) {
+		c.columnBuffer = nil
+	}
+	c.encoding = enc
+	c.columnType = c.baseType
+
+	if isDictionaryEncoding(enc) {
+		if c.dictionary == nil {
+			c.dictionary = c.newDictionary()
+		}
+		c.columnType = c.dictionary.Type()
+	}
+
+	// Data pages in version 2 can omit compression when dictionary
+	// encoding is employed; only the dictionary page needs to be
+	// compressed, the data pages are encoded with the hybrid
+	// RLE/Bit-Pack encoding which doesn't benefit from an extra
+	// compression layer.
+	c.isCompressed = isCompressed(c.compression) && (c.dataPageType != format.DataPageV2 || !isDictionaryEncoding(enc))
+}
+
+func (c *writerColumn) newDictionary() Dictionary {
+	dictBuffer := c.baseType.NewValues(
+		make([]byte, 0, defaultDictBufferSize),
+		nil,
+	)
+	return c.baseType.NewDictionary(int(c.bufferIndex), 0, dictBuffer)
+}
+
+// encodings returns the list of encodings used in the column chunk, which are
+// the encodings of the levels, the dictionary page, and the data pages.
+func (c *writerColumn) encodings() []format.Encoding {
+	encodings := make([]format.Encoding, 0, 4)
+	if c.maxDefinitionLevel > 0 {
+		encodings = addEncoding(encodings, format.RLE)
+	}
+	for _, stats := range c.columnChunk.MetaData.EncodingStats {
+		encodings = addEncoding(encodings, stats.Encoding)
+	}
+	sortPageEncodings(encodings)
+	return encodings
+}
+
+// checkDictionarySize switches the column to its fallback encoding when the
+// size of its dictionary exceeds the configured limit.
+func (c *writerColumn) checkDictionarySize() error {
+	if c.dictionary == nil || c.dictionaryFallback || c.dictionary.Page().Size() <= c.dictionaryMaxBytes {
+		return nil
+	}
+	// The buffered values are indexes into the dictionary, they must be
+	// written to a dictionary-encoded page before switching encodings.
+	if err := c.flush(); err != nil {
+		return err
+	}
+	fallback := c.fallbackEncoding
+	if fallback == nil {
+		fallback = c.alternativeEncoding
+	}
+	if fallback == nil {
+		fallback = &Plain
+	}
+	c.dictionaryFallback = true
+	c.setEncoding(fallback)
+	c.columnBuffer = c.newColumnBuffer()
+	return nil
+}
+
+// estimatedSize returns an estimate of the size that the column chunk will
+// have in the file, including the pages that were already written, and the
+// values buffered in memory and in the dictionary.
+func (c *writerColumn) estimatedSize() int64 {
+	size := c.columnChunk.MetaData.TotalCompressedSize
+	buffered := int64(0)
+	if c.columnBuffer != nil {
+		buffered += c.columnBuffer.Size()
+	}
+	if c.dictionary != nil {
+		buffered += c.dictionary.Page().Size()
+	}
+	// Assume that the buffered values will be encoded and compressed with the
+	// same ratio as the pages that were already written.
+	if c.writtenPageBytes > 0 {
+		buffered = int64(float64(buffered) * float64(size) / float64(c.writtenPageBytes))
+	}
+	return size + buffered
 }
 
 func (c *writerColumn) totalRowCount() int64 {
@@ -1204,6 +1612,11 @@ func (c *writerColumn) totalRowCount() int64 {
 
 func (c *writerColumn) flush() (err error) {
 	if c.columnBuffer.Len() > 0 {
+		if c.selectEncoding && !c.encodingSelected {
+			if err := c.selectPageEncoding(); err != nil {
+				return err
+			}
+		}
 		defer c.columnBuffer.Reset()
 		_, err = c.writeDataPage(c.columnBuffer.Page())
 	}
@@ -1217,7 +1630,8 @@ func (c *writerColumn) flushFilterPages() (err error) {
 
 	// If there is a dictionary, it contains all the values that we need to
 	// write to the filter.
-	if dict := c.dictionary; dict != nil {
+	dict := c.dictionary
+	if dict != nil && !c.dictionaryFallback {
 		// Need to always attempt to resize the filter, as the writer might
 		// be reused after resetting which would have reset the length of
 		// the filter to 0.
@@ -1226,8 +1640,13 @@ func (c *writerColumn) flushFilterPages() (err error) {
 	}
 
 	// When the filter was already allocated, pages have been written to it as
-	// they were seen by the column writer.
+	// they were seen by the column writer. If the column fell back from
+	// dictionary encoding, the values of dictionary-encoded pages are only
+	// found in the dictionary.
 	if len(c.filter) > 0 {
+		if dict != nil {
+			return c.writePageToFilter(dict.Page())
+		}
 		return nil
 	}
 
@@ -1244,6 +1663,12 @@ func (c *writerColumn) flushFilterPages() (err error) {
 	// systems are getting OOM-Killed.
 	c.resizeBloomFilter(c.columnChunk.MetaData.NumValues)
 
+	if dict != nil {
+		if err := c.writePageToFilter(dict.Page()); err != nil {
+			return err
+		}
+	}
+
 	column := &Column{
 		// Set all the fields required by the decodeDataPage* methods.
 		typ:                c.columnType,
@@ -1278,10 +1703,25 @@ func (c *writerColumn) flushFilterPages() (err error) {
 
 	decoder := thrift.NewDecoder(c.header.protocol.NewReader(pageReader))
 
+	var decryptor *columnDecryptor
+	if c.encryptor != nil {
+		decryptor = c.encryptor.decryptor()
+	}
+
 	for i := 0; i < c.numPages; i++ {
 		header := new(format.PageHeader)
-		if err := decoder.Decode(header); err != nil {
-			return err
+		if decryptor == nil {
+			if err := decoder.Decode(header); err != nil {
+				return err
+			}
+		} else {
+			b, err := decryptor.readModule(pageReader, moduleDataPageHeader, i)
+			if err != nil {
+				return err
+			}
+			if err := thrift.Unmarshal(&c.header.protocol, b, header); err != nil {
+				return err
+			}
 		}
 
 		if pbuf != nil {
@@ -1291,13 +1731,27 @@ func (c *writerColumn) flushFilterPages() (err error) {
 		if _, err := io.ReadFull(pageReader, pbuf.data); err != nil {
 			return err
 		}
+		if decryptor != nil {
+			plaintext := buffers.get(len(pbuf.data))
+			data, err := decryptor.decryptTo(plaintext.data, moduleDataPage, i, pbuf.data)
+			if err != nil {
+				plaintext.unref()
+				return err
+			}
+			pbuf.unref()
+			pbuf = plaintext
+			pbuf.data = data
+		}
 
 		var page Page
 
-		switch header.Type {
-		case format.DataPage:
+		switch {
+		case isDictionaryFormat(pageEncodingOf(header)):
+			// The values of dictionary-encoded pages were written to the
+			// filter from the dictionary.
+		case header.Type == format.DataPage:
 			page, err = column.decodeDataPageV1(DataPageHeaderV1{header.DataPageHeader}, pbuf, nil, header.UncompressedPageSize)
-		case format.DataPageV2:
+		case header.Type == format.DataPageV2:
 			page, err = column.decodeDataPageV2(DataPageHeaderV2{header.DataPageHeaderV2}, pbuf, nil, header.UncompressedPageSize)
 		}
 		if page != nil {
@@ -1341,26 +1795,47 @@ func (c *writerColumn) writeRows(rows []Value) error {
 		// rows are not written individually to the column.
 		c.columnBuffer = c.newColumnBuffer()
 	}
-	if _, err := c.columnBuffer.WriteValues(rows); err != nil {
-		return err
-	}
-	if c.columnBuffer.Size() >= int64(c.bufferSize) {
-		return c.flush()
-	}
-	return nil
+	_, err := c.columnBuffer.WriteValues(rows)
+	return err
+}
+
+// isPageFull returns true if the buffered values reached the size or the row
+// count limit of data pages.
+func (c *writerColumn) isPageFull() bool {
+	return c.columnBuffer.Size() >= int64(c.bufferSize) || c.columnBuffer.Len() >= c.pageRowCountLimit
 }
 
 func (c *writerColumn) WriteValues(values []Value) (numValues int, err error) {
 	if c.columnBuffer == nil {
 		c.columnBuffer = c.newColumnBuffer()
 	}
-	return c.columnBuffer.WriteValues(values)
+	numValues, err = c.columnBuffer.WriteValues(values)
+	if err == nil {
+		err = c.checkDictionarySize()
+	}
+	return numValues, err
 }
 
 func (c *writerColumn) writeBloomFilter(w io.Writer) error {
-	e := thrift.NewEncoder(c.header.protocol.NewWriter(w))
 	h := bloomFilterHeader(c.columnFilter)
 	h.NumBytes = int32(len(c.filter))
+
+	if c.encryptor != nil {
+		b, err := thrift.Marshal(&c.header.protocol, &h)
+		if err != nil {
+			return err
+		}
+		if b, err = c.encryptor.encrypt(nil, moduleBloomFilterHeader, 0, b); err != nil {
+			return err
+		}
+		if b, err = c.encryptor.encrypt(b, moduleBloomFilterBitset, 0, c.filter); err != nil {
+			return err
+		}
+		_, err = w.Write(b)
+		return err
+	}
+
+	e := thrift.NewEncoder(c.header.protocol.NewWriter(w))
 	if err := e.Encode(&h); err != nil {
 		return err
 	}
@@ -1412,6 +1887,17 @@ func (c *writerColumn) writeDataPage(page Page) (int64, error) {
 		}
 	}
 
+	repetitionLevelsByteLength := int32(len(buf.repetitions))
+	definitionLevelsByteLength := int32(len(buf.definitions))
+	if c.encryptor != nil {
+		if c.numPages > math.MaxInt16 {
+			return 0, fmt.Errorf("encrypted column chunks cannot have more than %d pages", math.MaxInt16+1)
+		}
+		if err := buf.encrypt(c.encryptor, moduleDataPage, c.numPages); err != nil {
+			return 0, fmt.Errorf("encrypting parquet data page: %w", err)
+		}
+	}
+
 	statistics := format.Statistics{}
 	if c.writePageStats {
 		statistics = c.makePageStatistics(page)
@@ -1421,7 +1907,9 @@ func (c *writerColumn) writeDataPage(page Page) (int64, error) {
 		Type:                 c.dataPageType,
 		UncompressedPageSize: int32(uncompressedPageSize),
 		CompressedPageSize:   int32(buf.size()),
-		CRC:                  int32(buf.crc32()),
+	}
+	if c.writePageChecksums {
+		pageHeader.CRC = int32(buf.crc32())
 	}
 
 	numRows := page.NumRows()
@@ -1441,15 +1929,14 @@ func (c *writerColumn) writeDataPage(page Page) (int64, error) {
 			NumNulls:                   int32(numNulls),
 			NumRows:                    int32(numRows),
 			Encoding:                   c.encoding.Encoding(),
-			DefinitionLevelsByteLength: int32(len(buf.definitions)),
-			RepetitionLevelsByteLength: int32(len(buf.repetitions)),
+			DefinitionLevelsByteLength: definitionLevelsByteLength,
+			RepetitionLevelsByteLength: repetitionLevelsByteLength,
 			IsCompressed:               &c.isCompressed,
 			Statistics:                 statistics,
 		}
 	}
 
-	buf.header.Reset()
-	if err := c.header.encoder.Encode(pageHeader); err != nil {
+	if err := c.encodePageHeader(pageHeader, moduleDataPageHeader); err != nil {
 		return 0, err
 	}
 
@@ -1498,22 +1985,28 @@ func (c *writerColumn) writeDictionaryPage(output io.Writer, dict Dictionary) (e
 			return fmt.Errorf("copmressing parquet dictionary page: %w", err)
 		}
 	}
+	if c.encryptor != nil {
+		if err := buf.encrypt(c.encryptor, moduleDictionaryPage, 0); err != nil {
+			return fmt.Errorf("encrypting parquet dictionary page: %w", err)
+		}
+	}
 
 	pageHeader := &format.PageHeader{
 		Type:                 format.DictionaryPage,
 		UncompressedPageSize: int32(uncompressedPageSize),
 		CompressedPageSize:   int32(buf.size()),
-		CRC:                  int32(buf.crc32()),
 		DictionaryPageHeader: &format.DictionaryPageHeader{
 			NumValues: int32(dict.Len()),
 			Encoding:  format.Plain,
 			IsSorted:  false,
 		},
 	}
+	if c.writePageChecksums {
+		pageHeader.CRC = int32(buf.crc32())
+	}
 
 	header := &c.buffers.header
-	header.Reset()
-	if err := c.header.encoder.Encode(pageHeader); err != nil {
+	if err := c.encodePageHeader(pageHeader, moduleDictionaryPageHeader); err != nil {
 		return err
 	}
 	if _, err := output.Write(header.Bytes()); err != nil {
@@ -1526,6 +2019,25 @@ func (c *writerColumn) writeDictionaryPage(output io.Writer, dict Dictionary) (e
 	return nil
 }
 
+// encodePageHeader encodes the page header to the header buffer, encrypting it
+// if the column is encrypted.
+func (c *writerColumn) encodePageHeader(pageHeader *format.PageHeader, module byte) error {
+	header := &c.buffers.header
+	header.Reset()
+	if err := c.header.encoder.Encode(pageHeader); err != nil {
+		return err
+	}
+	if c.encryptor != nil {
+		b, err := c.encryptor.encrypt(nil, module, c.numPages, header.Bytes())
+		if err != nil {
+			return fmt.Errorf("encrypting parquet page header: %w", err)
+		}
+		header.Reset()
+		header.Write(b)
+	}
+	return nil
+}
+
 func (w *writerColumn) writePageToFilter(page Page) (err error) {
 	pageType := page.Type()
 	pageData := page.Data()
@@ -1559,6 +2071,9 @@ func (c *writerColumn) writePageTo(size int64, writeTo func(io.Writer) (int64, e
 
 func (c *writerColumn) makePageStatistics(page Page) format.Statistics {
 	numNulls := page.NumNulls()
+	if hasUndefinedOrder(c.columnType) {
+		return format.Statistics{NullCount: numNulls}
+	}
 	minValue, maxValue, _ := page.Bounds()
 	minValueBytes := minValue.Bytes()
 	maxValueBytes := maxValue.Bytes()
@@ -1588,6 +2103,12 @@ func (c *writerColumn) recordPageStats(headerSize int32, header *format.PageHead
 		c.columnIndex.IndexPage(numValues, numNulls, minValue, maxValue)
 		c.columnChunk.MetaData.NumValues += numValues
 		c.columnChunk.MetaData.Statistics.NullCount += numNulls
+		c.writtenPageBytes += page.Size()
+
+		c.sizeStatistics.observePage(page, c.dictionary)
+		if c.geospatial != nil {
+			c.geospatial.observePage(page)
+		}
 
 		if pageHasBounds {
 			var existingMaxValue, existingMinValue Value
@@ -1626,15 +2147,7 @@ func (c *writerColumn) recordPageStats(headerSize int32, header *format.PageHead
 	}
 
 	pageType := header.Type
-	encoding := format.Encoding(-1)
-	switch pageType {
-	case format.DataPageV2:
-		encoding = header.DataPageHeaderV2.Encoding
-	case format.DataPage:
-		encoding = header.DataPageHeader.Encoding
-	case format.DictionaryPage:
-		encoding = header.DictionaryPageHeader.Encoding
-	}
+	encoding := pageEncodingOf(header)
 
 	c.columnChunk.MetaData.TotalUncompressedSize += int64(uncompressedSize)
 	c.columnChunk.MetaData.TotalCompressedSize += int64(compressedSize)
@@ -1645,6 +2158,19 @@ func (c *writerColumn) recordPageStats(headerSize int32, header *format.PageHead
 	})
 }
 
+func pageEncodingOf(header *format.PageHeader) format.Encoding {
+	switch header.Type {
+	case format.DataPageV2:
+		return header.DataPageHeaderV2.Encoding
+	case format.DataPage:
+		return header.DataPageHeader.Encoding
+	case format.DictionaryPage:
+		return header.DictionaryPageHeader.Encoding
+	default:
+		return format.Encoding(-1)
+	}
+}
+
 func addEncoding(encodings []format.Encoding, add format.Encoding) []format.Encoding {
 	for _, enc := range encodings {
 		if enc == add {
@@ -0,0 +1,102 @@
+package parquet_test
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"reflect"
+	"testing"
+
+	"github.com/parquet-go/parquet-go"
+)
+
+type writeConcurrencyRow struct {
+	ID       int64             `parquet:"id"`
+	Name     string            `parquet:"name,zstd"`
+	Category string            `parquet:"category,dict"`
+	Score    *float64          `parquet:"score,optional"`
+	Tags     []string          `parquet:"tags,list"`
+	Attrs    map[string]string `parquet:"attrs"`
+}
+
+func writeConcurrencyRows() []writeConcurrencyRow {
+	rows := make([]writeConcurrencyRow, 5000)
+	for i := range rows {
+		rows[i] = writeConcurrencyRow{
+			ID:       int64(i),
+			Name:     fmt.Sprintf("name-%d", (i*7919)%5000),
+			Category: fmt.Sprintf("category-%d", i%7),
+			Tags:     []string{"a", "b", "c"}[:i%4],
+			Attrs:    map[string]string{"key": fmt.Sprint(i % 11)},
+		}
+		if i%3 != 0 {
+			score := float64(i) / 3
+			rows[i].Score = &score
+		}
+	}
+	return rows
+}
+
+func TestWriteConcurrency(t *testing.T) {
+	rows := writeConcurrencyRows()
+
+	write := func(concurrency int) []byte {
+		return writeTestData(t, rows,
+			parquet.WriteConcurrency(concurrency),
+			parquet.Compression(&parquet.Snappy),
+			parquet.PageBufferSize(4096),
+			parquet.MaxRowsPerRowGroup(2000),
+			parquet.BloomFilters(parquet.SplitBlockFilter(10, "name")),
+			parquet.DataPageStatistics(true),
+		)
+	}
+
+	want := write(1)
+	for _, concurrency := range []int{2, 8} {
+		if got := write(concurrency); !bytes.Equal(want, got) {
+			t.Errorf("files written with concurrency %d differ from the sequential writer", concurrency)
+		}
+	}
+
+	f, err := parquet.OpenFile(bytes.NewReader(want), int64(len(want)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	values := make([]writeConcurrencyRow, len(rows))
+	n, err := parquet.NewGenericReader[writeConcurrencyRow](f).Read(values)
+	if err != nil && err != io.EOF {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(values[:n], rows) {
+		t.Error("wrong rows read from the file")
+	}
+}
+
+func TestWriteConcurrencyWriteRows(t *testing.T) {
+	rows := writeConcurrencyRows()
+	schema := parquet.SchemaOf(writeConcurrencyRow{})
+	buf := make([]parquet.Row, len(rows))
+	for i, row := range rows {
+		buf[i] = schema.Deconstruct(nil, &row)
+	}
+
+	write := func(concurrency int) []byte {
+		b := new(bytes.Buffer)
+		w := parquet.NewWriter(b, schema,
+			parquet.WriteConcurrency(concurrency),
+			parquet.Compression(&parquet.Zstd),
+			parquet.DataPageRowCountLimit(300),
+		)
+		if _, err := w.WriteRows(buf); err != nil {
+			t.Fatal(err)
+		}
+		if err := w.Close(); err != nil {
+			t.Fatal(err)
+		}
+		return b.Bytes()
+	}
+
+	if want, got := write(1), write(4); !bytes.Equal(want, got) {
+		t.Error("files written with concurrency differ from the sequential writer")
+	}
+}
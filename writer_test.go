@@ -8,9 +8,11 @@ import (
 	"os"
 	"os/exec"
 	"reflect"
+	"slices"
 	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/hexops/gotextdiff"
@@ -19,6 +21,9 @@ import (
 
 	"github.com/parquet-go/parquet-go"
 	"github.com/parquet-go/parquet-go/compress"
+	"github.com/parquet-go/parquet-go/deprecated"
+	"github.com/parquet-go/parquet-go/encoding/thrift"
+	"github.com/parquet-go/parquet-go/format"
 )
 
 const (
@@ -1273,3 +1278,614 @@ func TestColumnSkipPageBounds(t *testing.T) {
 		t.Fatalf("wrong max value of row groups in parquet file: want='' got=%s", string(statistics.MaxValue))
 	}
 }
+
+func TestWriterInterval(t *testing.T) {
+	type testStruct struct {
+		Interval deprecated.Duration `parquet:"interval"`
+		Optional deprecated.Duration `parquet:"optional,optional"`
+		ID       int64               `parquet:"id"`
+	}
+
+	rows := make([]testStruct, 100)
+	for i := range rows {
+		rows[i] = testStruct{
+			Interval: deprecated.Duration{Months: uint32(i), Days: uint32(i % 31), Milliseconds: uint32(i * 1000)},
+			ID:       int64(i),
+		}
+		if i%2 == 0 {
+			rows[i].Optional = deprecated.Duration{Days: uint32(i)}
+		}
+	}
+
+	b := new(bytes.Buffer)
+	w := parquet.NewGenericWriter[testStruct](b, parquet.PageBufferSize(256), parquet.DataPageStatistics(true))
+	if _, err := w.Write(rows); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := parquet.OpenFile(bytes.NewReader(b.Bytes()), int64(b.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	schema := f.Metadata().Schema[1]
+	if schema.ConvertedType == nil || *schema.ConvertedType != deprecated.Interval {
+		t.Errorf("wrong converted type: %v", schema.ConvertedType)
+	}
+	if schema.TypeLength == nil || *schema.TypeLength != 12 {
+		t.Errorf("wrong type length: %v", schema.TypeLength)
+	}
+
+	for i, chunk := range f.RowGroups()[0].ColumnChunks()[:2] {
+		statistics := f.Metadata().RowGroups[0].Columns[i].MetaData.Statistics
+		if statistics.MinValue != nil || statistics.MaxValue != nil {
+			t.Errorf("column %d: unexpected statistics min=%v max=%v", i, statistics.MinValue, statistics.MaxValue)
+		}
+		if _, err := chunk.ColumnIndex(); err != parquet.ErrMissingColumnIndex {
+			t.Errorf("column %d: expected ErrMissingColumnIndex but got %v", i, err)
+		}
+
+		metadata := f.Metadata().RowGroups[0].Columns[i].MetaData
+		input := bytes.NewReader(b.Bytes()[metadata.DataPageOffset : metadata.DataPageOffset+metadata.TotalCompressedSize])
+		decoder := thrift.NewDecoder(new(thrift.CompactProtocol).NewReader(input))
+		for input.Len() > 0 {
+			header := new(format.PageHeader)
+			if err := decoder.Decode(header); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := input.Seek(int64(header.CompressedPageSize), io.SeekCurrent); err != nil {
+				t.Fatal(err)
+			}
+			var statistics format.Statistics
+			switch {
+			case header.DataPageHeader != nil:
+				statistics = header.DataPageHeader.Statistics
+			case header.DataPageHeaderV2 != nil:
+				statistics = header.DataPageHeaderV2.Statistics
+			}
+			if statistics.MinValue != nil || statistics.MaxValue != nil || statistics.Min != nil || statistics.Max != nil {
+				t.Errorf("column %d: unexpected page header statistics min=%x max=%x", i, statistics.MinValue, statistics.MaxValue)
+			}
+		}
+
+		pages := chunk.Pages()
+		for {
+			p, err := pages.ReadPage()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if min, max, ok := p.Bounds(); ok && (min.IsNull() || max.IsNull()) {
+				t.Errorf("column %d: invalid page bounds", i)
+			}
+		}
+		pages.Close()
+	}
+
+	if _, err := f.RowGroups()[0].ColumnChunks()[2].ColumnIndex(); err != nil {
+		t.Errorf("column index of ordered column: %v", err)
+	}
+	columnIndexes, _, err := f.ReadPageIndex()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(columnIndexes[2].MinValues) == 0 {
+		t.Error("column index of ordered column is missing")
+	}
+
+	got, err := parquet.Read[testStruct](bytes.NewReader(b.Bytes()), int64(b.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(rows, got) {
+		t.Error("rows mismatch")
+	}
+
+	if v := parquet.IntervalValue(rows[3].Interval).Interval(); v != rows[3].Interval {
+		t.Errorf("interval value mismatch: want=%+v got=%+v", rows[3].Interval, v)
+	}
+}
+
+func TestWriterByteStreamSplit(t *testing.T) {
+	type testStruct struct {
+		Int32     int32     `parquet:"int32,split"`
+		Int64     int64     `parquet:"int64,split"`
+		Uint32    uint32    `parquet:"uint32,split,optional"`
+		Timestamp time.Time `parquet:"timestamp,timestamp(microsecond),split"`
+		Decimal   [16]byte  `parquet:"decimal,decimal(2:38),split"`
+		Array     [3]byte   `parquet:"array,split"`
+		Float     float32   `parquet:"float,split"`
+	}
+
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	rows := make([]testStruct, 1000)
+	for i := range rows {
+		rows[i] = testStruct{
+			Int32:     int32(i * 31),
+			Int64:     int64(i) << 33,
+			Timestamp: now.Add(time.Duration(i) * time.Second),
+			Decimal:   [16]byte{15: byte(i), 14: byte(i >> 8)},
+			Array:     [3]byte{byte(i), byte(i >> 8), 42},
+			Float:     float32(i) / 3,
+		}
+		if i%3 != 0 {
+			rows[i].Uint32 = uint32(i)
+		}
+	}
+
+	b := new(bytes.Buffer)
+	w := parquet.NewGenericWriter[testStruct](b, parquet.PageBufferSize(1024))
+	if _, err := w.Write(rows); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := parquet.OpenFile(bytes.NewReader(b.Bytes()), int64(b.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, column := range f.Metadata().RowGroups[0].Columns {
+		if !slices.Contains(column.MetaData.Encoding, format.ByteStreamSplit) {
+			t.Errorf("column %q is not encoded with BYTE_STREAM_SPLIT: %v", column.MetaData.PathInSchema, column.MetaData.Encoding)
+		}
+	}
+
+	got, err := parquet.Read[testStruct](bytes.NewReader(b.Bytes()), int64(b.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(rows, got) {
+		t.Error("rows mismatch")
+	}
+}
+
+func TestWriterDictionaryFallback(t *testing.T) {
+	type testStruct struct {
+		Name  string `parquet:"name,dict"`
+		Color string `parquet:"color,dict"`
+	}
+
+	colors := []string{"red", "green", "blue"}
+	rows := make([]testStruct, 2000)
+	for i := range rows {
+		rows[i].Name = fmt.Sprintf("name-%06d", i)
+		rows[i].Color = colors[i%len(colors)]
+	}
+
+	for _, test := range []struct {
+		scenario string
+		options  []parquet.WriterOption
+		fallback format.Encoding
+	}{
+		{
+			scenario: "writer limit",
+			options:  []parquet.WriterOption{parquet.DictionaryMaxBytes(1024)},
+			fallback: format.Plain,
+		},
+		{
+			scenario: "column limit",
+			options: []parquet.WriterOption{
+				parquet.ColumnDictionaryLimit(1024, &parquet.DeltaLengthByteArray, "name"),
+			},
+			fallback: format.DeltaLengthByteArray,
+		},
+		{
+			scenario: "data page v1",
+			options: []parquet.WriterOption{
+				parquet.DataPageVersion(1),
+				parquet.DictionaryMaxBytes(1024),
+				parquet.Compression(&parquet.Snappy),
+			},
+			fallback: format.Plain,
+		},
+	} {
+		t.Run(test.scenario, func(t *testing.T) {
+			options := append([]parquet.WriterOption{
+				parquet.PageBufferSize(512),
+				parquet.BloomFilters(parquet.SplitBlockFilter(10, "name")),
+			}, test.options...)
+
+			b := new(bytes.Buffer)
+			w := parquet.NewGenericWriter[testStruct](b, options...)
+			// Write two row groups to verify that the dictionary encoding is
+			// used again after falling back in the first row group.
+			for _, rowGroup := range [][]testStruct{rows[:1500], rows[1500:]} {
+				if _, err := w.Write(rowGroup); err != nil {
+					t.Fatal(err)
+				}
+				if err := w.Flush(); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := w.Close(); err != nil {
+				t.Fatal(err)
+			}
+
+			f, err := parquet.OpenFile(bytes.NewReader(b.Bytes()), int64(b.Len()))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for i, rowGroup := range f.Metadata().RowGroups {
+				name := rowGroup.Columns[0].MetaData
+				if !slices.Contains(name.Encoding, format.RLEDictionary) || !slices.Contains(name.Encoding, test.fallback) {
+					t.Errorf("row group %d: wrong encodings of the name column: %v", i, name.Encoding)
+				}
+
+				numPages := map[format.Encoding]int32{}
+				for _, stats := range name.EncodingStats {
+					if stats.PageType != format.DictionaryPage {
+						numPages[stats.Encoding] += stats.Count
+					}
+				}
+				if numPages[format.RLEDictionary] == 0 || numPages[test.fallback] == 0 {
+					t.Errorf("row group %d: the name column does not have both dictionary-encoded and fallback pages: %+v", i, name.EncodingStats)
+				}
+
+				color := rowGroup.Columns[1].MetaData
+				for _, stats := range color.EncodingStats {
+					if stats.PageType != format.DictionaryPage && stats.Encoding != format.RLEDictionary {
+						t.Errorf("row group %d: the color column must not fall back: %+v", i, color.EncodingStats)
+					}
+				}
+			}
+
+			offset := 0
+			for i, rowGroup := range f.RowGroups() {
+				bloomFilter := rowGroup.ColumnChunks()[0].BloomFilter()
+				if bloomFilter == nil {
+					t.Fatalf("row group %d: missing bloom filter", i)
+				}
+				for _, row := range rows[offset : offset+int(rowGroup.NumRows())] {
+					if ok, err := bloomFilter.Check(parquet.ValueOf(row.Name)); err != nil {
+						t.Fatal(err)
+					} else if !ok {
+						t.Errorf("row group %d: bloom filter does not contain %q", i, row.Name)
+					}
+				}
+				offset += int(rowGroup.NumRows())
+			}
+
+			got, err := parquet.Read[testStruct](bytes.NewReader(b.Bytes()), int64(b.Len()))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(rows, got) {
+				t.Error("rows mismatch")
+			}
+
+			// Copying a row group sizes the bloom filter ahead of time, pages
+			// are then written to the filter as they are produced.
+			copied := new(bytes.Buffer)
+			cw := parquet.NewWriter(copied, options...)
+			if _, err := cw.WriteRowGroup(f.RowGroups()[0]); err != nil {
+				t.Fatal(err)
+			}
+			if err := cw.Close(); err != nil {
+				t.Fatal(err)
+			}
+			cf, err := parquet.OpenFile(bytes.NewReader(copied.Bytes()), int64(copied.Len()))
+			if err != nil {
+				t.Fatal(err)
+			}
+			bloomFilter := cf.RowGroups()[0].ColumnChunks()[0].BloomFilter()
+			for _, row := range rows[:1500] {
+				if ok, err := bloomFilter.Check(parquet.ValueOf(row.Name)); err != nil {
+					t.Fatal(err)
+				} else if !ok {
+					t.Errorf("bloom filter of copied row group does not contain %q", row.Name)
+				}
+			}
+		})
+	}
+}
+
+func TestWriterDictionaryLimitValidation(t *testing.T) {
+	for _, option := range []parquet.WriterOption{
+		parquet.DictionaryMaxBytes(-1),
+		parquet.ColumnDictionaryLimit(0, nil, "name"),
+		parquet.ColumnDictionaryLimit(1024, &parquet.RLEDictionary, "name"),
+	} {
+		if _, err := parquet.NewWriterConfig(option); err == nil {
+			t.Errorf("expected an error for invalid dictionary limit option %+v", option)
+		}
+	}
+}
+
+func TestWriterAdaptiveEncoding(t *testing.T) {
+	type testStruct struct {
+		ID     int64    `parquet:"id"`
+		Label  string   `parquet:"label"`
+		Tags   []string `parquet:"tags,list"`
+		Score  *float64 `parquet:"score,optional"`
+		Plain  int64    `parquet:"plain,plain"`
+		Active bool     `parquet:"active"`
+	}
+
+	labels := []string{"first", "second", "third"}
+	prng := rand.New(rand.NewSource(0))
+	rows := make([]testStruct, 4000)
+	for i := range rows {
+		rows[i] = testStruct{
+			ID:     int64(i),
+			Label:  labels[i%len(labels)],
+			Tags:   labels[:i%len(labels)],
+			Plain:  int64(i),
+			Active: i%2 == 0,
+		}
+		if i%4 != 0 {
+			score := prng.Float64()
+			rows[i].Score = &score
+		}
+		// The second row group has unique labels, the writer should not
+		// select dictionary encoding for this column chunk.
+		if i >= 2000 {
+			rows[i].Label = fmt.Sprintf("%x", prng.Uint64())
+		}
+	}
+
+	for _, mode := range []parquet.AdaptiveEncodingMode{
+		parquet.AdaptiveEncodingSize,
+		parquet.AdaptiveEncodingCompressedSize,
+	} {
+		// Periodic values are compressed so well that PLAIN encoding may beat
+		// dictionary encoding, the expectations only hold for encoded sizes.
+		expectDictionary := mode == parquet.AdaptiveEncodingSize
+
+		b := new(bytes.Buffer)
+		w := parquet.NewGenericWriter[testStruct](b,
+			parquet.AdaptiveEncoding(mode),
+			parquet.Compression(&parquet.Zstd),
+			parquet.PageBufferSize(4096),
+		)
+		for _, rowGroup := range [][]testStruct{rows[:2000], rows[2000:]} {
+			if _, err := w.Write(rowGroup); err != nil {
+				t.Fatal(err)
+			}
+			if err := w.Flush(); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := w.Close(); err != nil {
+			t.Fatal(err)
+		}
+
+		f, err := parquet.OpenFile(bytes.NewReader(b.Bytes()), int64(b.Len()))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		dataPageEncodings := func(rowGroup, column int) []format.Encoding {
+			var encodings []format.Encoding
+			for _, stats := range f.Metadata().RowGroups[rowGroup].Columns[column].MetaData.EncodingStats {
+				if stats.PageType != format.DictionaryPage {
+					encodings = append(encodings, stats.Encoding)
+				}
+			}
+			return encodings
+		}
+
+		for _, test := range []struct {
+			rowGroup   int
+			column     int
+			want       format.Encoding
+			dictionary bool
+		}{
+			{rowGroup: 0, column: 0, want: format.DeltaBinaryPacked},
+			{rowGroup: 0, column: 1, want: format.RLEDictionary, dictionary: true},
+			{rowGroup: 0, column: 2, want: format.RLEDictionary, dictionary: true},
+			{rowGroup: 0, column: 4, want: format.Plain},
+			{rowGroup: 1, column: 4, want: format.Plain},
+		} {
+			if test.dictionary && !expectDictionary {
+				continue
+			}
+			if got := dataPageEncodings(test.rowGroup, test.column); !reflect.DeepEqual(got, []format.Encoding{test.want}) {
+				t.Errorf("%v: row group %d: column %d: want=%v got=%v", mode, test.rowGroup, test.column, test.want, got)
+			}
+		}
+
+		if got := dataPageEncodings(1, 1); slices.Contains(got, format.RLEDictionary) {
+			t.Errorf("%v: unique labels of the second row group must not be dictionary-encoded: %v", mode, got)
+		}
+
+		got, err := parquet.Read[testStruct](bytes.NewReader(b.Bytes()), int64(b.Len()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(rows, got) {
+			t.Errorf("%v: rows mismatch", mode)
+		}
+	}
+}
+
+func TestWriterMaxRowGroupBytes(t *testing.T) {
+	type testStruct struct {
+		ID      int64  `parquet:"id"`
+		Payload string `parquet:"payload"`
+	}
+
+	const maxRowGroupBytes = 64 * 1024
+	prng := rand.New(rand.NewSource(0))
+	rows := make([]testStruct, 5000)
+	for i := range rows {
+		payload := make([]byte, 50)
+		prng.Read(payload)
+		rows[i] = testStruct{ID: int64(i), Payload: fmt.Sprintf("%x", payload)}
+	}
+
+	for _, compression := range []compress.Codec{&parquet.Uncompressed, &parquet.Snappy} {
+		b := new(bytes.Buffer)
+		w := parquet.NewGenericWriter[testStruct](b,
+			parquet.MaxRowGroupBytes(maxRowGroupBytes),
+			parquet.PageBufferSize(8*1024),
+			parquet.Compression(compression),
+		)
+		if _, err := w.Write(rows); err != nil {
+			t.Fatal(err)
+		}
+		if err := w.Close(); err != nil {
+			t.Fatal(err)
+		}
+
+		f, err := parquet.OpenFile(bytes.NewReader(b.Bytes()), int64(b.Len()))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		rowGroups := f.Metadata().RowGroups
+		if len(rowGroups) < 2 {
+			t.Fatalf("%s: expected multiple row groups but got %d", compression, len(rowGroups))
+		}
+		numRows := int64(0)
+		for i, rowGroup := range rowGroups {
+			numRows += rowGroup.NumRows
+			if i == len(rowGroups)-1 {
+				break
+			}
+			if size := rowGroup.TotalCompressedSize; size < maxRowGroupBytes/2 || size > 3*maxRowGroupBytes/2 {
+				t.Errorf("%s: row group %d: size is too far from the target: %d", compression, i, size)
+			}
+		}
+		if numRows != int64(len(rows)) {
+			t.Errorf("%s: wrong number of rows: want=%d got=%d", compression, len(rows), numRows)
+		}
+	}
+}
+
+func TestWriterDataPageRowCountLimit(t *testing.T) {
+	type testStruct struct {
+		Flag bool     `parquet:"flag"`
+		Name string   `parquet:"name"`
+		Tags []string `parquet:"tags,list"`
+		Blob string   `parquet:"blob"`
+	}
+
+	rows := make([]testStruct, 1000)
+	for i := range rows {
+		rows[i] = testStruct{
+			Flag: i%3 == 0,
+			Name: fmt.Sprintf("name-%d", i),
+			Tags: []string{"a", "b", "c", "d"}[:i%5],
+			Blob: strings.Repeat("x", 100),
+		}
+	}
+
+	b := new(bytes.Buffer)
+	w := parquet.NewGenericWriter[testStruct](b,
+		parquet.DataPageRowCountLimit(100),
+		parquet.ColumnPageLimits(0, 30, "name"),
+		parquet.ColumnPageLimits(1024, 0, "blob"),
+	)
+	if _, err := w.Write(rows); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := parquet.OpenFile(bytes.NewReader(b.Bytes()), int64(b.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pageRowCounts := func(chunk parquet.ColumnChunk) []int64 {
+		offsetIndex, err := chunk.OffsetIndex()
+		if err != nil {
+			t.Fatal(err)
+		}
+		counts := make([]int64, offsetIndex.NumPages())
+		for i := range counts {
+			next := f.NumRows()
+			if i+1 < len(counts) {
+				next = offsetIndex.FirstRowIndex(i + 1)
+			}
+			counts[i] = next - offsetIndex.FirstRowIndex(i)
+		}
+		return counts
+	}
+
+	chunks := f.RowGroups()[0].ColumnChunks()
+	for _, test := range []struct {
+		column int
+		limit  int64
+	}{
+		{column: 0, limit: 100},
+		{column: 1, limit: 30},
+		{column: 2, limit: 100},
+		{column: 3, limit: 100},
+	} {
+		counts := pageRowCounts(chunks[test.column])
+		for i, count := range counts {
+			if count > test.limit {
+				t.Errorf("column %d: page %d has too many rows: %d > %d", test.column, i, count, test.limit)
+			}
+			// Pages are only cut at the row count limit, unless they reach
+			// the page buffer size first.
+			if count < test.limit && i < len(counts)-1 && test.column != 3 {
+				t.Errorf("column %d: page %d has fewer rows than the limit: %d < %d", test.column, i, count, test.limit)
+			}
+		}
+	}
+
+	// The blob column reaches its page buffer size before the row count limit.
+	if counts := pageRowCounts(chunks[3]); len(counts) <= 10 {
+		t.Errorf("the page buffer size override of the blob column was not applied: %d pages", len(counts))
+	}
+
+	got, err := parquet.Read[testStruct](bytes.NewReader(b.Bytes()), int64(b.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(rows, got) {
+		t.Error("rows mismatch")
+	}
+}
+
+func TestWriterOptionalByteSlice(t *testing.T) {
+	type testStruct struct {
+		ID   int64  `parquet:"id"`
+		Data []byte `parquet:"data,optional"`
+	}
+
+	rows := []testStruct{
+		{ID: 0, Data: []byte("hello")},
+		{ID: 1},
+		{ID: 2, Data: []byte{}},
+		{ID: 3, Data: []byte("world")},
+	}
+
+	b := new(bytes.Buffer)
+	w := parquet.NewGenericWriter[testStruct](b)
+	if _, err := w.Write(rows); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := parquet.OpenFile(bytes.NewReader(b.Bytes()), int64(b.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if nulls := f.Metadata().RowGroups[0].Columns[1].MetaData.Statistics.NullCount; nulls != 1 {
+		t.Errorf("wrong number of nulls: want=1 got=%d", nulls)
+	}
+
+	got := make([]testStruct, len(rows))
+	if n, err := parquet.NewGenericReader[testStruct](f).Read(got); n != len(rows) {
+		t.Fatalf("wrong number of rows read: %d/%d: %v", n, len(rows), err)
+	}
+	for i := range rows {
+		if got[i].ID != rows[i].ID || !bytes.Equal(got[i].Data, rows[i].Data) || (got[i].Data == nil) != (rows[i].Data == nil) {
+			t.Errorf("row %d mismatch: want=%q got=%q", i, rows[i].Data, got[i].Data)
+		}
+	}
+}